@@ -10,7 +10,7 @@ func getFeatureCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "feature",
 		Usage: "managing the works in developing.",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.BoolFlag{
 				Name:        "force-create-mr",
 				Value:       false,
@@ -34,7 +34,25 @@ func getFeatureCommand() *cli.Command {
 				Usage:    "auto merge request when feature is done",
 				Required: false,
 			},
-		},
+			&cli.BoolFlag{
+				Name: "resolve-conflict",
+				Usage: "resolve conflicts predicted by debug, test and release in a conflict-resolve branch " +
+					"without asking, rather than opening the merge request",
+				Required: false,
+			},
+			&cli.StringSliceFlag{
+				Name: "projects",
+				Usage: "operate the feature in several projects, input `projectNames` separated by comma, " +
+					"directories are located by workspace file or sibling directories of current project",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name: "workspace",
+				Usage: "operate the feature in all projects listed in workspace file `path/to/workspace.toml`, " +
+					"default is .gitlab-flow/workspace.toml of current project while --projects is set",
+				Required: false,
+			},
+		}, participantFlags()...),
 		Subcommands: getFeatureSubCommands(),
 	}
 }
@@ -44,7 +62,7 @@ func getHotfixCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "hotfix",
 		Usage: "managing the works in hotfix.",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.BoolFlag{
 				Name:        "force-create-mr",
 				Value:       false,
@@ -52,11 +70,40 @@ func getHotfixCommand() *cli.Command {
 				DefaultText: "false",
 				Required:    false,
 			},
-		},
+		}, participantFlags()...),
 		Subcommands: getHotfixSubCommands(),
 	}
 }
 
+// gitlab-flow release [command options] -r --release-branch-name
+func getReleaseCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "release",
+		Usage: "managing the release branch which bundles features.",
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:        "force-create-mr",
+				Value:       false,
+				Usage:       "force to create Merge Request",
+				DefaultText: "false",
+				Required:    false,
+			},
+			&cli.StringFlag{
+				Name:     "release-branch-name",
+				Aliases:  []string{"r"},
+				Usage:    "input the `releaseBranchName`",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "auto-merge",
+				Usage:    "auto merge request when it's created",
+				Required: false,
+			},
+		}, participantFlags()...),
+		Subcommands: getReleaseSubCommands(),
+	}
+}
+
 // getDashCommand
 func getDashCommand() *cli.Command {
 	return &cli.Command{
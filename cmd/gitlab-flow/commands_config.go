@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strings"
 
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/terminal"
@@ -14,7 +15,9 @@ import (
 
 	"github.com/yeqown/gitlab-flow/internal/conf"
 	gitlabop "github.com/yeqown/gitlab-flow/internal/gitlab-operator"
+	"github.com/yeqown/gitlab-flow/internal/render"
 	"github.com/yeqown/gitlab-flow/internal/types"
+	"github.com/yeqown/gitlab-flow/pkg"
 )
 
 func getConfigSubCommands() []*cli.Command {
@@ -56,10 +59,10 @@ func getConfigInitCommand() *cli.Command {
 			switch configType {
 			case types.ConfigType_Project:
 				configHolder = ch.Config(types.ConfigType_Project)
-				err = surveyProjectConfig(configHolder.AsProject())
+				err = surveyProjectConfig(configHolder.AsProject(), flags.NonInteractive)
 			default:
 				configHolder = conf.Default()
-				err = surveyConfig(configHolder.AsGlobal())
+				err = surveyConfig(configHolder.AsGlobal(), flags.NonInteractive)
 			}
 			if err != nil {
 				log.Warnf("failed to survey config: %v", err)
@@ -72,19 +75,13 @@ func getConfigInitCommand() *cli.Command {
 			}
 
 			if configType == types.ConfigType_Global {
-				cfg := configHolder.AsGlobal()
-				support := gitlabop.NewOAuth2Support(gitlabop.NewOAuth2ConfigFrom(cfg))
-				if err = support.Enter(""); err != nil {
-					log.
-						WithFields(log.Fields{"config": cfg}).
-						Error("gitlab-flow initialize.oauth failed:", err)
+				if err = authorizeConfig(configHolder.AsGlobal(), flags.NonInteractive); err != nil {
 					return err
 				}
-				cfg.OAuth2.AccessToken, cfg.OAuth2.RefreshToken = support.Load()
 			}
 
 			target := ch.SaveTo(configType)
-			if !surveySaveChoice(target) {
+			if !surveySaveChoice(target, flags.NonInteractive) {
 				log.Info("Aborted to save configuration")
 				return nil
 			}
@@ -116,13 +113,7 @@ func getConfigShowCommand() *cli.Command {
 				DefaultText: "true",
 				Value:       true,
 			},
-			&cli.StringFlag{
-				Name:        "output",
-				Aliases:     []string{"o"},
-				Usage:       "output configuration to file, support json/toml format. not support yet",
-				DefaultText: "none",
-				Value:       "none",
-			},
+			outputFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			configType := explainConfigFlags(c)
@@ -140,93 +131,152 @@ func getConfigShowCommand() *cli.Command {
 				return errors.New("could not get configuration")
 			}
 
-			// Branch settings
-			table := tablewriter.NewWriter(os.Stdout)
-			table.SetHeader([]string{"Module", "Setting", "Value"})
-			table.SetAutoMergeCells(true)
-			table.SetColumnColor(
-				tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiGreenColor},
-				tablewriter.Colors{tablewriter.Bold, tablewriter.FgHiBlackColor},
-				tablewriter.Colors{tablewriter.Bold, tablewriter.FgWhiteColor},
-			)
-
-			switch configHolder.Type() {
-			case types.ConfigType_Project:
-				cfg := configHolder.AsProject()
-				data := fillConfigRenderData(
-					cfg.Branch,
-					nil,
-					"",
-					"",
-					cfg.DebugMode,
-					cfg.OpenBrowser,
-					cfg.ProjectName,
-				)
-				table.AppendBulk(data)
-			case types.ConfigType_Global:
-				cfg := configHolder.AsGlobal()
-				data := fillConfigRenderData(
-					cfg.Branch,
-					cfg.OAuth2,
-					cfg.GitlabAPIURL,
-					cfg.GitlabHost,
-					&cfg.DebugMode,
-					&cfg.OpenBrowser,
-					"",
-				)
-				table.AppendBulk(data)
-			}
+			data := fillConfigRenderData(configHolder)
+			return printView(c, newConfigView(configHolder.Type(), data))
+		},
+	}
+}
 
-			table.Render()
+// configView is the view of configuration settings.
+type configView struct {
+	Type     types.ConfigType `json:"type"`
+	Settings []*configSetting `json:"settings"`
+}
 
-			return nil
-		},
+type configSetting struct {
+	Module  string `json:"module"`
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+}
+
+// newConfigView creates configView from rows of fillConfigRenderData.
+func newConfigView(typ types.ConfigType, data [][]string) *configView {
+	v := &configView{Type: typ, Settings: make([]*configSetting, 0, len(data))}
+	for _, row := range data {
+		v.Settings = append(v.Settings, &configSetting{Module: row[0], Setting: row[1], Value: row[2]})
 	}
+
+	return v
 }
 
-func fillConfigRenderData(
-	branch *types.BranchSetting,
-	oauth2 *types.OAuth,
-	gitlabAPIURL, gitlabHost string,
-	debug, openBrowser *bool,
-	projectName string,
-) (data [][]string) {
-	data = make([][]string, 0, 10)
-
-	if projectName != "" {
-		data = append(data, []string{"Project", "Name", projectName})
+var _configTblHeader = []string{"Module", "Setting", "Value"}
+
+func (v *configView) Sections() []*render.Section {
+	rows := make([][]string, 0, len(v.Settings))
+	for _, s := range v.Settings {
+		rows = append(rows, []string{s.Module, s.Setting, s.Value})
 	}
 
-	if branch != nil {
-		data = append(data, []string{"Branch Settings", "Master", branch.Master.String()})
-		data = append(data, []string{"Branch Settings", "Dev", branch.Dev.String()})
-		data = append(data, []string{"Branch Settings", "Test", branch.Test.String()})
-		data = append(data, []string{"Branch Settings", "Feature Branch Prefix", branch.FeatureBranchPrefix})
-		data = append(data, []string{"Branch Settings", "Hotfix Branch Prefix", branch.HotfixBranchPrefix})
-		data = append(data, []string{"Branch Settings", "Conflict Branch Prefix", branch.ConflictResolveBranchPrefix})
+	return []*render.Section{
+		{
+			Header: _configTblHeader,
+			Rows:   rows,
+			ColumnColors: []tablewriter.Colors{
+				{tablewriter.Bold, tablewriter.FgHiGreenColor},
+				{tablewriter.Bold, tablewriter.FgHiBlackColor},
+				{tablewriter.Bold, tablewriter.FgWhiteColor},
+			},
+			MergeCells: true,
+		},
 	}
+}
 
-	if oauth2 != nil {
-		data = append(data, []string{"Gitlab OAuth2", "Callback Host", oauth2.CallbackHost})
-		data = append(data, []string{"Gitlab OAuth2", "Access Token", oauth2.AccessToken})
-		data = append(data, []string{"Gitlab OAuth2", "Refresh Token", oauth2.RefreshToken})
+// fillConfigRenderData renders settings of global or project configuration into rows of
+// (section, setting, value), settings which are not set in the configuration are skipped.
+func fillConfigRenderData(configHolder types.ConfigHolder) [][]string {
+	rows := make(configRows, 0, 32)
+
+	switch configHolder.Type() {
+	case types.ConfigType_Project:
+		cfg := configHolder.AsProject()
+		rows.addOptional("Project", "Name", cfg.ProjectName)
+		rows.addBranch(cfg.Branch)
+		rows.addBool("Flags", "Debug", cfg.DebugMode)
+		rows.addBool("Flags", "Auto Open Browser", cfg.OpenBrowser)
+		rows.addBool("Flags", "Semver Mode", cfg.Semver)
+		rows.addMerge(cfg.Merge)
+		rows.addOptional("Git", "Backend", cfg.GitBackend)
+		rows.addOptional("Git", "Remote", cfg.GitRemote)
+		rows.addBool("Git", "Auto Stash", cfg.AutoStash)
+		rows.addParticipants(cfg.Participants)
+	case types.ConfigType_Global:
+		cfg := configHolder.AsGlobal()
+		rows.addBranch(cfg.Branch)
+		if cfg.OAuth2 != nil {
+			rows.add("Gitlab OAuth2", "Callback Host", cfg.OAuth2.CallbackHost)
+			rows.add("Gitlab OAuth2", "Access Token", cfg.OAuth2.AccessToken)
+			rows.add("Gitlab OAuth2", "Refresh Token", cfg.OAuth2.RefreshToken)
+		}
+		rows.addOptional("Gitlab", "API Endpoint", cfg.GitlabAPIURL)
+		rows.addOptional("Gitlab", "Host", cfg.GitlabHost)
+		rows.addBool("Flags", "Debug", &cfg.DebugMode)
+		rows.addBool("Flags", "Auto Open Browser", &cfg.OpenBrowser)
+		rows.addBool("Flags", "Semver Mode", &cfg.Semver)
+		rows.addMerge(cfg.Merge)
+		rows.addOptional("Git", "Backend", cfg.GitBackend)
+		rows.addOptional("Git", "Remote", cfg.GitRemote)
+		rows.addBool("Git", "Auto Stash", &cfg.AutoStash)
 	}
 
-	if gitlabAPIURL != "" {
-		data = append(data, []string{"Gitlab", "API Endpoint", gitlabAPIURL})
+	return rows
+}
+
+// configRows are rows of (section, setting, value) to render configuration.
+type configRows [][]string
+
+func (r *configRows) add(section, setting, value string) {
+	*r = append(*r, []string{section, setting, value})
+}
+
+// addOptional adds the setting only if it's set.
+func (r *configRows) addOptional(section, setting, value string) {
+	if value != "" {
+		r.add(section, setting, value)
 	}
-	if gitlabHost != "" {
-		data = append(data, []string{"Gitlab", "Host", gitlabHost})
+}
+
+// addBool adds the setting only if it's set.
+func (r *configRows) addBool(section, setting string, value *bool) {
+	if value != nil {
+		r.add(section, setting, fmt.Sprintf("%v", *value))
 	}
+}
 
-	if debug != nil {
-		data = append(data, []string{"Flags", "Debug", fmt.Sprintf("%v", *debug)})
+func (r *configRows) addBranch(branch *types.BranchSetting) {
+	if branch == nil {
+		return
 	}
-	if openBrowser != nil {
-		data = append(data, []string{"Flags", "Auto Open Browser", fmt.Sprintf("%v", *openBrowser)})
+
+	r.add("Branch Settings", "Master", branch.Master.String())
+	r.add("Branch Settings", "Dev", branch.Dev.String())
+	r.add("Branch Settings", "Test", branch.Test.String())
+	r.add("Branch Settings", "Feature Branch Prefix", branch.FeatureBranchPrefix)
+	r.add("Branch Settings", "Hotfix Branch Prefix", branch.HotfixBranchPrefix)
+	r.add("Branch Settings", "Conflict Branch Prefix", branch.ConflictResolveBranchPrefix)
+	r.add("Branch Settings", "Release Branch Prefix", branch.ReleaseBranchPrefix)
+	r.add("Branch Settings", "Release Base", branch.ReleaseBase.String())
+}
+
+func (r *configRows) addMerge(merge *types.MergeSetting) {
+	if merge == nil {
+		return
 	}
 
-	return data
+	r.add("Merge Settings", "Squash", fmt.Sprintf("%v", merge.Squash))
+	r.add("Merge Settings", "Remove Source Branch", fmt.Sprintf("%v", merge.RemoveSourceBranch))
+}
+
+func (r *configRows) addParticipants(participants *types.ParticipantSetting) {
+	for _, step := range []types.FlowStep{
+		types.FlowStepIssue, types.FlowStepDebug, types.FlowStepTest, types.FlowStepRelease, types.FlowStepHotfix,
+	} {
+		p := participants.Of(step)
+		if p == nil {
+			continue
+		}
+		r.add("Participants", string(step), fmt.Sprintf("assignees: %s; reviewers: %s; labels: %s",
+			strings.Join(p.Assignees, ","), strings.Join(p.Reviewers, ","), strings.Join(p.Labels, ",")))
+	}
 }
 
 // getConfigEditCommand edit current configuration in the terminal, interact with user to get configuration.
@@ -249,9 +299,9 @@ func getConfigEditCommand() *cli.Command {
 
 			switch configType {
 			case types.ConfigType_Project:
-				err = surveyProjectConfig(configHolder.AsProject())
+				err = surveyProjectConfig(configHolder.AsProject(), flags.NonInteractive)
 			default:
-				err = surveyConfig(configHolder.AsGlobal())
+				err = surveyConfig(configHolder.AsGlobal(), flags.NonInteractive)
 			}
 			if err != nil {
 				log.Warnf("failed to survey config: %v", err)
@@ -270,7 +320,7 @@ func getConfigEditCommand() *cli.Command {
 			}
 
 			target := helper.SaveTo(configType)
-			if !surveySaveChoice(target) {
+			if !surveySaveChoice(target, flags.NonInteractive) {
 				log.Info("Aborted to save configuration")
 				return nil
 			}
@@ -286,6 +336,43 @@ func getConfigEditCommand() *cli.Command {
 	}
 }
 
+// authorizeConfig authorizes gitlab-flow by OAuth2 and fills tokens into cfg. In non-interactive mode,
+// the browser authorization is skipped, tokens are taken from environment variables
+// GITLAB_FLOW_ACCESS_TOKEN and GITLAB_FLOW_REFRESH_TOKEN.
+func authorizeConfig(cfg *types.Config, nonInteractive bool) error {
+	if nonInteractive {
+		accessToken := os.Getenv(pkg.EnvName(_envPrefix, "accessToken"))
+		refreshToken := os.Getenv(pkg.EnvName(_envPrefix, "refreshToken"))
+		if refreshToken == "" {
+			if accessToken == "" {
+				return errors.Errorf("OAuth2 authorization could not be done in non-interactive mode, "+
+					"please set %s or %s", pkg.EnvName(_envPrefix, "accessToken"), pkg.EnvName(_envPrefix, "refreshToken"))
+			}
+			cfg.OAuth2.AccessToken = accessToken
+			return nil
+		}
+
+		// refresh token has expired should fail rather than waiting for authorization by browser.
+		support := gitlabop.NewOAuth2Support(gitlabop.NewOAuth2ConfigFrom(cfg))
+		var err error
+		if cfg.OAuth2.AccessToken, cfg.OAuth2.RefreshToken, err = support.Refresh(refreshToken); err != nil {
+			return errors.Wrapf(err, "could not refresh access token by %s", pkg.EnvName(_envPrefix, "refreshToken"))
+		}
+		return nil
+	}
+
+	support := gitlabop.NewOAuth2Support(gitlabop.NewOAuth2ConfigFrom(cfg))
+	if err := support.Enter(""); err != nil {
+		log.
+			WithFields(log.Fields{"config": cfg}).
+			Error("gitlab-flow initialize.oauth failed:", err)
+		return err
+	}
+	cfg.OAuth2.AccessToken, cfg.OAuth2.RefreshToken = support.Load()
+
+	return nil
+}
+
 func buildGitlabQuestions(cfg *types.Config) []*survey.Question {
 	return []*survey.Question{
 		{
@@ -328,7 +415,7 @@ func buildGitlabQuestions(cfg *types.Config) []*survey.Question {
 	}
 }
 
-func buildFlagsQuestions(debugMode, openBrowser bool, withOAuthMode bool) []*survey.Question {
+func buildFlagsQuestions(debugMode, openBrowser, semver bool, withOAuthMode bool) []*survey.Question {
 	qs := []*survey.Question{
 		{
 			Name: "debugMode",
@@ -348,6 +435,15 @@ func buildFlagsQuestions(debugMode, openBrowser bool, withOAuthMode bool) []*sur
 			Validate:  nil,
 			Transform: nil,
 		},
+		{
+			Name: "semver",
+			Prompt: &survey.Confirm{
+				Message: "Would you like to use semantic version as milestone title (e.g. v1.2.0)",
+				Default: semver,
+			},
+			Validate:  nil,
+			Transform: nil,
+		},
 	}
 
 	if withOAuthMode {
@@ -446,12 +542,41 @@ func buildBranchQuestions(cfg *types.BranchSetting) []*survey.Question {
 			Validate:  survey.Required,
 			Transform: nil,
 		},
+		{
+			Name: "releaseBranchPrefix",
+			Prompt: &survey.Input{
+				Message: "Input your release branch prefix",
+				Default: defaultIfEmpty(cfg.ReleaseBranchPrefix, types.ReleaseBranchPrefix),
+			},
+			Validate:  survey.Required,
+			Transform: nil,
+		},
+		{
+			Name: "releaseBase",
+			Prompt: &survey.Input{
+				Message: "Input the branch which release branch checkout from",
+				Default: defaultIfEmpty(cfg.ReleaseBase.String(), cfg.Test.String()),
+			},
+			Validate:  survey.Required,
+			Transform: nil,
+		},
+	}
+}
+
+// defaultIfEmpty returns def if s is empty, it's used to fill default value of optional settings
+// those could be missing in the old configuration.
+func defaultIfEmpty(s, def string) string {
+	if s == "" {
+		return def
 	}
+
+	return s
 }
 
-// surveyConfig initialize configuration in an interactive session.
+// surveyConfig initialize configuration in an interactive session, or from environment
+// variables in non-interactive mode.
 // DONE(@yeqown): init flow2 in survey method.
-func surveyConfig(cfg *types.Config) error {
+func surveyConfig(cfg *types.Config, nonInteractive bool) error {
 	log.
 		WithField("config", cfg).
 		Debug("surveyConfig called")
@@ -462,11 +587,11 @@ func surveyConfig(cfg *types.Config) error {
 
 	questions := make([]*survey.Question, 0, 8)
 	questions = append(questions, buildGitlabQuestions(cfg)...)
-	questions = append(questions, buildFlagsQuestions(cfg.DebugMode, cfg.OpenBrowser, true)...)
+	questions = append(questions, buildFlagsQuestions(cfg.DebugMode, cfg.OpenBrowser, cfg.Semver, true)...)
 	questions = append(questions, buildBranchQuestions(cfg.Branch)...)
 
 	ans := new(configSurveyAns)
-	if err := survey.Ask(questions, ans); err != nil {
+	if err := pkg.AskOrLoad(questions, ans, nonInteractive, _envPrefix); err != nil {
 		if errors.Is(err, terminal.InterruptErr) {
 			log.Warnf("user canceled the operation")
 		}
@@ -498,6 +623,7 @@ func surveyConfig(cfg *types.Config) error {
 
 	cfg.DebugMode = ans.DebugMode
 	cfg.OpenBrowser = ans.OpenBrowser
+	cfg.Semver = ans.Semver
 
 	cfg.Branch.Master = types.BranchTyp(ans.MasterBranch)
 	cfg.Branch.Dev = types.BranchTyp(ans.DevBranch)
@@ -506,6 +632,8 @@ func surveyConfig(cfg *types.Config) error {
 	cfg.Branch.HotfixBranchPrefix = ans.HotfixBranchPrefix
 	cfg.Branch.ConflictResolveBranchPrefix = ans.ConflictResolveBranchPrefix
 	cfg.Branch.IssueBranchPrefix = ans.IssueBranchPrefix
+	cfg.Branch.ReleaseBranchPrefix = ans.ReleaseBranchPrefix
+	cfg.Branch.ReleaseBase = types.BranchTyp(ans.ReleaseBase)
 
 	return err
 }
@@ -516,6 +644,7 @@ type configSurveyAns struct {
 
 	OpenBrowser bool
 	DebugMode   bool
+	Semver      bool
 	OAuthMode   string
 	AppID       string
 	AppSecret   string
@@ -527,16 +656,18 @@ type configSurveyAns struct {
 	HotfixBranchPrefix          string
 	ConflictResolveBranchPrefix string
 	IssueBranchPrefix           string
+	ReleaseBranchPrefix         string
+	ReleaseBase                 string
 }
 
-func surveyProjectConfig(cfg *types.ProjectConfig) error {
+func surveyProjectConfig(cfg *types.ProjectConfig, nonInteractive bool) error {
 	questions := make([]*survey.Question, 0, 4)
 	questions = append(questions, buildProjectNameQuestions(cfg.ProjectName)...)
 	questions = append(questions, buildBranchQuestions(cfg.Branch)...)
-	questions = append(questions, buildFlagsQuestions(*cfg.DebugMode, *cfg.OpenBrowser, false)...)
+	questions = append(questions, buildFlagsQuestions(*cfg.DebugMode, *cfg.OpenBrowser, *cfg.Semver, false)...)
 
 	ans := new(projectSurveyAns)
-	if err := survey.Ask(questions, ans); err != nil {
+	if err := pkg.AskOrLoad(questions, ans, nonInteractive, _envPrefix); err != nil {
 		if errors.Is(err, terminal.InterruptErr) {
 			log.Warnf("user canceled the operation")
 		}
@@ -548,6 +679,7 @@ func surveyProjectConfig(cfg *types.ProjectConfig) error {
 
 	cfg.DebugMode = &ans.DebugMode
 	cfg.OpenBrowser = &ans.OpenBrowser
+	cfg.Semver = &ans.Semver
 
 	cfg.Branch.Master = types.BranchTyp(ans.MasterBranch)
 	cfg.Branch.Dev = types.BranchTyp(ans.DevBranch)
@@ -556,6 +688,8 @@ func surveyProjectConfig(cfg *types.ProjectConfig) error {
 	cfg.Branch.HotfixBranchPrefix = ans.HotfixBranchPrefix
 	cfg.Branch.ConflictResolveBranchPrefix = ans.ConflictResolveBranchPrefix
 	cfg.Branch.IssueBranchPrefix = ans.IssueBranchPrefix
+	cfg.Branch.ReleaseBranchPrefix = ans.ReleaseBranchPrefix
+	cfg.Branch.ReleaseBase = types.BranchTyp(ans.ReleaseBase)
 
 	return nil
 }
@@ -565,6 +699,7 @@ type projectSurveyAns struct {
 
 	OpenBrowser bool
 	DebugMode   bool
+	Semver      bool
 
 	MasterBranch                string
 	DevBranch                   string
@@ -573,9 +708,15 @@ type projectSurveyAns struct {
 	HotfixBranchPrefix          string
 	ConflictResolveBranchPrefix string
 	IssueBranchPrefix           string
+	ReleaseBranchPrefix         string
+	ReleaseBase                 string
 }
 
-func surveySaveChoice(target string) bool {
+func surveySaveChoice(target string, nonInteractive bool) bool {
+	if nonInteractive {
+		return true
+	}
+
 	ans := new(bool)
 	if err := survey.AskOne(&survey.Confirm{
 		Message: "The configuration would saved to " + target + ", continue?",
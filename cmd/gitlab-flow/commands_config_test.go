@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+func Test_fillConfigRenderData(t *testing.T) {
+	autoStash := true
+	data := fillConfigRenderData(&types.ProjectConfig{
+		ProjectName: "gitlab-flow",
+		GitRemote:   "upstream",
+		AutoStash:   &autoStash,
+		Participants: &types.ParticipantSetting{
+			Test: &types.Participants{Reviewers: []string{"bob"}},
+		},
+	})
+	assert.Equal(t, [][]string{
+		{"Project", "Name", "gitlab-flow"},
+		{"Git", "Remote", "upstream"},
+		{"Git", "Auto Stash", "true"},
+		{"Participants", string(types.FlowStepTest), "assignees: ; reviewers: bob; labels: "},
+	}, data)
+
+	data = fillConfigRenderData(&types.Config{
+		OAuth2:       &types.OAuth{CallbackHost: "localhost:2333"},
+		GitlabAPIURL: "https://gitlab.example.com/api/v4/",
+	})
+	assert.Equal(t, [][]string{
+		{"Gitlab OAuth2", "Callback Host", "localhost:2333"},
+		{"Gitlab OAuth2", "Access Token", ""},
+		{"Gitlab OAuth2", "Refresh Token", ""},
+		{"Gitlab", "API Endpoint", "https://gitlab.example.com/api/v4/"},
+		{"Flags", "Debug", "false"},
+		{"Flags", "Auto Open Browser", "false"},
+		{"Flags", "Semver Mode", "false"},
+		{"Git", "Auto Stash", "false"},
+	}, data)
+}
@@ -1,12 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
 
+	"github.com/pkg/errors"
 	cli "github.com/urfave/cli/v2"
+	"github.com/yeqown/log"
 
+	"github.com/yeqown/gitlab-flow/internal"
+	gitop "github.com/yeqown/gitlab-flow/internal/git-operator"
+	"github.com/yeqown/gitlab-flow/internal/repository"
+	"github.com/yeqown/gitlab-flow/internal/tui"
 	"github.com/yeqown/gitlab-flow/internal/types"
+	"github.com/yeqown/gitlab-flow/internal/web"
+	"github.com/yeqown/gitlab-flow/pkg"
 )
 
 func getDashSubCommands() cli.Commands {
@@ -14,6 +27,11 @@ func getDashSubCommands() cli.Commands {
 		getDashFeatureDetailSubCommand(),
 		getDashProjectDetailSubCommand(),
 		getDashMilestoneOverviewSubCommand(),
+		getDashReleaseSubCommand(),
+		getDashHotfixSubCommand(),
+		getDashChangelogSubCommand(),
+		getDashTUISubCommand(),
+		getDashServeSubCommand(),
 	}
 }
 
@@ -25,6 +43,7 @@ func getDashFeatureDetailSubCommand() *cli.Command {
 		Usage:     "overview of the feature of current project.",
 		ArgsUsage: "-b, --branch_name `BranchName`",
 		Flags: []cli.Flag{
+			outputFlag(),
 			&cli.StringFlag{
 				Name:        "branch_name",
 				Aliases:     []string{"b"},
@@ -35,15 +54,14 @@ func getDashFeatureDetailSubCommand() *cli.Command {
 		},
 		Action: func(c *cli.Context) error {
 			featureBranchName := c.String("branch_name")
-			data, err := getDash(c).FeatureDetail(featureBranchName)
+			view, err := getDash(c).FeatureDetail(featureBranchName)
 			if err != nil {
 				fmt.Printf("\nIf could not parse branch name by default, you can try:\n" +
 					"1. specify a branch name by `-b YOUR-BRANCH-NAME`\n" +
 					"2. switch to feature branch by `git checkout feature/YOUR-BRANCH-NAME`\n")
 				return err
 			}
-			_, _ = fmt.Fprintf(os.Stdout, "%s\n", data)
-			return nil
+			return printView(c, view)
 		},
 	}
 }
@@ -56,6 +74,7 @@ func getDashMilestoneOverviewSubCommand() *cli.Command {
 		Usage:     "overview of one milestone, includes: merges, issues, branch",
 		ArgsUsage: "-m, --milestone_name -b --branch_name",
 		Flags: []cli.Flag{
+			outputFlag(),
 			&cli.StringFlag{
 				Name:    "milestone_name",
 				Aliases: []string{"m"},
@@ -73,11 +92,175 @@ func getDashMilestoneOverviewSubCommand() *cli.Command {
 		Action: func(c *cli.Context) error {
 			milestoneName := c.String("milestone_name")
 			filterBranchName := c.String("branch_name")
-			data, err := getDash(c).MilestoneOverview(milestoneName, filterBranchName)
+			view, err := getDash(c).MilestoneOverview(milestoneName, filterBranchName)
+			if err != nil {
+				return err
+			}
+			return printView(c, view)
+		},
+	}
+}
+
+// gitlab-flow dash hotfix -b hotfixBranchName
+func getDashHotfixSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "hotfix",
+		Aliases:   []string{"hf"},
+		Usage:     "overview of the hotfix, includes: issue, merges into master and back-merges",
+		ArgsUsage: "-b, --hotfix_branch_name `hotfixBranchName`",
+		Flags: []cli.Flag{
+			outputFlag(),
+			&cli.StringFlag{
+				Name:        "hotfix_branch_name",
+				Aliases:     []string{"b"},
+				Usage:       "input the target `hotfixBranchName`",
+				DefaultText: "current branch",
+				Required:    false,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			view, err := getDash(c).HotfixDetail(c.String("hotfix_branch_name"))
+			if err != nil {
+				return err
+			}
+			return printView(c, view)
+		},
+	}
+}
+
+// gitlab-flow dash release -r releaseBranchName
+func getDashReleaseSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "release",
+		Aliases:   []string{"r"},
+		Usage:     "overview of the release, includes: features, merges",
+		ArgsUsage: "-r, --release_branch_name `releaseBranchName` [-l, --list]",
+		Flags: []cli.Flag{
+			outputFlag(),
+			&cli.StringFlag{
+				Name:        "release_branch_name",
+				Aliases:     []string{"r"},
+				Usage:       "input the target `releaseBranchName`",
+				DefaultText: "current branch",
+				Required:    false,
+			},
+			&cli.BoolFlag{
+				Name:    "list",
+				Aliases: []string{"l"},
+				Usage:   "list all releases of current project",
+				Value:   false,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.Bool("list") {
+				view, err := getDash(c).ReleaseOverview()
+				if err != nil {
+					return err
+				}
+				return printView(c, view)
+			}
+
+			view, err := getDash(c).ReleaseDetail(c.String("release_branch_name"))
+			if err != nil {
+				return err
+			}
+			return printView(c, view)
+		},
+	}
+}
+
+// gitlab-flow dash changelog [-m milestoneName | --from milestoneName [--to milestoneName]] [-w]
+func getDashChangelogSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "changelog",
+		Aliases: []string{"cl"},
+		Usage:   "render markdown release notes of one milestone or a range of milestones",
+		ArgsUsage: "-m, --milestone_name `milestoneName` | --from `milestoneName` [--to `milestoneName`] " +
+			"[-g, --group_by label|prefix] [-w, --write]",
+		Flags: []cli.Flag{
+			outputFlag(),
+			&cli.StringFlag{
+				Name:        "milestone_name",
+				Aliases:     []string{"m"},
+				Usage:       "render changelog of `milestoneName`",
+				DefaultText: "current branch milestone",
+			},
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: "render changelog of milestones created since `milestoneName` (included)",
+			},
+			&cli.StringFlag{
+				Name:        "to",
+				Usage:       "render changelog of milestones created until `milestoneName` (included)",
+				DefaultText: "latest milestone",
+			},
+			&cli.StringFlag{
+				Name:    "group_by",
+				Aliases: []string{"g"},
+				Usage: "group issues by `groupBy`, label means issue labels, prefix means " +
+					"conventional-commit prefix of issue title (feat, fix ...)",
+				Value: string(internal.ChangelogGroupByPrefix),
+			},
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "top heading `title` of changelog",
+			},
+			&cli.BoolFlag{
+				Name:    "write",
+				Aliases: []string{"w"},
+				Usage:   "prepend changelog to file instead of printing to stdout",
+				Value:   false,
+			},
+			&cli.StringFlag{
+				Name:  "file",
+				Usage: "changelog `file` to prepend into, relative path is based on working directory",
+				Value: "CHANGELOG.md",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			groupBy := internal.ChangelogGroupBy(c.String("group_by"))
+			if groupBy != internal.ChangelogGroupByLabel && groupBy != internal.ChangelogGroupByPrefix {
+				return fmt.Errorf("invalid group_by: %s, must be one of (label, prefix)", groupBy)
+			}
+
+			view, err := getDash(c).Changelog(&internal.ChangelogOption{
+				Title:         c.String("title"),
+				MilestoneName: c.String("milestone_name"),
+				FromMilestone: c.String("from"),
+				ToMilestone:   c.String("to"),
+				GroupBy:       groupBy,
+			})
 			if err != nil {
 				return err
 			}
-			_, _ = fmt.Fprintf(os.Stdout, "%s\n", data)
+
+			if !c.Bool("write") {
+				return printView(c, view)
+			}
+
+			// changelog file is always written in markdown, and the top heading is written only once.
+			var heading []byte
+			if view.Title != "" {
+				heading = []byte("# " + view.Title + "\n\n")
+				view.Title = ""
+			}
+			data, err := view.Markdown()
+			if err != nil {
+				return err
+			}
+
+			file := c.String("file")
+			if !filepath.IsAbs(file) {
+				cwd := parseGlobalFlags(c).CWD
+				if cwd == "" {
+					cwd = defaultCWD()
+				}
+				file = filepath.Join(cwd, file)
+			}
+			if err = pkg.PrependFileUnder(file, heading, data); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(os.Stdout, "changelog has been written into %s\n", file)
 			return nil
 		},
 	}
@@ -90,23 +273,130 @@ func getDashProjectDetailSubCommand() *cli.Command {
 		Aliases: []string{"p"},
 		Usage:   "do something of current project.",
 		Flags: []cli.Flag{
+			outputFlag(),
 			&cli.StringFlag{
 				Name:        "module",
 				Aliases:     []string{"m"},
-				Usage:       "project `module`, module is one of (home, tag, branch, commit)",
+				Usage:       "project `module`, module is one of (home, tag, branch, commit, links, stats)",
 				DefaultText: "all",
 				Value:       "all",
 				Required:    false,
 			},
+			&cli.IntFlag{
+				Name:        "days",
+				Aliases:     []string{"d"},
+				Usage:       "time window of recent statistics in `days`, such as hotfix count",
+				DefaultText: "30",
+				Value:       30,
+				Required:    false,
+			},
 		},
 		Action: func(c *cli.Context) error {
 			module := c.String("module")
-			data, err := getDash(c).ProjectDetail(module)
+			days := c.Int("days")
+			if days <= 0 {
+				return errors.New("days must be positive")
+			}
+			view, err := getDash(c).ProjectDetail(module, days)
 			if err != nil {
 				return err
 			}
-			_, _ = fmt.Fprintf(os.Stdout, "%s\n", data)
-			return nil
+			return printView(c, view)
+		},
+	}
+}
+
+// gitlab-flow dash tui
+func getDashTUISubCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "tui",
+		Usage: "browse projects, features, issues and merge requests in an interactive terminal dashboard.",
+		Description: `keys: enter/esc/tab to navigate, o to open web URL, c to checkout the branch,
+d/t/m to run feature debug/test/release, r to refresh from local data, s to sync the milestone, q to quit.`,
+		Action: func(c *cli.Context) error {
+			flags := parseGlobalFlags(c)
+			if flags.NonInteractive {
+				return errors.New("dash tui could not run in non-interactive mode")
+			}
+
+			logLevel := log.LevelInfo
+			if flags.DebugMode {
+				logLevel = log.LevelDebug
+			}
+
+			// all projects share the local repository, so it's opened only once.
+			ctx, ch := buildFlowContextWithFlags(flags)
+			repo := internal.NewRepository(ctx, ch)
+			wb, err := newWorkbench(ctx, ch, repo)
+			if err != nil {
+				return err
+			}
+
+			return tui.Run(wb, func(project *internal.ProjectItemView) (*tui.Workbench, error) {
+				if fi, err := os.Stat(project.LocalDir); err != nil || !fi.IsDir() {
+					return nil, errors.Errorf("directory %s of project(%s) not found", project.LocalDir, project.Name)
+				}
+
+				flags.CWD = project.LocalDir
+				flags.ProjectName = strconv.Itoa(project.ID)
+				ctx, ch := buildFlowContextWithFlags(flags)
+				return newWorkbench(ctx, ch, repo)
+			}, logLevel)
+		},
+	}
+}
+
+// newWorkbench creates the workbench of project with the shared repository, flow is created on demand.
+func newWorkbench(
+	ctx *types.FlowContext, ch internal.IConfigHelper, repo repository.IFlowRepository) (*tui.Workbench, error) {
+	dash, err := internal.NewDashWithRepository(ctx, ch, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tui.Workbench{
+		Dash: dash,
+		Flow: func() (internal.IFlow, error) { return internal.NewFlowWithRepository(ctx, ch, repo) },
+		Git:  gitop.New(ctx.Config().GitBackend, ctx.CWD(), ctx.Config().GitRemote),
+	}, nil
+}
+
+// gitlab-flow dash serve --addr 127.0.0.1:2334
+func getDashServeSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "serve in-flight features of all projects from local data as web pages and JSON API.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "addr",
+				Aliases:  []string{"a"},
+				Usage:    "listen `address`, use 0.0.0.0:port to share with others in the network",
+				Value:    "127.0.0.1:2334",
+				Required: false,
+			},
+			&cli.IntFlag{
+				Name:     "days",
+				Aliases:  []string{"d"},
+				Usage:    "default time window of project statistics in `days`",
+				Value:    30,
+				Required: false,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// pages are across projects, so the current directory is not required to be a project.
+			flowCtx, ch := buildFlowContextWithFlags(parseGlobalFlags(c))
+			dash := internal.NewGlobalDash(flowCtx, ch, internal.NewRepository(flowCtx, ch))
+			handler, err := web.NewHandler(dash, c.Int("days"))
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			addr := c.String("addr")
+			_, _ = fmt.Fprintf(os.Stdout, "dashboard is serving on http://%s, press Ctrl+C to stop\n", addr)
+			return web.Serve(ctx, addr, handler)
 		},
 	}
 }
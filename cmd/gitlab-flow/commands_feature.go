@@ -8,6 +8,7 @@ import (
 	cli "github.com/urfave/cli/v2"
 	"github.com/yeqown/log"
 
+	"github.com/yeqown/gitlab-flow/internal"
 	"github.com/yeqown/gitlab-flow/internal/types"
 )
 
@@ -20,7 +21,11 @@ func getFeatureSubCommands() cli.Commands {
 		getFeatureDebugSubCommand(),
 		getFeatureTestSubCommand(),
 		getFeatureReleaseSubCommand(),
+		getFeaturePipelineSubCommand(),
 		getFeatureResolveConflictCommand(),
+		getFeatureUpdateSubCommand(),
+		getFeatureUpdateIssueSubCommand(),
+		getFeatureCloseSubCommand(),
 		getCheckoutCommand(),
 	}
 }
@@ -33,19 +38,54 @@ func getFeatureBeginSubCommand() *cli.Command {
 	return &cli.Command{
 		Name:        "open",
 		Usage:       "open a milestone and branch name, feature name would be same to milestone",
-		ArgsUsage:   "open @title @desc",
-		Description: "@title title of milestone \n\t @desc description of milestone",
+		ArgsUsage:   "open [--bump major|minor|patch] @title @desc",
+		Description: "@title title of milestone, it could be empty if --bump is set \n\t @desc description of milestone",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name: "bump",
+				Usage: "suggest the next semantic version from remote tags and milestones by bumping " +
+					"`part` (major, minor, patch), the suggestion would be used as title if title is empty",
+			},
+			&cli.BoolFlag{
+				Name: "group-milestone",
+				Usage: "create the milestone in the group which the project belongs to, " +
+					"so that it could be shared by projects of the group",
+			},
+		},
 		Action: func(c *cli.Context) error {
+			opc := getOpFeatureContext(c)
+			opc.Bump = c.String("bump")
+			opc.GroupMilestone = c.Bool("group-milestone")
+
 			title := c.Args().Get(0)
 			desc := c.Args().Get(1)
-			if title == "" {
+			if opc.Bump != "" && c.Args().Len() == 1 {
+				// only description is provided, title would be the suggested version.
+				title, desc = "", title
+			}
+			if title == "" && opc.Bump == "" {
 				return errors.New("'Title' could not be empty")
 			}
 			if desc == "" {
 				return errors.New("'Description' could not be empty")
 			}
-			opc := getOpFeatureContext(c)
-			return getFlow(c).FeatureBegin(opc, title, desc)
+
+			projects, err := getWorkspaceProjects(c)
+			if err != nil {
+				return err
+			}
+			if len(projects) == 0 {
+				return getFlow(c).FeatureBegin(opc, title, desc)
+			}
+			// milestone title must be the same in all projects, so that it could not be suggested
+			// by each project separately.
+			if title == "" {
+				return errors.New("'Title' could not be empty while opening feature in several projects")
+			}
+			return runInProjects(c, projects, func(flow internal.IFlow) error {
+				copied := *opc
+				return flow.FeatureBegin(&copied, title, desc)
+			})
 		},
 	}
 }
@@ -56,8 +96,15 @@ func getFeatureBeginIssueSubCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "open-issue",
 		Usage:     "open an issue then create issue branch from feature branch, also merge request",
-		ArgsUsage: "open-issue -f @title @desc",
-		Flags:     []cli.Flag{},
+		ArgsUsage: "open-issue -f [--draft] @title @desc",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name: "draft",
+				Usage: "open a draft merge request from issue branch into feature branch, " +
+					"it would be marked as ready by close-issue",
+				Required: false,
+			},
+		},
 		Action: func(c *cli.Context) error {
 			defer func() {
 				log.
@@ -68,6 +115,7 @@ func getFeatureBeginIssueSubCommand() *cli.Command {
 			issueTitle := c.Args().Get(0)
 			issueDesc := c.Args().Get(1)
 			opc := getOpFeatureContext(c)
+			opc.Draft = c.Bool("draft")
 			return getFlow(c).FeatureBeginIssue(opc, issueTitle, issueDesc)
 		},
 	}
@@ -106,7 +154,7 @@ func getFeatureDebugSubCommand() *cli.Command {
 		Flags:     []cli.Flag{},
 		Action: func(c *cli.Context) error {
 			opc := getOpFeatureContext(c)
-			return getFlow(c).FeatureDebugging(opc)
+			return runFeatureAction(c, opc, internal.IFlow.FeatureDebugging)
 		},
 	}
 }
@@ -120,7 +168,7 @@ func getFeatureTestSubCommand() *cli.Command {
 		Flags:     []cli.Flag{},
 		Action: func(c *cli.Context) error {
 			opc := getOpFeatureContext(c)
-			return getFlow(c).FeatureTest(opc)
+			return runFeatureAction(c, opc, internal.IFlow.FeatureTest)
 		},
 	}
 }
@@ -130,11 +178,65 @@ func getFeatureReleaseSubCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "release",
 		Usage:     "open a merge request from feature branch into MasterBranch",
-		ArgsUsage: "-f, --feature_branch_name `featureBranchName`",
-		Flags:     []cli.Flag{},
+		ArgsUsage: "-f, --feature_branch_name `featureBranchName` [-t, --tag `tagName`] [--ignore-pipeline]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "tag",
+				Aliases: []string{"t"},
+				Usage: "`tagName` to create on the merge commit after the merge request has been merged, " +
+					"a gitlab release would be published with notes of the milestone",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "ignore-pipeline",
+				Usage:    "release even if the latest pipeline of feature branch has failed",
+				Required: false,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			opc := getOpFeatureContext(c)
+			opc.ReleaseTag = c.String("tag")
+			opc.IgnorePipeline = c.Bool("ignore-pipeline")
+			return runFeatureAction(c, opc, internal.IFlow.FeatureRelease)
+		},
+	}
+}
+
+// getFeaturePipelineSubCommand shows the latest pipeline of feature branch, or of the merge
+// request into targetBranch if -t is set. With --wait it polls until the pipeline finished
+// and exits with error if the pipeline failed.
+// gitlab-flow feature pipeline [-t, --target_branch `targetBranch`] [-w, --wait]
+func getFeaturePipelineSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "pipeline",
+		Usage: "show the latest pipeline of feature branch or its merge request",
+		ArgsUsage: "-f, --feature_branch_name `featureBranchName` " +
+			"[-t, --target_branch `targetBranch`] [-w, --wait]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "target_branch",
+				Aliases:  []string{"t"},
+				Usage:    "show pipeline of the merge request from feature branch into `targetBranch`",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "wait",
+				Aliases:  []string{"w"},
+				Usage:    "wait until the pipeline finished, exit with error if it failed",
+				Required: false,
+			},
+			outputFlag(),
+		},
 		Action: func(c *cli.Context) error {
 			opc := getOpFeatureContext(c)
-			return getFlow(c).FeatureRelease(opc)
+			view, err := getFlow(c).FeaturePipeline(opc, c.String("target_branch"), c.Bool("wait"))
+			if view != nil {
+				if err2 := printView(c, view); err2 != nil {
+					return err2
+				}
+			}
+
+			return err
 		},
 	}
 }
@@ -162,6 +264,113 @@ func getFeatureResolveConflictCommand() *cli.Command {
 	}
 }
 
+// updateFlags control how the branch is updated from its base, and continue or abort
+// the update which is stopped by conflicts.
+func updateFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:     "rebase",
+			Usage:    "rebase the branch onto its base rather than merging the base into the branch",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "continue",
+			Usage:    "continue the update after conflicts are resolved and staged",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "abort",
+			Usage:    "abort the update which is stopped by conflicts",
+			Required: false,
+		},
+	}
+}
+
+func getUpdateAction(c *cli.Context) (types.UpdateAction, error) {
+	switch {
+	case c.Bool("continue") && c.Bool("abort"):
+		return "", errors.New("--continue and --abort could not be used together")
+	case c.Bool("continue"):
+		return types.UpdateActionContinue, nil
+	case c.Bool("abort"):
+		return types.UpdateActionAbort, nil
+	}
+
+	return types.UpdateActionStart, nil
+}
+
+// getFeatureUpdateSubCommand update feature branch from MasterBranch.
+// gitlab-flow feature update [--rebase] [--continue] [--abort]
+func getFeatureUpdateSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "update",
+		Usage:     "fetch and then merge MasterBranch into feature branch, or rebase feature branch onto it",
+		ArgsUsage: "-f, --feature_branch_name `featureBranchName` [--rebase] [--continue] [--abort]",
+		Flags:     updateFlags(),
+		Action: func(c *cli.Context) error {
+			action, err := getUpdateAction(c)
+			if err != nil {
+				return err
+			}
+			opc := getOpFeatureContext(c)
+			opc.Rebase = c.Bool("rebase")
+			return getFlow(c).FeatureUpdate(opc, action)
+		},
+	}
+}
+
+// getFeatureUpdateIssueSubCommand update issue branch from its feature branch.
+// gitlab-flow feature update-issue -i @issueBranchName [--rebase] [--continue] [--abort]
+func getFeatureUpdateIssueSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "update-issue",
+		Usage:     "fetch and then merge feature branch into issue branch, or rebase issue branch onto it",
+		ArgsUsage: "update-issue -i @issueBranchName [--rebase] [--continue] [--abort]",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:     "issue_branch_name",
+				Aliases:  []string{"i"},
+				Value:    "", // default current branch
+				Usage:    "input the `issueBranchName`",
+				Required: false,
+			},
+		}, updateFlags()...),
+		Action: func(c *cli.Context) error {
+			action, err := getUpdateAction(c)
+			if err != nil {
+				return err
+			}
+			opc := getOpFeatureContext(c)
+			opc.Rebase = c.Bool("rebase")
+			return getFlow(c).FeatureUpdateIssue(opc, c.String("issue_branch_name"), action)
+		},
+	}
+}
+
+// getFeatureCloseSubCommand close the feature after it has been merged into master.
+// gitlab-flow feature close [-d, --delete-branch]
+func getFeatureCloseSubCommand() *cli.Command {
+	return &cli.Command{
+		Name: "close",
+		Usage: "close the milestone, issues and merge requests of the feature which " +
+			"has been merged into MasterBranch",
+		ArgsUsage: "-f, --feature_branch_name `featureBranchName` [-d, --delete-branch]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:     "delete-branch",
+				Aliases:  []string{"d"},
+				Usage:    "delete feature branch and issue branches both locally and remotely",
+				Required: false,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			deleteBranch := c.Bool("delete-branch")
+			opc := getOpFeatureContext(c)
+			return getFlow(c).FeatureClose(opc, deleteBranch)
+		},
+	}
+}
+
 // getCheckoutCommand checkout to branch related to current feature, feature branch
 // or issue branches. It will list all branches if --list is set.
 // It would interact with user to choose which branch to check out if --issue is set,
@@ -179,6 +388,7 @@ func getCheckoutCommand() *cli.Command {
 				Name:  "list",
 				Usage: "list all branches",
 			},
+			outputFlag(),
 		},
 		Action: func(c *cli.Context) error {
 			listAll := c.Bool("list")
@@ -201,7 +411,9 @@ func getCheckoutCommand() *cli.Command {
 			}
 
 			opc := getOpFeatureContext(c)
-			getFlow(c).Checkout(opc, listAll, issueID)
+			if view := getFlow(c).Checkout(opc, listAll, issueID); view != nil {
+				return printView(c, view)
+			}
 
 			return nil
 		},
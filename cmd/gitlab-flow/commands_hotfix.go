@@ -47,10 +47,11 @@ func getHotfixStartSubCommand() *cli.Command {
 // gitlab-flow hotfix release @title @desc
 func getHotfixFinishSubCommand() *cli.Command {
 	return &cli.Command{
-		Name:        "close",
-		Usage:       "close [-b, --hotfix_branch_name `hotfixBranchName`]",
-		ArgsUsage:   "[-b, --hotfix_branch_name `hotfixBranchName`]",
-		Description: "close a hotfix development, then create a merge request into master",
+		Name:      "close",
+		Usage:     "close [-b, --hotfix_branch_name `hotfixBranchName`] [--backport] [-t, --tag `tagName`]",
+		ArgsUsage: "[-b, --hotfix_branch_name `hotfixBranchName`] [--backport] [-t, --tag `tagName`]",
+		Description: "close a hotfix development, then create a merge request into master. " +
+			"\n--backport also creates merge requests into develop and test branch",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:     "hotfix_branch_name",
@@ -59,6 +60,20 @@ func getHotfixFinishSubCommand() *cli.Command {
 				Usage:    "`hotfixBranchName`", // be overwritten
 				Required: false,
 			},
+			&cli.BoolFlag{
+				Name: "backport",
+				Usage: "back-merge hotfix into develop and test branch, a conflict-resolve branch " +
+					"would be created if there is conflict",
+				Value:    false,
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:    "tag",
+				Aliases: []string{"t"},
+				Usage: "`tagName` to create on the merge commit after the merge request has been merged, " +
+					"a gitlab release would be published with notes of the hotfix",
+				Required: false,
+			},
 		},
 		Action: func(c *cli.Context) error {
 			defer func() {
@@ -68,6 +83,7 @@ func getHotfixFinishSubCommand() *cli.Command {
 			}()
 			hotfixBranchName := c.String("hotfix_branch_name")
 			opc := getOpHotfixContext(c)
+			opc.ReleaseTag = c.String("tag")
 			return getFlow(c).HotfixFinish(opc, hotfixBranchName)
 		},
 	}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"github.com/pkg/errors"
+	cli "github.com/urfave/cli/v2"
+	"github.com/yeqown/log"
+)
+
+// release subcommands
+func getReleaseSubCommands() cli.Commands {
+	return cli.Commands{
+		getReleaseOpenSubCommand(),
+		getReleaseAttachSubCommand(),
+		getReleaseFinishSubCommand(),
+		getReleaseCloseSubCommand(),
+	}
+}
+
+// getReleaseOpenSubCommand to open a release branch
+// gitlab-flow release open @version @desc
+func getReleaseOpenSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "open",
+		Usage:     "open @version @description",
+		ArgsUsage: "@version [@description]",
+		Description: "open a release branch from the release base branch (test as default)." +
+			"\n@version version of release, release/@version would be the branch name \n@desc description",
+		Action: func(c *cli.Context) error {
+			log.
+				WithFields(log.Fields{"args": c.Args().Slice()}).
+				Debug("open release")
+
+			version := c.Args().Get(0)
+			desc := c.Args().Get(1)
+			if version == "" {
+				return errors.New("version could not be empty")
+			}
+
+			opc := getOpReleaseContext(c)
+			return getFlow(c).ReleaseBegin(opc, version, desc)
+		},
+	}
+}
+
+// getReleaseAttachSubCommand to attach features to release
+// gitlab-flow release attach -f featureA -f featureB
+func getReleaseAttachSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "attach",
+		Usage:     "attach -f `featureBranchName` [-f `featureBranchName`]",
+		ArgsUsage: "-f `featureBranchName` [-f `featureBranchName`]",
+		Description: "attach features to the release, and open merge requests " +
+			"from feature branches to the release branch",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:     "feature",
+				Aliases:  []string{"f"},
+				Usage:    "feature `featureBranchName` to attach, could be specified multiple times",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			opc := getOpReleaseContext(c)
+			return getFlow(c).ReleaseAttach(opc, c.StringSlice("feature"))
+		},
+	}
+}
+
+// getReleaseFinishSubCommand to open merge request from release to master
+// gitlab-flow release finish
+func getReleaseFinishSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "finish",
+		Usage:       "open a merge request from release branch into master",
+		Description: "open a merge request from release branch into master, attached features would be listed",
+		Action: func(c *cli.Context) error {
+			opc := getOpReleaseContext(c)
+			return getFlow(c).ReleaseFinish(opc)
+		},
+	}
+}
+
+// getReleaseCloseSubCommand to tag master after release has been merged
+// gitlab-flow release close [-t tagName]
+func getReleaseCloseSubCommand() *cli.Command {
+	return &cli.Command{
+		Name:        "close",
+		Usage:       "close [-t, --tag `tagName`]",
+		ArgsUsage:   "[-t, --tag `tagName`]",
+		Description: "tag master after the release has been merged into master, then close the release",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "tag",
+				Aliases:     []string{"t"},
+				Usage:       "`tagName` to create on master",
+				DefaultText: "release version",
+				Required:    false,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			opc := getOpReleaseContext(c)
+			return getFlow(c).ReleaseClose(opc, c.String("tag"))
+		},
+	}
+}
@@ -51,6 +51,7 @@ func getSyncMilestoneSubCommand() *cli.Command {
 			&cli.IntFlag{
 				Name:     "milestone_id",
 				Aliases:  []string{"m"},
+				EnvVars:  []string{"GITLAB_FLOW_MILESTONE_ID"},
 				Usage:    "choose milestone manually",
 				Required: false,
 			},
@@ -60,11 +61,18 @@ func getSyncMilestoneSubCommand() *cli.Command {
 				Usage:   "choose milestone in the list load from remote repository",
 				Value:   false,
 			},
+			&cli.BoolFlag{
+				Name:    "group",
+				Aliases: []string{"g"},
+				Usage:   "milestone_id is a group milestone of the group which the project belongs to",
+				Value:   false,
+			},
 		},
 		Action: func(c *cli.Context) error {
-			milestoneID := c.Int("milestoneID")
+			milestoneID := c.Int("milestone_id")
 			interact := c.Bool("interact")
-			return getFlow(c).SyncMilestone(milestoneID, interact)
+			group := c.Bool("group")
+			return getFlow(c).SyncMilestone(milestoneID, group, interact)
 		},
 	}
 }
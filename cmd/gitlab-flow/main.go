@@ -8,6 +8,11 @@ import (
 )
 
 func main() {
+	os.Exit(run(os.Args))
+}
+
+// run runs the app with args and returns the exit code, which is non-zero if the command failed.
+func run(args []string) int {
 	app := cli.NewApp()
 	app.EnableBashCompletion = true
 	app.Name = "gitlab-flow"
@@ -27,9 +32,12 @@ func main() {
 	setupLogger()
 	setupCommands(app)
 
-	if err := app.Run(os.Args); err != nil {
-		log.Infof("App quit: %v", err)
+	if err := app.Run(args); err != nil {
+		log.Errorf("App quit: %v", err)
+		return 1
 	}
+
+	return 0
 }
 
 func setupLogger() {
@@ -51,6 +59,7 @@ func setupCommands(app *cli.App) {
 		getConfigCommand(),
 		getFeatureCommand(),
 		getHotfixCommand(),
+		getReleaseCommand(),
 		getDashCommand(),
 		getSyncCommand(),
 	}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_run(t *testing.T) {
+	assert.Equal(t, 0, run([]string{"gitlab-flow", "--help"}))
+	assert.Equal(t, 0, run([]string{"gitlab-flow", "--version"}))
+
+	// the command fails before any configuration is loaded.
+	assert.Equal(t, 1, run([]string{"gitlab-flow", "--non-interactive", "dash", "tui"}))
+	assert.Equal(t, 1, run([]string{"gitlab-flow", "--no-such-flag"}))
+}
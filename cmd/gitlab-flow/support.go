@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/yeqown/gitlab-flow/internal"
 	"github.com/yeqown/gitlab-flow/internal/conf"
+	"github.com/yeqown/gitlab-flow/internal/render"
 	"github.com/yeqown/gitlab-flow/internal/types"
 	"github.com/yeqown/gitlab-flow/pkg"
 )
@@ -50,6 +52,15 @@ var _cliGlobalFlags = []cli.Flag{
 		DefaultText: "false",
 		Required:    false,
 	},
+	&cli.BoolFlag{
+		Name:        "auto-stash",
+		Value:       false,
+		DefaultText: "false",
+		EnvVars:     []string{"GITLAB_FLOW_AUTO_STASH"},
+		Usage: "stash local changes before switching branches or merging, and restore them after that. " +
+			"Otherwise, these operations are refused if the working tree is not clean.",
+		Required: false,
+	},
 	&cli.StringFlag{
 		Name:        "cwd",
 		Value:       "",
@@ -62,14 +73,30 @@ var _cliGlobalFlags = []cli.Flag{
 		Aliases:     []string{"p"},
 		Value:       "",
 		DefaultText: "",
-		Usage:       "input `projectName` to locate which project should be operate.",
-		Required:    false,
+		EnvVars:     []string{"GITLAB_FLOW_PROJECT"},
+		Usage: "input `projectName` to locate which project should be operate, project ID or " +
+			"full path (namespace/name) is also accepted to resolve projects with the same name.",
+		Required: false,
+	},
+	&cli.BoolFlag{
+		Name:        "non-interactive",
+		Aliases:     []string{"yes"},
+		Value:       false,
+		DefaultText: "false",
+		EnvVars:     []string{"GITLAB_FLOW_NON_INTERACTIVE"},
+		Usage: "never prompt, answers are taken from flags or environment variables (GITLAB_FLOW_*), " +
+			"confirmations take their default answers, and it fails with candidates if a choice is required.",
+		Required: false,
 	},
 }
 
+// _envPrefix is the prefix of environment variables which answer prompts in non-interactive mode.
+const _envPrefix = types.EnvPrefix
+
 type globalFlags struct {
 	DebugMode   bool // verbose mode
 	OpenBrowser bool // open web browser automatically or not
+	AutoStash   bool // stash local changes before switching branches and restore them after that
 	ForceRemote bool // DO NOT query from local, or create remote resource even if local has the same name.
 
 	// ProjectName is the name of project which should be operated.
@@ -78,30 +105,97 @@ type globalFlags struct {
 	// CWD is the current working directory,
 	// if not set, will use the current git repository root path.
 	CWD string
+	// NonInteractive never prompt, prompts are answered by flags or environment variables.
+	NonInteractive bool
+	// Participants overrides participants of all flow steps in configuration.
+	Participants *types.Participants
 }
 
 func parseGlobalFlags(c *cli.Context) globalFlags {
 	return globalFlags{
 		DebugMode:   c.Bool("debug"),
 		OpenBrowser: c.Bool("web"),
+		AutoStash:   c.Bool("auto-stash"),
 		ForceRemote: c.Bool("force-remote"),
 		ProjectName: c.String("project"),
 		CWD:         c.String("cwd"),
+
+		NonInteractive: c.Bool("non-interactive"),
+		Participants: &types.Participants{
+			Assignees: c.StringSlice("assignee"),
+			Reviewers: c.StringSlice("reviewer"),
+			Labels:    c.StringSlice("label"),
+		},
 	}
 }
 
+// participantFlags override assignees, reviewers and labels of issues and merge requests
+// created by the command.
+func participantFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "assignee",
+			Usage: "assign issues and merge requests to `usernames`, @me means yourself",
+		},
+		&cli.StringSliceFlag{
+			Name:  "reviewer",
+			Usage: "request review of merge requests from `usernames`, @me means yourself",
+		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "add `labels` to issues and merge requests",
+		},
+	}
+}
+
+// outputFlag chooses the format of command output, it's used by commands which display data.
+func outputFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Usage:   "output `format`, one of (table, json, yaml, markdown)",
+		Value:   string(render.FormatTable),
+	}
+}
+
+// printView renders v in the format of --output and prints it into stdout.
+func printView(c *cli.Context, v interface{}) error {
+	format, err := render.ParseFormat(c.String("output"))
+	if err != nil {
+		return err
+	}
+
+	data, err := render.Render(format, v)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "%s\n", bytes.TrimRight(data, "\n"))
+	return nil
+}
+
 func getOpFeatureContext(c *cli.Context) *types.OpFeatureContext {
 	return &types.OpFeatureContext{
 		ForceCreateMergeRequest: c.Bool("force-create-mr"),
 		FeatureBranchName:       c.String("feature-branch-name"),
 		AutoMergeRequest:        c.Bool("auto-merge"),
 		ParseIssueCompatible:    c.Bool("parse-issue-compatible"),
+		ResolveConflict:         c.Bool("resolve-conflict"),
 	}
 }
 
 func getOpHotfixContext(c *cli.Context) *types.OpHotfixContext {
 	return &types.OpHotfixContext{
 		ForceCreateMergeRequest: c.Bool("force-create-mr"),
+		Backport:                c.Bool("backport"),
+	}
+}
+
+func getOpReleaseContext(c *cli.Context) *types.OpReleaseContext {
+	return &types.OpReleaseContext{
+		ForceCreateMergeRequest: c.Bool("force-create-mr"),
+		ReleaseBranchName:       c.String("release-branch-name"),
+		AutoMergeRequest:        c.Bool("auto-merge"),
 	}
 }
 
@@ -140,6 +234,11 @@ func mergeConfig(c1 *types.ProjectConfig, c2 *types.Config) *types.Config {
 		GitlabHost:   c2.GitlabHost,
 		DebugMode:    c2.DebugMode,
 		OpenBrowser:  c2.DebugMode,
+		Semver:       c2.Semver,
+		Merge:        c2.Merge,
+		GitBackend:   c2.GitBackend,
+		GitRemote:    c2.GitRemote,
+		AutoStash:    c2.AutoStash,
 	}
 
 	if c1 == nil {
@@ -155,6 +254,23 @@ func mergeConfig(c1 *types.ProjectConfig, c2 *types.Config) *types.Config {
 	if c1.OpenBrowser != nil {
 		render.OpenBrowser = *c1.OpenBrowser
 	}
+	if c1.Semver != nil {
+		render.Semver = *c1.Semver
+	}
+	if c1.Merge != nil {
+		render.Merge = c1.Merge
+	}
+	if c1.GitBackend != "" {
+		render.GitBackend = c1.GitBackend
+	}
+	if c1.GitRemote != "" {
+		render.GitRemote = c1.GitRemote
+	}
+	if c1.AutoStash != nil {
+		render.AutoStash = *c1.AutoStash
+	}
+	render.Participants = c1.Participants
+	render.Templates = c1.Templates
 
 	return render
 }
@@ -218,16 +334,30 @@ func buildFlowContextWithFlags(flags globalFlags) (*types.FlowContext, internal.
 	if flags.OpenBrowser {
 		mergedConfig.OpenBrowser = flags.OpenBrowser
 	}
+	/* auto stash */
+	if flags.AutoStash {
+		mergedConfig.AutoStash = flags.AutoStash
+	}
+	/* participants */
+	if p := flags.Participants; p != nil && (len(p.Assignees) != 0 || len(p.Reviewers) != 0 || len(p.Labels) != 0) {
+		mergedConfig.Participants = mergedConfig.Participants.Override(p)
+	}
 
-	types.SetBranchSetting(mergedConfig.Branch.Master, mergedConfig.Branch.Dev, mergedConfig.Branch.Test)
+	types.SetBranchSetting(
+		mergedConfig.Branch.Master,
+		mergedConfig.Branch.Dev,
+		mergedConfig.Branch.Test,
+		mergedConfig.Branch.ReleaseBase,
+	)
 	types.SetBranchPrefix(
 		mergedConfig.Branch.FeatureBranchPrefix,
 		mergedConfig.Branch.HotfixBranchPrefix,
 		mergedConfig.Branch.ConflictResolveBranchPrefix,
 		mergedConfig.Branch.IssueBranchPrefix,
+		mergedConfig.Branch.ReleaseBranchPrefix,
 	)
 
-	return types.NewContext(cwd, projectName, mergedConfig, flags.ForceRemote), helper
+	return types.NewContext(cwd, projectName, mergedConfig, flags.ForceRemote, flags.NonInteractive), helper
 }
 
 var (
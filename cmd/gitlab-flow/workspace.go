@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	cli "github.com/urfave/cli/v2"
+	"github.com/yeqown/log"
+
+	"github.com/yeqown/gitlab-flow/internal"
+	"github.com/yeqown/gitlab-flow/internal/conf"
+	gitop "github.com/yeqown/gitlab-flow/internal/git-operator"
+	"github.com/yeqown/gitlab-flow/internal/repository"
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+// getWorkspaceProjects returns projects specified by --projects or --workspace, empty result
+// means only the current project should be operated.
+//
+// Projects of --projects are looked up in the workspace file (--workspace or the default one
+// cwd/.gitlab-flow/workspace.toml) to locate their directories, a sibling directory of the current
+// working directory with the same name would be used if it's not listed in the workspace.
+func getWorkspaceProjects(c *cli.Context) ([]*types.WorkspaceProject, error) {
+	names := c.StringSlice("projects")
+	wsPath := c.String("workspace")
+	if len(names) == 0 && wsPath == "" {
+		return nil, nil
+	}
+
+	cwd := defaultCWD()
+	if flags := parseGlobalFlags(c); flags.CWD != "" {
+		cwd = flags.CWD
+	}
+	cwd, err := filepath.Abs(cwd)
+	if err != nil {
+		return nil, errors.Wrap(err, "get absolute path of working directory")
+	}
+
+	var ws *types.Workspace
+	if wsPath == "" {
+		wsPath = conf.WorkspacePath(cwd)
+		if _, err = os.Stat(wsPath); err != nil {
+			// the default workspace file is optional.
+			wsPath = ""
+		}
+	}
+	if wsPath != "" {
+		if ws, err = conf.LoadWorkspace(wsPath, cwd); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(names) == 0 {
+		if len(ws.Projects) == 0 {
+			return nil, errors.Errorf("no project found in workspace(%s)", wsPath)
+		}
+		return ws.Projects, nil
+	}
+
+	return resolveWorkspaceProjects(ws, names, cwd), nil
+}
+
+// resolveWorkspaceProjects locates the directory of each project name, the project listed in ws
+// is preferred, otherwise it's the current working directory or a sibling directory of it.
+func resolveWorkspaceProjects(ws *types.Workspace, names []string, cwd string) []*types.WorkspaceProject {
+	projects := make([]*types.WorkspaceProject, 0, len(names))
+	for _, name := range names {
+		if p := ws.Lookup(name); p != nil {
+			projects = append(projects, p)
+			continue
+		}
+
+		p := &types.WorkspaceProject{Name: name, Path: filepath.Join(filepath.Dir(cwd), name)}
+		if filepath.Base(cwd) == name {
+			p.Path = cwd
+		}
+		projects = append(projects, p)
+	}
+
+	return projects
+}
+
+var _workspaceStatusTblHeader = []string{"Project", "Directory", "Status"}
+
+// runInProjects runs fn with the flow of each project one by one, and prints a combined
+// status table. A failed project would not stop the rest projects.
+func runInProjects(c *cli.Context, projects []*types.WorkspaceProject, fn func(flow internal.IFlow) error) error {
+	flags := parseGlobalFlags(c)
+	tblData := make([][]string, 0, len(projects))
+	failed := 0
+	// all projects share the local repository, so it's opened only once.
+	var repo repository.IFlowRepository
+
+	for _, p := range projects {
+		_, _ = fmt.Fprintf(os.Stdout, "\n>>> %s (%s)\n", p.Name, p.Path)
+
+		err := func() error {
+			if fi, err := os.Stat(p.Path); err != nil || !fi.IsDir() {
+				return errors.Errorf("directory %s not found", p.Path)
+			}
+
+			flags.CWD = p.Path
+			flags.ProjectName = p.Name
+			ctx, ch := buildFlowContextWithFlags(flags)
+			if repo == nil {
+				repo = internal.NewRepository(ctx, ch)
+			}
+			flow, err := internal.NewFlowWithRepository(ctx, ch, repo)
+			if err != nil {
+				return err
+			}
+			return fn(flow)
+		}()
+
+		status := "✅ done"
+		if err != nil {
+			log.
+				WithFields(log.Fields{"project": p.Name, "path": p.Path}).
+				Errorf("operate project failed: %v", err)
+			status = "❌ " + err.Error()
+			failed++
+		}
+		tblData = append(tblData, []string{p.Name, p.Path, status})
+	}
+
+	fmt.Println()
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader(_workspaceStatusTblHeader)
+	w.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	w.SetAlignment(tablewriter.ALIGN_LEFT)
+	w.SetRowLine(true)
+	w.AppendBulk(tblData)
+	w.Render()
+
+	if failed != 0 {
+		return errors.Errorf("%d of %d projects failed", failed, len(projects))
+	}
+
+	return nil
+}
+
+// runFeatureAction runs fn in the current project, or in all projects of workspace if --projects
+// or --workspace is set. The feature branch name would be the current branch of the current project
+// if it's not specified, so that all projects operate the same feature branch.
+func runFeatureAction(
+	c *cli.Context, opc *types.OpFeatureContext, fn func(flow internal.IFlow, opc *types.OpFeatureContext) error) error {
+	projects, err := getWorkspaceProjects(c)
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		return fn(getFlow(c), opc)
+	}
+
+	if opc.FeatureBranchName == "" {
+		ctx, _ := buildFlowContextWithFlags(parseGlobalFlags(c))
+		gitOperator := gitop.New(ctx.Config().GitBackend, ctx.CWD(), ctx.Config().GitRemote)
+		if opc.FeatureBranchName, err = gitOperator.CurrentBranch(); err != nil {
+			return errors.Wrap(err, "could not get current branch, please specify feature branch name")
+		}
+	}
+
+	return runInProjects(c, projects, func(flow internal.IFlow) error {
+		// flow may modify the context, so each project uses its own copy.
+		copied := *opc
+		return fn(flow, &copied)
+	})
+}
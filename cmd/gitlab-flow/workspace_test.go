@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+func Test_resolveWorkspaceProjects(t *testing.T) {
+	ws := &types.Workspace{
+		Projects: []*types.WorkspaceProject{
+			{Name: "b", Path: "/repos/service-b"},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		ws    *types.Workspace
+		names []string
+		want  []*types.WorkspaceProject
+	}{
+		{
+			name:  "listed in workspace",
+			ws:    ws,
+			names: []string{"b"},
+			want:  []*types.WorkspaceProject{{Name: "b", Path: "/repos/service-b"}},
+		},
+		{
+			name:  "current project",
+			ws:    ws,
+			names: []string{"a"},
+			want:  []*types.WorkspaceProject{{Name: "a", Path: "/work/a"}},
+		},
+		{
+			name:  "sibling directory",
+			ws:    ws,
+			names: []string{"c"},
+			want:  []*types.WorkspaceProject{{Name: "c", Path: "/work/c"}},
+		},
+		{
+			name:  "without workspace",
+			ws:    nil,
+			names: []string{"a", "b"},
+			want: []*types.WorkspaceProject{
+				{Name: "a", Path: "/work/a"},
+				{Name: "b", Path: "/work/b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolveWorkspaceProjects(tt.ws, tt.names, "/work/a"))
+		})
+	}
+}
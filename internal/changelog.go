@@ -0,0 +1,274 @@
+package internal
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/yeqown/gitlab-flow/internal/repository"
+)
+
+// ChangelogGroupBy decides how issues are grouped in changelog.
+type ChangelogGroupBy string
+
+const (
+	// ChangelogGroupByPrefix groups issues by conventional-commit prefix of issue title,
+	// such as "feat: xxx", "fix(scope): xxx".
+	ChangelogGroupByPrefix ChangelogGroupBy = "prefix"
+	// ChangelogGroupByLabel groups issues by labels of issue, an issue with multiple labels
+	// would be listed in each group.
+	ChangelogGroupByLabel ChangelogGroupBy = "label"
+)
+
+// ChangelogOption contains options to render changelog.
+type ChangelogOption struct {
+	// Title of changelog, would be rendered as the top heading if not empty.
+	Title string
+	// MilestoneName renders changelog of only one milestone.
+	MilestoneName string
+	// FromMilestone and ToMilestone renders changelog of milestones which are created
+	// between them (both included). ToMilestone would be the latest milestone if it's empty.
+	FromMilestone string
+	ToMilestone   string
+	// GroupBy decides how issues are grouped, default is ChangelogGroupByPrefix.
+	GroupBy ChangelogGroupBy
+}
+
+// _changelogOthersGroup holds issues could not be grouped.
+const _changelogOthersGroup = "Others"
+
+var (
+	// conventionalTitleRegexp matches title like: "feat(scope)!: subject".
+	conventionalTitleRegexp = regexp.MustCompile(`^(\w+)(\((.+)\))?(!)?:\s*(.+)$`)
+
+	// _changelogPrefixGroups maps conventional-commit prefix into group name,
+	// and the order of groups is same as the order of _changelogPrefixGroupOrder.
+	_changelogPrefixGroups = map[string]string{
+		"feat":     "Features",
+		"feature":  "Features",
+		"fix":      "Bug Fixes",
+		"bugfix":   "Bug Fixes",
+		"hotfix":   "Bug Fixes",
+		"perf":     "Performance Improvements",
+		"refactor": "Code Refactoring",
+		"docs":     "Documentation",
+		"test":     "Tests",
+		"build":    "Build System",
+		"ci":       "Continuous Integration",
+		"chore":    "Chores",
+		"style":    "Styles",
+		"revert":   "Reverts",
+	}
+	_changelogPrefixGroupOrder = []string{
+		"Features", "Bug Fixes", "Performance Improvements", "Code Refactoring",
+		"Documentation", "Tests", "Build System", "Continuous Integration",
+		"Chores", "Styles", "Reverts", _changelogOthersGroup,
+	}
+)
+
+// ChangelogEntry is an issue in changelog, along with merge requests which close it.
+type ChangelogEntry struct {
+	IssueIID      int                 `json:"issue_iid"`
+	Subject       string              `json:"subject"`
+	Scope         string              `json:"scope,omitempty"`
+	Breaking      bool                `json:"breaking"`
+	WebURL        string              `json:"web_url"`
+	MergeRequests []*MergeRequestView `json:"merge_requests"`
+}
+
+// ChangelogGroup is a group of entries, such as "Features", "Bug Fixes".
+type ChangelogGroup struct {
+	Name    string            `json:"name"`
+	Entries []*ChangelogEntry `json:"entries"`
+}
+
+// ChangelogSection is changelog of one milestone.
+type ChangelogSection struct {
+	Title  string            `json:"title"`
+	Date   string            `json:"date,omitempty"`
+	Desc   string            `json:"desc"`
+	WebURL string            `json:"web_url"`
+	Groups []*ChangelogGroup `json:"groups"`
+}
+
+// parseConventionalTitle parses issue title in conventional-commit format,
+// ok is false if title is not matched.
+func parseConventionalTitle(title string) (typ, scope, subject string, breaking, ok bool) {
+	title = strings.TrimSpace(title)
+	matched := conventionalTitleRegexp.FindStringSubmatch(title)
+	if len(matched) == 0 {
+		return "", "", title, false, false
+	}
+
+	return strings.ToLower(matched[1]), matched[3], matched[5], matched[4] == "!", true
+}
+
+// groupChangelogEntries groups issues into changelog groups, merge requests are linked to
+// issue by IssueIID and closed merge requests are ignored.
+func groupChangelogEntries(
+	issues []*repository.IssueDO,
+	mrs []*repository.MergeRequestDO,
+	groupBy ChangelogGroupBy,
+) []*ChangelogGroup {
+	issueMRs := make(map[int][]*MergeRequestView, len(issues))
+	for _, mr := range mrs {
+		if mr.IssueIID == 0 || mr.State == repository.MergeRequestStateClosed {
+			continue
+		}
+		issueMRs[mr.IssueIID] = append(issueMRs[mr.IssueIID], newMergeRequestView(mr))
+	}
+
+	groups := make(map[string]*ChangelogGroup, 8)
+	appendTo := func(name string, entry *ChangelogEntry) {
+		g, ok := groups[name]
+		if !ok {
+			g = &ChangelogGroup{Name: name}
+			groups[name] = g
+		}
+		g.Entries = append(g.Entries, entry)
+	}
+
+	for _, issue := range issues {
+		typ, scope, subject, breaking, ok := parseConventionalTitle(issue.Title)
+		entry := &ChangelogEntry{
+			IssueIID:      issue.IssueIID,
+			Subject:       subject,
+			Scope:         scope,
+			Breaking:      breaking,
+			WebURL:        issue.WebURL,
+			MergeRequests: issueMRs[issue.IssueIID],
+		}
+
+		switch groupBy {
+		case ChangelogGroupByLabel:
+			labels := issue.LabelList()
+			if len(labels) == 0 {
+				appendTo(_changelogOthersGroup, entry)
+			}
+			for _, label := range labels {
+				appendTo(label, entry)
+			}
+		default:
+			name, known := _changelogPrefixGroups[typ]
+			if !ok || !known {
+				name = _changelogOthersGroup
+			}
+			appendTo(name, entry)
+		}
+	}
+
+	return sortChangelogGroups(groups, groupBy)
+}
+
+// sortChangelogGroups sorts groups in the order of _changelogPrefixGroupOrder while grouping by prefix,
+// otherwise by name. Others group is always the last one.
+func sortChangelogGroups(groups map[string]*ChangelogGroup, groupBy ChangelogGroupBy) []*ChangelogGroup {
+	out := make([]*ChangelogGroup, 0, len(groups))
+	if groupBy != ChangelogGroupByLabel {
+		for _, name := range _changelogPrefixGroupOrder {
+			if g, ok := groups[name]; ok {
+				out = append(out, g)
+			}
+		}
+		return out
+	}
+
+	for name, g := range groups {
+		if name == _changelogOthersGroup {
+			continue
+		}
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	if g, ok := groups[_changelogOthersGroup]; ok {
+		out = append(out, g)
+	}
+
+	return out
+}
+
+var (
+	changelogTpl        *template.Template
+	changelogTplPattern = `{{- if .title }}# {{ .title }}
+
+{{ end -}}
+{{- range .sections }}## [{{ .Title }}]({{ .WebURL }}){{ if .Date }} ({{ .Date }}){{ end }}
+{{ if .Desc }}
+{{ .Desc }}
+{{ end }}
+{{- range .Groups }}
+### {{ .Name }}
+
+{{ range .Entries }}- {{ if .Breaking }}**BREAKING** {{ end }}{{ if .Scope }}**{{ .Scope }}:** {{ end }}{{ .Subject }} ([#{{ .IssueIID }}]({{ .WebURL }})){{ range .MergeRequests }} [!{{ .IID }}]({{ .WebURL }}){{ end }}
+{{ end }}
+{{- end }}
+{{ end -}}
+`
+)
+
+func init() {
+	changelogTpl = template.Must(
+		template.New("changelog").Parse(changelogTplPattern))
+}
+
+// renderChangelog renders sections into markdown.
+func renderChangelog(title string, sections []*ChangelogSection) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	data := map[string]interface{}{
+		"title":    title,
+		"sections": sections,
+	}
+	if err := changelogTpl.Execute(buf, data); err != nil {
+		return nil, errors.Wrap(err, "changelogTpl.Execute")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// selectChangelogMilestones picks milestones from the given milestones which are ordered by
+// created_at DESC, the result is ordered by created_at DESC too, so that the latest milestone
+// would be on the top of changelog.
+func selectChangelogMilestones(
+	milestones []*repository.MilestoneDO, opt *ChangelogOption) ([]*repository.MilestoneDO, error) {
+	indexOf := func(title string) int {
+		for idx, v := range milestones {
+			if v.Title == title {
+				return idx
+			}
+		}
+		return -1
+	}
+
+	if opt.MilestoneName != "" {
+		idx := indexOf(opt.MilestoneName)
+		if idx < 0 {
+			return nil, errors.Errorf("milestone(%s) not found", opt.MilestoneName)
+		}
+		return milestones[idx : idx+1], nil
+	}
+
+	if opt.FromMilestone == "" {
+		return nil, errors.New("milestone name or range is required")
+	}
+
+	from := indexOf(opt.FromMilestone)
+	if from < 0 {
+		return nil, errors.Errorf("milestone(%s) not found", opt.FromMilestone)
+	}
+	to := 0
+	if opt.ToMilestone != "" {
+		if to = indexOf(opt.ToMilestone); to < 0 {
+			return nil, errors.Errorf("milestone(%s) not found", opt.ToMilestone)
+		}
+	}
+	if to > from {
+		return nil, errors.Errorf("milestone(%s) is created before milestone(%s)",
+			opt.ToMilestone, opt.FromMilestone)
+	}
+
+	return milestones[to : from+1], nil
+}
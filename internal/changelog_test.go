@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yeqown/gitlab-flow/internal/repository"
+)
+
+func Test_parseConventionalTitle(t *testing.T) {
+	tests := []struct {
+		title    string
+		typ      string
+		scope    string
+		subject  string
+		breaking bool
+		ok       bool
+	}{
+		{title: "feat: login page", typ: "feat", subject: "login page", ok: true},
+		{title: "Fix(auth)!: token expired", typ: "fix", scope: "auth", subject: "token expired", breaking: true, ok: true},
+		{title: "login page", subject: "login page", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			typ, scope, subject, breaking, ok := parseConventionalTitle(tt.title)
+			assert.Equal(t, tt.typ, typ)
+			assert.Equal(t, tt.scope, scope)
+			assert.Equal(t, tt.subject, subject)
+			assert.Equal(t, tt.breaking, breaking)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func Test_groupChangelogEntries(t *testing.T) {
+	issues := []*repository.IssueDO{
+		{IssueIID: 1, Title: "fix: crash on start", Labels: "bug"},
+		{IssueIID: 2, Title: "feat(ui): dark mode", Labels: "ui,feature"},
+		{IssueIID: 3, Title: "upgrade dependencies"},
+	}
+	mrs := []*repository.MergeRequestDO{
+		{MergeRequestIID: 10, IssueIID: 2, State: repository.MergeRequestStateMerged},
+		{MergeRequestIID: 11, IssueIID: 2, State: repository.MergeRequestStateClosed},
+	}
+
+	groups := groupChangelogEntries(issues, mrs, ChangelogGroupByPrefix)
+	if assert.Len(t, groups, 3) {
+		assert.Equal(t, "Features", groups[0].Name)
+		assert.Equal(t, "ui", groups[0].Entries[0].Scope)
+		assert.Len(t, groups[0].Entries[0].MergeRequests, 1)
+		assert.Equal(t, "Bug Fixes", groups[1].Name)
+		assert.Equal(t, _changelogOthersGroup, groups[2].Name)
+	}
+
+	groups = groupChangelogEntries(issues, mrs, ChangelogGroupByLabel)
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	assert.Equal(t, []string{"bug", "feature", "ui", _changelogOthersGroup}, names)
+}
+
+func Test_renderChangelog(t *testing.T) {
+	closedAt := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	sections := []*ChangelogSection{
+		{
+			Title:  "v1.2.0",
+			Date:   closedAt.Format("2006-01-02"),
+			WebURL: "https://gitlab.example.com/p/-/milestones/3",
+			Groups: groupChangelogEntries(
+				[]*repository.IssueDO{{IssueIID: 1, Title: "feat: login", WebURL: "https://gitlab.example.com/p/-/issues/1"}},
+				[]*repository.MergeRequestDO{{MergeRequestIID: 2, IssueIID: 1, WebURL: "https://gitlab.example.com/p/-/merge_requests/2"}},
+				ChangelogGroupByPrefix,
+			),
+		},
+	}
+
+	data, err := renderChangelog("Changelog", sections)
+	assert.NoError(t, err)
+	expected := `# Changelog
+
+## [v1.2.0](https://gitlab.example.com/p/-/milestones/3) (2021-06-01)
+
+### Features
+
+- login ([#1](https://gitlab.example.com/p/-/issues/1)) [!2](https://gitlab.example.com/p/-/merge_requests/2)
+
+`
+	assert.Equal(t, expected, string(data))
+}
+
+func Test_selectChangelogMilestones(t *testing.T) {
+	// ordered by created_at DESC
+	milestones := []*repository.MilestoneDO{
+		{Title: "v4"}, {Title: "v3"}, {Title: "v2"}, {Title: "v1"},
+	}
+	titles := func(ms []*repository.MilestoneDO) []string {
+		out := make([]string, 0, len(ms))
+		for _, m := range ms {
+			out = append(out, m.Title)
+		}
+		return out
+	}
+
+	got, err := selectChangelogMilestones(milestones, &ChangelogOption{MilestoneName: "v2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v2"}, titles(got))
+
+	got, err = selectChangelogMilestones(milestones, &ChangelogOption{FromMilestone: "v2", ToMilestone: "v3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v3", "v2"}, titles(got))
+
+	got, err = selectChangelogMilestones(milestones, &ChangelogOption{FromMilestone: "v3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"v4", "v3"}, titles(got))
+
+	_, err = selectChangelogMilestones(milestones, &ChangelogOption{FromMilestone: "v3", ToMilestone: "v1"})
+	assert.Error(t, err)
+	_, err = selectChangelogMilestones(milestones, &ChangelogOption{MilestoneName: "v5"})
+	assert.Error(t, err)
+}
@@ -34,6 +34,8 @@ var (
 			HotfixBranchPrefix:          types.HotfixBranchPrefix,
 			ConflictResolveBranchPrefix: types.ConflictResolveBranchPrefix,
 			IssueBranchPrefix:           types.IssueBranchPrefix,
+			ReleaseBranchPrefix:         types.ReleaseBranchPrefix,
+			ReleaseBase:                 types.ReleaseBaseBranch,
 		},
 		OAuth2: &types.OAuth{
 			Scopes:       DefaultScopes,
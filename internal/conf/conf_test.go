@@ -5,10 +5,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/yeqown/gitlab-flow/internal/types"
 )
 
-func Example_Load() {
-	cfg, err := Load("", nil)
+func ExampleLoad() {
+	cfg := new(types.Config)
+	err := Load("", cfg, false)
 	// this would use default config and parser (toml)
 	fmt.Println(cfg, err)
 }
@@ -18,6 +21,6 @@ func Example_save() {
 }
 
 func Test_Config_Template(t *testing.T) {
-	err := Save(".", defaultConf, true)
+	err := Save(t.TempDir(), defaultConf)
 	assert.NoError(t, err)
 }
@@ -16,6 +16,29 @@ debug = {{.DebugMode}}
 # A flag which controls gitlab-flow to open browser automatically or not.
 # If set to true, gitlab-flow always open browser automatically.
 open_browser = {{.OpenBrowser}}
+{{- if .Semver }}
+
+# A flag which enables semantic version mode. If set to true, milestone title must be
+# a semantic version (e.g. v1.2.0), and `feature open --bump` / `hotfix open` could suggest
+# the next version from remote tags and milestones.
+semver = {{.Semver}}
+{{- end }}
+{{- if .GitBackend }}
+
+# The backend of local git operations (checkout, fetch, merge and etc.), "cmd" executes
+# `git` executable file, "go-git" uses go-git which does not depend on `git` executable file.
+git_backend = "{{.GitBackend}}"
+{{- end }}
+{{- if .GitRemote }}
+
+# The name of remote which branches are pushed to and pulled from, default is "origin".
+git_remote = "{{.GitRemote}}"
+{{- end }}
+{{- if .AutoStash }}
+
+# Stash local changes before switching branches or merging, and restore them after that.
+auto_stash = {{.AutoStash}}
+{{- end }}
 
 # The branch settings controls the branch name which gitlab-flow would access,
 # generate, and use. for example, while gitlab-flow is creating a feature branch,
@@ -30,4 +53,65 @@ open_browser = {{.OpenBrowser}}
   feature_branch_prefix = "{{.Branch.FeatureBranchPrefix}}"
   hotfix_branch_prefix = "{{.Branch.HotfixBranchPrefix}}"
   conflict_resolve_branch_prefix = "{{.Branch.ConflictResolveBranchPrefix}}"
-  issue_branch_prefix = "{{.Branch.IssueBranchPrefix}}"
\ No newline at end of file
+  issue_branch_prefix = "{{.Branch.IssueBranchPrefix}}"
+  # The release flow checkouts release branch (ReleaseBranchPrefix + version) from
+  # the release_base branch, empty release_base means the test branch.
+  release_branch_prefix = "{{.Branch.ReleaseBranchPrefix}}"
+  release_base = "{{.Branch.ReleaseBase}}"
+{{- if .Merge }}
+
+# The merge settings controls how merge request is merged while --auto-merge is set.
+# squash squashes commits into one, remove_source_branch removes issue branch or the
+# branch merged into master after merged.
+[merge]
+  squash = {{.Merge.Squash}}
+  remove_source_branch = {{.Merge.RemoveSourceBranch}}
+{{- end }}
+{{- define "participants" }}
+  assignees = [{{ range $i, $v := .Assignees }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}]
+  reviewers = [{{ range $i, $v := .Reviewers }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}]
+  labels = [{{ range $i, $v := .Labels }}{{ if $i }}, {{ end }}{{ printf "%q" $v }}{{ end }}]
+{{- end }}
+{{- with .Participants }}
+
+# The participants settings are default assignees, reviewers (gitlab usernames, "@me" means
+# yourself) and labels of issues and merge requests created in each step: issue (feature issues
+# and their merge requests), debug, test, release (merge requests into dev, test and master)
+# and hotfix. They could be overridden by --assignee, --reviewer and --label flags.
+{{- with .Issue }}
+
+[participants.issue]
+{{- template "participants" . }}
+{{- end }}
+{{- with .Debug }}
+
+[participants.debug]
+{{- template "participants" . }}
+{{- end }}
+{{- with .Test }}
+
+[participants.test]
+{{- template "participants" . }}
+{{- end }}
+{{- with .Release }}
+
+[participants.release]
+{{- template "participants" . }}
+{{- end }}
+{{- with .Hotfix }}
+
+[participants.hotfix]
+{{- template "participants" . }}
+{{- end }}
+{{- end }}
+{{- with .Templates }}
+
+# The templates settings are go text/template of titles and descriptions of issues and merge
+# requests. Empty description template means using .gitlab/issue_templates/{step}.md or
+# .gitlab/merge_request_templates/{step}.md (Default.md as fallback) of the repository.
+[templates]
+  issue_title = {{ printf "%q" .IssueTitle }}
+  issue_desc = {{ printf "%q" .IssueDesc }}
+  merge_request_title = {{ printf "%q" .MergeRequestTitle }}
+  merge_request_desc = {{ printf "%q" .MergeRequestDesc }}
+{{- end }}
\ No newline at end of file
@@ -14,6 +14,27 @@ gitlab_host = "{{.GitlabHost}}"
 # If set to true, gitlab-flow always open browser automatically.
 open_browser = {{.OpenBrowser}}
 
+# A flag which enables semantic version mode. If set to true, milestone title must be
+# a semantic version (e.g. v1.2.0), and `feature open --bump` / `hotfix open` could suggest
+# the next version from remote tags and milestones.
+semver = {{.Semver}}
+{{- if .GitBackend }}
+
+# The backend of local git operations (checkout, fetch, merge and etc.), "cmd" executes
+# `git` executable file, "go-git" uses go-git which does not depend on `git` executable file.
+git_backend = "{{.GitBackend}}"
+{{- end }}
+{{- if .GitRemote }}
+
+# The name of remote which branches are pushed to and pulled from, default is "origin".
+git_remote = "{{.GitRemote}}"
+{{- end }}
+{{- if .AutoStash }}
+
+# Stash local changes before switching branches or merging, and restore them after that.
+auto_stash = {{.AutoStash}}
+{{- end }}
+
 # The branch settings controls the branch name which gitlab-flow would access,
 # generate, and use. for example, while gitlab-flow is creating a feature branch,
 # it will use the branch name (FeatureBranchPrefix + feature name), and checkout
@@ -28,6 +49,19 @@ open_browser = {{.OpenBrowser}}
   hotfix_branch_prefix = "{{.Branch.HotfixBranchPrefix}}"
   conflict_resolve_branch_prefix = "{{.Branch.ConflictResolveBranchPrefix}}"
   issue_branch_prefix = "{{.Branch.IssueBranchPrefix}}"
+  # The release flow checkouts release branch (ReleaseBranchPrefix + version) from
+  # the release_base branch, empty release_base means the test branch.
+  release_branch_prefix = "{{.Branch.ReleaseBranchPrefix}}"
+  release_base = "{{.Branch.ReleaseBase}}"
+{{- if .Merge }}
+
+# The merge settings controls how merge request is merged while --auto-merge is set.
+# squash squashes commits into one, remove_source_branch removes issue branch or the
+# branch merged into master after merged.
+[merge]
+  squash = {{.Merge.Squash}}
+  remove_source_branch = {{.Merge.RemoveSourceBranch}}
+{{- end }}
 
 # OAuth2 settings, which stores the access token and refresh token for gitlab-flow
 # to access gitlab API.
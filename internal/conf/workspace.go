@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+
+	toml "github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+const defaultWorkspaceFilename = "workspace.toml"
+
+// WorkspacePath returns the default workspace file path of the project, which is
+// located in the project config directory: cwd/.gitlab-flow/workspace.toml.
+func WorkspacePath(cwd string) string {
+	return filepath.Join(cwd, defaultConfigDirectoryName, defaultWorkspaceFilename)
+}
+
+// LoadWorkspace loads workspace from file, relative paths of projects would be converted
+// into absolute paths based on cwd, and empty path means a sibling directory of cwd
+// which has the same name as the project.
+//
+//	[[projects]]
+//	name = "service-a"
+//	path = "../service-a"
+func LoadWorkspace(path, cwd string) (*types.Workspace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "open workspace file")
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	ws := new(types.Workspace)
+	if err = toml.NewDecoder(f).Decode(ws); err != nil {
+		return nil, errors.Wrap(err, "decode workspace file")
+	}
+
+	for _, p := range ws.Projects {
+		if p.Path == "" {
+			p.Path = filepath.Join("..", p.Name)
+		}
+		if !filepath.IsAbs(p.Path) {
+			p.Path = filepath.Join(cwd, p.Path)
+		}
+		if p.Name == "" {
+			p.Name = filepath.Base(p.Path)
+		}
+	}
+
+	return ws, nil
+}
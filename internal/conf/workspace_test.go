@@ -0,0 +1,78 @@
+package conf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+func Test_WorkspacePath(t *testing.T) {
+	assert.Equal(t, filepath.Join("/work/a", ".gitlab-flow", "workspace.toml"), WorkspacePath("/work/a"))
+}
+
+func Test_LoadWorkspace(t *testing.T) {
+	cwd := "/work/a"
+
+	tests := []struct {
+		name    string
+		content string
+		want    []*types.WorkspaceProject
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			content: "",
+			want:    nil,
+		},
+		{
+			name:    "relative path",
+			content: "[[projects]]\nname = \"b\"\npath = \"../service-b\"\n",
+			want:    []*types.WorkspaceProject{{Name: "b", Path: "/work/service-b"}},
+		},
+		{
+			name:    "absolute path",
+			content: "[[projects]]\nname = \"b\"\npath = \"/repos/b\"\n",
+			want:    []*types.WorkspaceProject{{Name: "b", Path: "/repos/b"}},
+		},
+		{
+			name:    "empty path means sibling directory",
+			content: "[[projects]]\nname = \"b\"\n",
+			want:    []*types.WorkspaceProject{{Name: "b", Path: "/work/b"}},
+		},
+		{
+			name:    "empty name means directory name",
+			content: "[[projects]]\npath = \"../c\"\n",
+			want:    []*types.WorkspaceProject{{Name: "c", Path: "/work/c"}},
+		},
+		{
+			name:    "invalid toml",
+			content: "[[projects]\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), defaultWorkspaceFilename)
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			ws, err := LoadWorkspace(path, cwd)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ws.Projects)
+		})
+	}
+}
+
+func Test_LoadWorkspace_not_found(t *testing.T) {
+	_, err := LoadWorkspace(filepath.Join(t.TempDir(), defaultWorkspaceFilename), "/work/a")
+	assert.Error(t, err)
+}
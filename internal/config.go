@@ -32,7 +32,7 @@ func NewConfigHelper(helperContext *ConfigHelperContext) (IConfigHelper, error)
 		globalConfig:  new(types.Config),
 		projectConfig: new(types.ProjectConfig),
 
-		gitOp: gitop.NewBasedCmd(helperContext.CWD),
+		gitOp: gitop.NewBasedCmd(helperContext.CWD, ""),
 	}
 
 	err := ch.preload()
@@ -87,6 +87,14 @@ func (f *fileConfigImpl) Config(typ types.ConfigType) types.ConfigHolder {
 		Branch:      f.projectConfig.Branch,
 		DebugMode:   f.projectConfig.DebugMode,
 		OpenBrowser: f.projectConfig.OpenBrowser,
+		Semver:      f.projectConfig.Semver,
+		Merge:       f.projectConfig.Merge,
+		GitBackend:  f.projectConfig.GitBackend,
+		GitRemote:   f.projectConfig.GitRemote,
+		AutoStash:   f.projectConfig.AutoStash,
+
+		Participants: f.projectConfig.Participants,
+		Templates:    f.projectConfig.Templates,
 	}
 
 	if f.projectConfig.Branch == nil {
@@ -100,6 +108,23 @@ func (f *fileConfigImpl) Config(typ types.ConfigType) types.ConfigHolder {
 		v := f.globalConfig.OpenBrowser
 		render.OpenBrowser = &v
 	}
+	if f.projectConfig.Semver == nil {
+		v := f.globalConfig.Semver
+		render.Semver = &v
+	}
+	if f.projectConfig.Merge == nil {
+		render.Merge = f.globalConfig.Merge
+	}
+	if f.projectConfig.GitBackend == "" {
+		render.GitBackend = f.globalConfig.GitBackend
+	}
+	if f.projectConfig.GitRemote == "" {
+		render.GitRemote = f.globalConfig.GitRemote
+	}
+	if f.projectConfig.AutoStash == nil {
+		v := f.globalConfig.AutoStash
+		render.AutoStash = &v
+	}
 
 	return render
 }
@@ -1,14 +1,42 @@
 package internal
 
 // IDash is used to display useful data of the current development stage,
-// and also to analyze user developing data.
+// and also to analyze user developing data. All methods return view models
+// which could be rendered by render package.
 type IDash interface {
 	// FeatureDetail get feature detail
-	FeatureDetail(featureBranchName string) ([]byte, error)
+	FeatureDetail(featureBranchName string) (*FeatureDetailView, error)
 
 	// MilestoneOverview get milestone detail
-	MilestoneOverview(milestoneName, branchFilter string) ([]byte, error)
+	MilestoneOverview(milestoneName, branchFilter string) (*MilestoneOverviewView, error)
 
-	// ProjectDetail display project detail， includes: project web URL
-	ProjectDetail(module string) ([]byte, error)
+	// HotfixDetail get hotfix detail, includes: issue, merge requests into master
+	// and back-merge merge requests.
+	HotfixDetail(hotfixBranchName string) (*HotfixDetailView, error)
+
+	// ReleaseDetail get release detail, includes: attached features and merge requests.
+	ReleaseDetail(releaseBranchName string) (*ReleaseDetailView, error)
+
+	// ReleaseOverview list all releases of the current project.
+	ReleaseOverview() (*ReleaseOverviewView, error)
+
+	// Changelog collects release notes of one milestone or a range of milestones.
+	Changelog(opt *ChangelogOption) (*ChangelogView, error)
+
+	// ProjectList lists all projects recorded in local database.
+	ProjectList() (*ProjectListView, error)
+
+	// FeatureList lists all features of the current project from local database.
+	FeatureList() (*FeatureListView, error)
+
+	// InFlight lists open features of all projects recorded in local database.
+	InFlight() (*InFlightView, error)
+
+	// ForProject returns IDash of another project recorded in local database, the returned
+	// IDash never opens web browser.
+	ForProject(projectID int) (IDash, error)
+
+	// ProjectDetail display project detail， includes: project web URL and statistics,
+	// statsDays is the time window of recent statistics, such as hotfix count.
+	ProjectDetail(module string, statsDays int) (*ProjectDetailView, error)
 }
@@ -1,25 +1,25 @@
 package internal
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os"
-	"strconv"
-	"text/template"
+	"sort"
+	"time"
 
-	"github.com/olekukonko/tablewriter"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	"github.com/yeqown/log"
 
 	gitop "github.com/yeqown/gitlab-flow/internal/git-operator"
+	gitlabop "github.com/yeqown/gitlab-flow/internal/gitlab-operator"
 	"github.com/yeqown/gitlab-flow/internal/repository"
-	"github.com/yeqown/gitlab-flow/internal/repository/impl"
 	"github.com/yeqown/gitlab-flow/internal/types"
 	"github.com/yeqown/gitlab-flow/pkg"
 )
 
 type dashImpl struct {
 	ctx         *types.FlowContext
+	ch          IConfigHelper
 	repo        repository.IFlowRepository
 	gitOperator gitop.IGitOperator
 }
@@ -30,41 +30,52 @@ func NewDash(ctx *types.FlowContext, ch IConfigHelper) IDash {
 		panic("can not reach")
 	}
 
+	dash, err := NewDashWithRepository(ctx, ch, NewRepository(ctx, ch))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	return dash
+}
+
+// NewDashWithRepository constructs dash with the given repository like NewDash, but returns error
+// rather than exiting, so that the caller could report it, e.g. in the terminal dashboard.
+func NewDashWithRepository(ctx *types.FlowContext, ch IConfigHelper, repo repository.IFlowRepository) (IDash, error) {
+	if ctx == nil {
+		return nil, errors.New("empty FlowContext initialized")
+	}
+
 	log.
 		WithField("context", ctx).
 		Debugf("constructing dash")
 
 	dash := dashImpl{
 		ctx:         ctx,
-		repo:        impl.NewBasedSqlite3(impl.ConnectDB(ch.Context().GlobalConfPath, ctx.IsDebug())),
-		gitOperator: gitop.NewBasedCmd(ctx.CWD()),
+		ch:          ch,
+		repo:        repo,
+		gitOperator: gitop.New(ctx.Config().GitBackend, ctx.CWD(), ctx.Config().GitRemote),
 	}
 
 	// DONE(@yeqown): need load project info from a local database.
 	if err := dash.fillContextWithProject(); err != nil {
-		log.Fatalf("could not locate project(%s): %v", ctx.ProjectName(), err)
+		return nil, errors.Wrapf(err, "could not locate project(%s)", ctx.ProjectName())
 	}
 
-	return dash
+	return dash, nil
 }
 
-var (
-	detailTpl        *template.Template
-	detailTplPattern = `
-🚗 Project's Name	:		{{.projectTitle}} (ID:{{.projectID}})
-🚕 Project's URL	:		{{.projectURL}}
-🚌 Milestone Title	:		{{.milestoneTitle}} (ID:{{.milestoneID}})
-🎯 Milestone Desc	:		{{.milestoneDesc}}
-🤡 Feature Branch	:		{{.featureBranch}}
-👽 Milestone URL	:		{{.milestoneWebURL}}
-`
-	_featureDetailTblHeader      = []string{"MR#Src", "MR#Target", "MR#WebURL", "Issue#IID", "Issue#Desc"}
-	_featureDetailIssueTblHeader = []string{"Issue#IID", "Issue#Title", "Issue#Desc", "Issue#WebURL"}
-)
+// NewGlobalDash constructs dash which is not bound to any project, so that it could be used out of
+// project directory. Only methods across projects are available, e.g. InFlight, ProjectList,
+// MilestoneOverview with milestone name, and ForProject to get dash of a project.
+func NewGlobalDash(ctx *types.FlowContext, ch IConfigHelper, repo repository.IFlowRepository) IDash {
+	ctx.InjectProject(&types.ProjectBasics{})
 
-func init() {
-	detailTpl = template.Must(
-		template.New("detail").Parse(detailTplPattern))
+	return dashImpl{
+		ctx:         ctx,
+		ch:          ch,
+		repo:        repo,
+		gitOperator: gitop.New(ctx.Config().GitBackend, ctx.CWD(), ctx.Config().GitRemote),
+	}
 }
 
 // fillContextWithProject
@@ -74,15 +85,16 @@ func (d dashImpl) fillContextWithProject() error {
 	projectName := d.ctx.ProjectName()
 
 	// get from local
-	injected, err := injectProjectIntoContext(d.repo, d.ctx, projectName, d.ctx.CWD())
+	injected, err := injectProjectIntoContext(
+		d.repo, d.ctx, parseProjectSelector(projectName), d.ctx.CWD(), d.ctx.NonInteractive())
 	if err == nil && injected {
 		return nil
 	}
 
-	// err != nil or not injected
+	// err != nil or not injected, the caller decides whether to exit or not.
 	log.
 		WithFields(log.Fields{"cwd": d.ctx.CWD(), "injected": injected}).
-		Fatalf("could not found project(%s) from local: %v", projectName, err)
+		Debugf("could not found project(%s) from local: %v", projectName, err)
 
 	return fmt.Errorf("could not found project(%s) from local: %v", projectName, err)
 }
@@ -91,7 +103,7 @@ func (d dashImpl) fillContextWithProject() error {
 // * basic information to the current milestone.
 // * all merge requests and its related issue created in the current milestone.
 // * all issues created in the current milestone with web url.
-func (d dashImpl) FeatureDetail(branchName string) ([]byte, error) {
+func (d dashImpl) FeatureDetail(branchName string) (*FeatureDetailView, error) {
 	if branchName == "" {
 		out, err := d.gitOperator.CurrentBranch()
 		if out == "" || err != nil {
@@ -159,118 +171,74 @@ func (d dashImpl) FeatureDetail(branchName string) ([]byte, error) {
 		return nil, errors.Wrap(err, "dashImpl.FeatureDetail query issues")
 	}
 
-	return d.dealDataIntoFeatureDetail(branch, milestone, issues, mrs)
+	// query all branches related to milestone to locate issue branches.
+	branches, err := d.repo.QueryBranches(&repository.BranchDO{
+		ProjectID:   d.ctx.Project().ID,
+		MilestoneID: branch.MilestoneID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.FeatureDetail query branches")
+	}
+
+	d.refreshOpenMergeRequests(mrs)
+
+	view, err := d.dealDataIntoFeatureDetail(branch, milestone, issues, mrs)
+	if err != nil {
+		return nil, err
+	}
+
+	issueBranches := make(map[int]string, len(branches))
+	for _, b := range branches {
+		if b.IssueIID != 0 {
+			issueBranches[b.IssueIID] = b.BranchName
+		}
+	}
+	for _, issue := range view.Issues {
+		issue.Branch = issueBranches[issue.IID]
+	}
+
+	return view, nil
 }
 
-// dealDataIntoFeatureDetail deal all data related to feature branch into template and tables.
+// dealDataIntoFeatureDetail deal all data related to feature branch into view.
 func (d dashImpl) dealDataIntoFeatureDetail(
 	bm *repository.BranchDO, milestone *repository.MilestoneDO,
 	issues []*repository.IssueDO, mrs []*repository.MergeRequestDO,
-) ([]byte, error) {
-	// rework issue
-	issueCache := make(map[int]*repository.IssueDO)
-	issueTblData := make([][]string, len(issues))
-	for idx, v := range issues {
-		issueCache[v.IssueIID] = v
-		issueTblData[idx] = []string{
-			strconv.Itoa(v.IssueIID),
-			v.Title,
-			v.Desc,
-			v.WebURL,
-		}
+) (*FeatureDetailView, error) {
+	view := &FeatureDetailView{
+		Project: newProjectView(d.ctx.Project()),
+		Milestone: MilestoneView{
+			ID:     milestone.MilestoneID,
+			Title:  milestone.Title,
+			Desc:   milestone.Desc,
+			WebURL: milestone.WebURL,
+		},
+		FeatureBranch: bm.BranchName,
+		MergeRequests: make([]*MergeRequestView, 0, len(mrs)),
+		Issues:        make([]*IssueView, 0, len(issues)),
 	}
 
-	mrTblData := make([][]string, len(mrs))
-	for idx, mr := range mrs {
-		issue, ok := issueCache[mr.IssueIID]
-		if !ok {
+	issueCache := make(map[int]struct{}, len(issues))
+	for _, v := range issues {
+		issueCache[v.IssueIID] = struct{}{}
+		view.Issues = append(view.Issues, newIssueView(v))
+	}
+
+	for _, mr := range mrs {
+		if _, ok := issueCache[mr.IssueIID]; !ok {
 			log.
 				WithFields(log.Fields{
 					"mergeRequestURL": mr.WebURL,
 				}).
 				Warn("no issues found with merge request")
-			issue = new(repository.IssueDO)
-		}
-
-		mrTblData[idx] = []string{
-			mr.SourceBranch,              //
-			mr.TargetBranch,              //
-			mr.WebURL,                    // MR-URL
-			strconv.Itoa(issue.IssueIID), // issue.issueIID
-			issue.Desc,                   // issue.Title
 		}
+		view.MergeRequests = append(view.MergeRequests, newMergeRequestView(mr))
 	}
 
-	log.
-		WithFields(log.Fields{"mrTblData": mrTblData}).
-		Debug("mrTblData is conducted")
-
-	data := map[string]interface{}{
-		"projectTitle":    d.ctx.Project().Name,
-		"projectID":       d.ctx.Project().ID,
-		"projectURL":      d.ctx.Project().WebURL,
-		"milestoneTitle":  milestone.Title,
-		"milestoneID":     milestone.MilestoneID,
-		"milestoneDesc":   milestone.Desc,
-		"featureBranch":   bm.BranchName,
-		"milestoneWebURL": milestone.WebURL,
-	}
-	buf := bytes.NewBuffer(nil)
-	if err := detailTpl.Execute(buf, data); err != nil {
-		return nil, errors.Wrap(err, "detailTpl.Execute")
-	}
-
-	buf.WriteString("All Merge Requests:\n")
-
-	// output all merge request into table
-	w := tablewriter.NewWriter(buf)
-	w.SetHeader(_featureDetailTblHeader)
-	w.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	w.SetAlignment(tablewriter.ALIGN_LEFT)
-	for _, row := range mrTblData {
-		// if master merge request
-		if row[1] == types.MasterBranch.String() {
-			w.Rich(row, []tablewriter.Colors{
-				{tablewriter.Bold, tablewriter.FgHiRedColor},
-				{tablewriter.Bold, tablewriter.FgHiRedColor},
-				{},
-				{tablewriter.Bold, tablewriter.FgBlackColor},
-			})
-			continue
-		}
-
-		if row[1] == types.TestBranch.String() {
-			w.Rich(row, []tablewriter.Colors{
-				{tablewriter.Bold, tablewriter.FgHiGreenColor},
-				{tablewriter.Bold, tablewriter.FgHiGreenColor},
-				{},
-				{tablewriter.Bold, tablewriter.FgBlackColor},
-			})
-			continue
-		}
-
-		w.Append(row)
-	}
-	w.Render()
-
-	buf.WriteString("All Issues:\n")
-
-	// output all issues into detail
-	w2 := tablewriter.NewWriter(buf)
-	w2.SetHeader(_featureDetailIssueTblHeader)
-	w2.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	w2.SetAlignment(tablewriter.ALIGN_LEFT)
-	w2.AppendBulk(issueTblData)
-	w2.Render()
-
-	return buf.Bytes(), nil
+	return view, nil
 }
 
-var (
-	_milestoneOverviewTblHeader = []string{"🏝Project", "MR#Action", "🏕MR#WebURL"}
-)
-
-func (d dashImpl) MilestoneOverview(milestoneName, branchFilter string) ([]byte, error) {
+func (d dashImpl) MilestoneOverview(milestoneName, branchFilter string) (*MilestoneOverviewView, error) {
 	log.
 		WithFields(log.Fields{
 			"milestoneName":      milestoneName,
@@ -317,9 +285,17 @@ func (d dashImpl) MilestoneOverview(milestoneName, branchFilter string) ([]byte,
 		}).
 		Debug("catching milestone result")
 
-	// handle data and format output
-	tblData := make([][]string, 0, 8)
+	// handle data into view
+	view := &MilestoneOverviewView{
+		Milestone:     milestoneName,
+		MergeRequests: make([]*MilestoneMergeRequestView, 0, 8),
+	}
 	for _, v := range projectMilestones {
+		// closed milestone means the feature has been finished, no need to display.
+		if v.ClosedAt != nil {
+			continue
+		}
+
 		// locate project info
 		project, err := d.repo.QueryProject(&repository.ProjectDO{ProjectID: v.ProjectID})
 		if err != nil {
@@ -328,6 +304,7 @@ func (d dashImpl) MilestoneOverview(milestoneName, branchFilter string) ([]byte,
 					"projectId": v.ProjectID,
 				}).
 				Warnf("could not locate project: %v", err)
+			project = new(repository.ProjectDO)
 		}
 
 		// catching mergeRequest of each project
@@ -346,84 +323,526 @@ func (d dashImpl) MilestoneOverview(milestoneName, branchFilter string) ([]byte,
 				Warnf("could not locate project merge request: %v", err)
 		}
 
-		// insert all merge requests of current project into tblData
+		// insert all merge requests of current project into view
 		for _, mr := range mrs {
-			tblData = append(tblData, []string{
-				project.ProjectName,
-				fmt.Sprintf("%s => %s", mr.SourceBranch, mr.TargetBranch),
-				mr.WebURL,
+			view.MergeRequests = append(view.MergeRequests, &MilestoneMergeRequestView{
+				Project:          project.ProjectName,
+				MergeRequestView: newMergeRequestView(mr),
 			})
 		}
 	}
 
-	buf := bytes.NewBuffer(nil)
-	w := tablewriter.NewWriter(buf)
-	w.SetHeader(_milestoneOverviewTblHeader)
-	w.SetColumnColor(
-		tablewriter.Colors{tablewriter.Bold, tablewriter.FgGreenColor},
-		tablewriter.Colors{},
-		tablewriter.Colors{},
-	)
-	w.SetRowLine(true)
-	w.SetAutoMergeCells(true)
-	w.AppendBulk(tblData)
-	w.Render()
+	return view, nil
+}
 
-	return buf.Bytes(), nil
+// HotfixDetail get hotfix detail of the current project:
+// * basic information of the hotfix issue.
+// * all merge requests of the hotfix, includes back-merge and conflict-resolve merge requests.
+func (d dashImpl) HotfixDetail(hotfixBranchName string) (*HotfixDetailView, error) {
+	if hotfixBranchName == "" {
+		hotfixBranchName, _ = d.gitOperator.CurrentBranch()
+	}
+	if hotfixBranchName == "" {
+		return nil, errors.New("hotfix branch could not be empty")
+	}
+	hotfixBranchName = genHotfixBranchName(hotfixBranchName)
+	projectID := d.ctx.Project().ID
+
+	issue, err := d.repo.QueryIssue(&repository.IssueDO{
+		ProjectID:     projectID,
+		RelatedBranch: hotfixBranchName,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not locate hotfix issue:"+hotfixBranchName)
+	}
+
+	// all merge requests close the hotfix issue, includes back-merge and conflict-resolve merge requests.
+	mrs, err := d.repo.QueryMergeRequests(&repository.MergeRequestDO{
+		ProjectID: projectID,
+		IssueIID:  issue.IssueIID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.HotfixDetail query mergeRequest")
+	}
+
+	view := &HotfixDetailView{
+		Project:       newProjectView(d.ctx.Project()),
+		HotfixBranch:  hotfixBranchName,
+		Issue:         newIssueView(issue),
+		MergeRequests: make([]*MergeRequestView, 0, len(mrs)),
+	}
+	for _, mr := range mrs {
+		view.MergeRequests = append(view.MergeRequests, newMergeRequestView(mr))
+	}
+
+	return view, nil
+}
+
+// ReleaseDetail get release detail of the current project:
+// * basic information of the release.
+// * all features attached to the release.
+// * all merge requests into the release branch and from the release branch.
+func (d dashImpl) ReleaseDetail(releaseBranchName string) (*ReleaseDetailView, error) {
+	if releaseBranchName == "" {
+		releaseBranchName, _ = d.gitOperator.CurrentBranch()
+		if !isReleaseName(releaseBranchName) {
+			return nil, errors.Wrap(errInvalidReleaseName, "dashImpl.ReleaseDetail.CurrentBranch")
+		}
+	}
+	releaseBranchName = genReleaseBranchName(releaseBranchName)
+	projectID := d.ctx.Project().ID
+
+	release, err := d.repo.QueryRelease(&repository.ReleaseDO{
+		ProjectID:  projectID,
+		BranchName: releaseBranchName,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not locate release:"+releaseBranchName)
+	}
+
+	attached, err := d.repo.QueryReleaseMilestones(&repository.ReleaseMilestoneDO{
+		ProjectID:     projectID,
+		ReleaseBranch: releaseBranchName,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.ReleaseDetail query attached features")
+	}
+
+	view := &ReleaseDetailView{
+		Project:       newProjectView(d.ctx.Project()),
+		Version:       release.Version,
+		Desc:          release.Desc,
+		ReleaseBranch: release.BranchName,
+		BaseBranch:    release.BaseBranch,
+		Tag:           release.TagName,
+		Features:      make([]*ReleaseFeatureView, 0, len(attached)),
+	}
+	for _, v := range attached {
+		milestone, err := d.repo.QueryMilestone(&repository.MilestoneDO{
+			ProjectID:   projectID,
+			MilestoneID: v.MilestoneID,
+		})
+		if err != nil {
+			log.
+				WithFields(log.Fields{"milestoneID": v.MilestoneID}).
+				Warnf("could not locate milestone: %v", err)
+			milestone = new(repository.MilestoneDO)
+		}
+
+		view.Features = append(view.Features, &ReleaseFeatureView{
+			FeatureBranch: v.FeatureBranch,
+			Milestone: MilestoneView{
+				ID:     milestone.MilestoneID,
+				Title:  milestone.Title,
+				Desc:   milestone.Desc,
+				WebURL: milestone.WebURL,
+			},
+		})
+	}
+
+	// merge requests into the release branch and from the release branch.
+	intoMRs, err := d.repo.QueryMergeRequests(&repository.MergeRequestDO{
+		ProjectID:    projectID,
+		TargetBranch: releaseBranchName,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.ReleaseDetail query mergeRequest")
+	}
+	fromMRs, err := d.repo.QueryMergeRequests(&repository.MergeRequestDO{
+		ProjectID:    projectID,
+		SourceBranch: releaseBranchName,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.ReleaseDetail query mergeRequest")
+	}
+
+	view.MergeRequests = make([]*MergeRequestView, 0, len(intoMRs)+len(fromMRs))
+	for _, mr := range append(intoMRs, fromMRs...) {
+		view.MergeRequests = append(view.MergeRequests, newMergeRequestView(mr))
+	}
+
+	return view, nil
+}
+
+// ReleaseOverview list all releases of the current project, closed releases
+// would be displayed with their tags.
+func (d dashImpl) ReleaseOverview() (*ReleaseOverviewView, error) {
+	projectID := d.ctx.Project().ID
+	releases, err := d.repo.QueryReleases(&repository.ReleaseDO{ProjectID: projectID})
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.ReleaseOverview query releases")
+	}
+
+	view := &ReleaseOverviewView{Releases: make([]*ReleaseView, 0, len(releases))}
+	for _, v := range releases {
+		attached, err := d.repo.QueryReleaseMilestones(&repository.ReleaseMilestoneDO{
+			ProjectID:     projectID,
+			ReleaseBranch: v.BranchName,
+		})
+		if err != nil {
+			log.
+				WithFields(log.Fields{"releaseBranch": v.BranchName}).
+				Warnf("could not query attached features: %v", err)
+		}
+
+		features := make([]string, 0, len(attached))
+		for _, m := range attached {
+			features = append(features, m.FeatureBranch)
+		}
+
+		view.Releases = append(view.Releases, &ReleaseView{
+			Branch:     v.BranchName,
+			BaseBranch: v.BaseBranch,
+			Tag:        v.TagName,
+			Features:   features,
+		})
+	}
+
+	return view, nil
 }
 
-func (d dashImpl) ProjectDetail(module string) ([]byte, error) {
+// Changelog renders markdown release notes of one milestone or a range of milestones
+// from local data, issues are grouped by conventional-commit prefix or label.
+func (d dashImpl) Changelog(opt *ChangelogOption) (*ChangelogView, error) {
+	if opt == nil {
+		opt = &ChangelogOption{}
+	}
+	log.
+		WithFields(log.Fields{
+			"option": opt,
+		}).
+		Debug("Changelog called")
 
+	if opt.MilestoneName == "" && opt.FromMilestone == "" {
+		// locate milestone of current branch just like MilestoneOverview.
+		featureBranchName, _ := d.gitOperator.CurrentBranch()
+		featureBranchName = genFeatureBranchName(featureBranchName)
+		milestone, err := d.repo.QueryMilestoneByBranchName(d.ctx.Project().ID, featureBranchName)
+		if err != nil {
+			return nil, errors.Wrap(err, "you must specify a milestone name or range, or "+
+				"sure you are using a branch which could get milestone")
+		}
+		opt.MilestoneName = milestone.Title
+	}
+
+	projectID := d.ctx.Project().ID
+	milestones, err := d.repo.QueryMilestones(&repository.MilestoneDO{ProjectID: projectID})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not query milestones")
+	}
+	if milestones, err = selectChangelogMilestones(milestones, opt); err != nil {
+		return nil, err
+	}
+
+	sections := make([]*ChangelogSection, 0, len(milestones))
+	for _, m := range milestones {
+		issues, err := d.repo.QueryIssues(&repository.IssueDO{
+			ProjectID:   projectID,
+			MilestoneID: m.MilestoneID,
+		})
+		if err != nil {
+			log.
+				WithFields(log.Fields{"milestoneID": m.MilestoneID}).
+				Warnf("could not query issues: %v", err)
+		}
+		mrs, err := d.repo.QueryMergeRequests(&repository.MergeRequestDO{
+			ProjectID:   projectID,
+			MilestoneID: m.MilestoneID,
+		})
+		if err != nil {
+			log.
+				WithFields(log.Fields{"milestoneID": m.MilestoneID}).
+				Warnf("could not query merge requests: %v", err)
+		}
+
+		section := &ChangelogSection{
+			Title:  m.Title,
+			Desc:   m.Desc,
+			WebURL: m.WebURL,
+			Groups: groupChangelogEntries(issues, mrs, opt.GroupBy),
+		}
+		if m.ClosedAt != nil {
+			section.Date = m.ClosedAt.Format("2006-01-02")
+		}
+		sections = append(sections, section)
+	}
+
+	return &ChangelogView{Title: opt.Title, Sections: sections}, nil
+}
+
+// ProjectList lists all projects recorded in local database.
+func (d dashImpl) ProjectList() (*ProjectListView, error) {
+	projects, err := d.repo.QueryProjects(&repository.ProjectDO{})
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.ProjectList query projects")
+	}
+
+	view := &ProjectListView{Projects: make([]*ProjectItemView, 0, len(projects))}
+	for _, p := range projects {
+		view.Projects = append(view.Projects, &ProjectItemView{
+			ProjectView: ProjectView{ID: p.ProjectID, Name: p.ProjectName, WebURL: p.WebURL},
+			LocalDir:    p.LocalDir,
+		})
+	}
+
+	return view, nil
+}
+
+// FeatureList lists all features of the current project, open features are listed first,
+// and newer features are listed before older ones.
+func (d dashImpl) FeatureList() (*FeatureListView, error) {
+	features, err := d.featureItems(d.ctx.Project().ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.FeatureList")
+	}
+
+	return &FeatureListView{Project: newProjectView(d.ctx.Project()), Features: features}, nil
+}
+
+// InFlight lists open features of all projects, projects without open features are omitted.
+func (d dashImpl) InFlight() (*InFlightView, error) {
+	projects, err := d.repo.QueryProjects(&repository.ProjectDO{})
+	if err != nil {
+		return nil, errors.Wrap(err, "dashImpl.InFlight query projects")
+	}
+
+	view := &InFlightView{Projects: make([]*InFlightProjectView, 0, len(projects))}
+	for _, p := range projects {
+		features, err := d.featureItems(p.ProjectID)
+		if err != nil {
+			return nil, errors.Wrap(err, "dashImpl.InFlight")
+		}
+
+		features = lo.Filter(features, func(f *FeatureItemView, _ int) bool { return !f.Closed })
+		if len(features) == 0 {
+			continue
+		}
+		view.Projects = append(view.Projects, &InFlightProjectView{
+			Project:  ProjectView{ID: p.ProjectID, Name: p.ProjectName, WebURL: p.WebURL},
+			Features: features,
+		})
+	}
+
+	return view, nil
+}
+
+// featureItems queries milestones and feature branches of the project, open features are
+// listed first, and newer features are listed before older ones.
+func (d dashImpl) featureItems(projectID int) ([]*FeatureItemView, error) {
+	milestones, err := d.repo.QueryMilestones(&repository.MilestoneDO{ProjectID: projectID})
+	if err != nil {
+		return nil, errors.Wrap(err, "query milestones")
+	}
+	branches, err := d.repo.QueryBranches(&repository.BranchDO{ProjectID: projectID})
+	if err != nil {
+		return nil, errors.Wrap(err, "query branches")
+	}
+
+	featureBranches := make(map[int]string, len(milestones))
+	for _, b := range branches {
+		if b.IssueIID == 0 && isFeatureName(b.BranchName) {
+			featureBranches[b.MilestoneID] = b.BranchName
+		}
+	}
+
+	sort.SliceStable(milestones, func(i, j int) bool {
+		a, b := milestones[i], milestones[j]
+		if (a.ClosedAt == nil) != (b.ClosedAt == nil) {
+			return a.ClosedAt == nil
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+
+	features := make([]*FeatureItemView, 0, len(milestones))
+	for _, m := range milestones {
+		features = append(features, &FeatureItemView{
+			Milestone:     MilestoneView{ID: m.MilestoneID, Title: m.Title, Desc: m.Desc, WebURL: m.WebURL},
+			FeatureBranch: featureBranches[m.MilestoneID],
+			GroupID:       m.GroupID,
+			Closed:        m.ClosedAt != nil,
+		})
+	}
+
+	return features, nil
+}
+
+// ForProject returns dash of the project recorded in local database, it shares the repository
+// with d, and never opens web browser since it's used to serve data to web browsers.
+func (d dashImpl) ForProject(projectID int) (IDash, error) {
+	project, err := d.repo.QueryProject(&repository.ProjectDO{ProjectID: projectID})
+	if err != nil {
+		return nil, errors.Wrapf(err, "dashImpl.ForProject query project(%d)", projectID)
+	}
+
+	ctx := d.ctx.WithProject(&types.ProjectBasics{
+		ID:     project.ProjectID,
+		Name:   project.ProjectName,
+		WebURL: project.WebURL,
+	}, project.LocalDir)
+	ctx.DisableOpenBrowser()
+
+	return dashImpl{
+		ctx:         ctx,
+		ch:          d.ch,
+		repo:        d.repo,
+		gitOperator: gitop.New(ctx.Config().GitBackend, project.LocalDir, ctx.Config().GitRemote),
+	}, nil
+}
+
+// ProjectDetail returns links of project modules, and opens them in web browser if needed.
+// Statistics of the project are only collected while module is "all" or "stats", "links" means
+// all links without statistics.
+func (d dashImpl) ProjectDetail(module string, statsDays int) (*ProjectDetailView, error) {
 	var (
 		projectName = d.ctx.Project().Name
 
-		homeURL   = d.ctx.Project().WebURL
-		branchURL = genProjectURL(d.ctx.Project().WebURL, "/-/branches")
-		tagURL    = genProjectURL(d.ctx.Project().WebURL, "/-/tags")
-		commitURL = genProjectURL(d.ctx.Project().WebURL, "/commits/master")
+		home   = &LinkView{Title: projectName, URL: d.ctx.Project().WebURL}
+		branch = &LinkView{Title: "Branches Link", URL: genProjectURL(d.ctx.Project().WebURL, "/-/branches")}
+		tag    = &LinkView{Title: "Tags Link", URL: genProjectURL(d.ctx.Project().WebURL, "/-/tags")}
+		commit = &LinkView{Title: "Commits Link", URL: genProjectURL(d.ctx.Project().WebURL, "/commits/master")}
 	)
 
+	view := &ProjectDetailView{Project: newProjectView(d.ctx.Project())}
 	switch module {
 	case "home":
-		d.printAndOpenBrowser(projectName, homeURL)
+		view.Links = []*LinkView{home}
 	case "branch":
-		d.printAndOpenBrowser("Branch Link", branchURL)
+		view.Links = []*LinkView{branch}
 	case "tag":
-		d.printAndOpenBrowser("Tags Link", tagURL)
+		view.Links = []*LinkView{tag}
 	case "commit":
-		d.printAndOpenBrowser("Commits Link", commitURL)
+		view.Links = []*LinkView{commit}
+	case "stats":
+		// only stats
 	default:
-		d.printAndOpenBrowser(projectName, homeURL)
-		d.printAndOpenBrowser("Branches Link", branchURL)
-		d.printAndOpenBrowser("Tags Link", tagURL)
-		d.printAndOpenBrowser("Commits Link", commitURL)
+		view.Links = []*LinkView{home, branch, tag, commit}
 	}
 
-	// TODO: print stats
+	d.openBrowser(view.Links...)
+
+	switch module {
+	case "all", "stats":
+		stats, err := d.projectStats(statsDays)
+		if err != nil {
+			return nil, errors.Wrap(err, "dashImpl.ProjectDetail")
+		}
+		view.Stats = stats
+	}
 
-	return nil, nil
+	return view, nil
 }
 
-func genProjectURL(base, suffix string) string {
-	return base + suffix
+// projectStats collects statistics of the current project from local database and gitlab.
+func (d dashImpl) projectStats(days int) (*ProjectStatsView, error) {
+	var (
+		projectID = d.ctx.Project().ID
+		now       = time.Now()
+		stats     = &ProjectStatsView{Days: days}
+	)
+
+	milestones, err := d.repo.QueryMilestones(&repository.MilestoneDO{ProjectID: projectID})
+	if err != nil {
+		return nil, errors.Wrap(err, "query milestones")
+	}
+	issues, err := d.repo.QueryIssues(&repository.IssueDO{ProjectID: projectID})
+	if err != nil {
+		return nil, errors.Wrap(err, "query issues")
+	}
+	mrs, err := d.repo.QueryMergeRequests(&repository.MergeRequestDO{ProjectID: projectID})
+	if err != nil {
+		return nil, errors.Wrap(err, "query merge requests")
+	}
+	fillLocalProjectStats(stats, now, milestones, issues, mrs)
+
+	// statistics from gitlab are best-effort, local statistics are still useful without them.
+	if err = d.remoteProjectStats(stats, now); err != nil {
+		log.
+			WithFields(log.Fields{"projectID": projectID, "error": err}).
+			Warnf("could not collect statistics from gitlab, they are left empty")
+	}
+
+	log.
+		WithFields(log.Fields{"projectID": projectID, "stats": stats}).
+		Debug("dashImpl.projectStats done")
+
+	return stats, nil
 }
 
-// printAndOpenBrowser print WebURL into stdout and open web browser.
-func (d dashImpl) printAndOpenBrowser(title string, urls ...string) {
-	if len(title) == 0 && len(urls) == 0 {
-		log.Warn("could not execute printAndOpenBrowser with empty title and url")
+// refreshOpenMergeRequests refreshes state and pipeline status of open merge requests from gitlab,
+// since local records are only updated by flow commands. It's best-effort, local records are
+// used if gitlab could not be reached.
+func (d dashImpl) refreshOpenMergeRequests(mrs []*repository.MergeRequestDO) {
+	open := lo.Filter(mrs, func(mr *repository.MergeRequestDO, _ int) bool {
+		return mr.State == "" || mr.State == repository.MergeRequestStateOpened
+	})
+	if len(open) == 0 {
 		return
 	}
 
-	for _, url := range urls {
-		_, err1 := fmt.Fprintf(os.Stdout, _printTpl, title, url)
-		if d.ctx.ShouldOpenBrowser() {
-			err2 := pkg.OpenBrowser(url)
+	accessToken, err := renewOAuthAccessToken(d.ctx, d.ch)
+	if err != nil {
+		log.
+			WithFields(log.Fields{"error": err}).
+			Warnf("could not refresh merge requests from gitlab, local records are used")
+		return
+	}
+
+	gitlabOperator := gitlabop.NewGitlabOperator(accessToken, d.ctx.APIEndpoint())
+	ctx := context.Background()
+	for _, mr := range open {
+		if err = refreshMergeRequest(ctx, gitlabOperator, d.repo, mr); err != nil {
 			log.
-				WithFields(log.Fields{"printErr": err1, "openBrowserError": err2}).
-				Debugf("printAndOpenBrowser")
+				WithFields(log.Fields{"mergeRequest": mr.WebURL, "error": err}).
+				Warnf("could not refresh merge request from gitlab, local record is used")
 		}
+	}
+}
+
+// remoteProjectStats collects statistics of the current project from gitlab.
+func (d dashImpl) remoteProjectStats(stats *ProjectStatsView, now time.Time) error {
+	// dash reads local data only except stats, so gitlab operator is created on demand.
+	accessToken, err := renewOAuthAccessToken(d.ctx, d.ch)
+	if err != nil {
+		return err
+	}
+	gitlabOperator := gitlabop.NewGitlabOperator(accessToken, d.ctx.APIEndpoint())
+	ctx := context.Background()
+	projectID := d.ctx.Project().ID
+
+	remoteMilestones, err := gitlabop.Collect(gitlabOperator.ListMilestones(ctx, &gitlabop.ListMilestoneRequest{
+		ProjectID: projectID,
+		State:     gitlabop.MilestoneStateActive,
+	}))
+	if err != nil {
+		return errors.Wrap(err, "list milestones from gitlab")
+	}
+	openMRs, err := gitlabop.Collect(gitlabOperator.ListMergeRequests(ctx, &gitlabop.ListMergeRequestRequest{
+		ProjectID: projectID,
+		State:     gitlabop.MergeRequestStateOpened,
+	}))
+	if err != nil {
+		return errors.Wrap(err, "list merge requests from gitlab")
+	}
+	fillRemoteProjectStats(stats, now, len(remoteMilestones), openMRs)
+
+	return nil
+}
 
+func genProjectURL(base, suffix string) string {
+	return base + suffix
+}
+
+// openBrowser opens links in web browser if it's enabled.
+func (d dashImpl) openBrowser(links ...*LinkView) {
+	if !d.ctx.ShouldOpenBrowser() {
+		return
+	}
+
+	for _, link := range links {
+		err := pkg.OpenBrowser(link.URL)
+		log.
+			WithFields(log.Fields{"url": link.URL, "openBrowserError": err}).
+			Debugf("openBrowser")
 	}
 }
 
@@ -437,15 +856,16 @@ type projectInjectIntoContextHelper interface {
 
 // injectProjectIntoContext query project from local persistence repository and inject into context.
 //
-// First, query project from local repository with projectName,
+// First, query project from local repository with project ID or name of selector,
 // if not found, then query with localDir.
 //
 // Then, let user choose one project from matched projects if there are more than one.
 //
 // Finally, inject project into context.
 func injectProjectIntoContext(
-	q projectQueryHelper, injector projectInjectIntoContextHelper, projectName, localDir string) (bool, error) {
-	filter := &repository.ProjectDO{ProjectName: projectName}
+	q projectQueryHelper, injector projectInjectIntoContextHelper,
+	sel projectSelector, localDir string, nonInteractive bool) (bool, error) {
+	filter := &repository.ProjectDO{ProjectName: sel.Name, ProjectID: sel.ID}
 	tries := 1
 
 query:
@@ -472,7 +892,7 @@ query:
 
 	if len(projects) != 0 {
 		// let user choose one
-		matched, err2 := chooseOneProjectInteractively(projects)
+		matched, err2 := chooseOneProjectInteractively(projects, sel, nonInteractive)
 		if err2 != nil {
 			return true, errors.Wrap(err2, "injectProjectIntoContext.chooseOneProjectInteractively")
 		}
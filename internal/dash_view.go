@@ -0,0 +1,679 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	gitlabop "github.com/yeqown/gitlab-flow/internal/gitlab-operator"
+	"github.com/yeqown/gitlab-flow/internal/render"
+	"github.com/yeqown/gitlab-flow/internal/repository"
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+// View models are returned by IDash, they could be rendered into table, json, yaml or markdown
+// by render package. Sections of view model decide how it looks like in table and markdown format.
+
+// ProjectView is the basic information of project.
+type ProjectView struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	WebURL string `json:"web_url"`
+}
+
+func newProjectView(p *types.ProjectBasics) ProjectView {
+	return ProjectView{ID: p.ID, Name: p.Name, WebURL: p.WebURL}
+}
+
+// MilestoneView is the basic information of milestone.
+type MilestoneView struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Desc   string `json:"desc"`
+	WebURL string `json:"web_url"`
+}
+
+// IssueView is the basic information of issue, Branch is the issue branch if it's known.
+type IssueView struct {
+	IID    int    `json:"iid"`
+	Title  string `json:"title"`
+	Desc   string `json:"desc"`
+	WebURL string `json:"web_url"`
+	Branch string `json:"branch,omitempty"`
+}
+
+func newIssueView(issue *repository.IssueDO) *IssueView {
+	return &IssueView{IID: issue.IssueIID, Title: issue.Title, Desc: issue.Desc, WebURL: issue.WebURL}
+}
+
+// MergeRequestView is the basic information of merge request, IssueIID is the issue which
+// the merge request closes.
+type MergeRequestView struct {
+	IID          int    `json:"iid"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	WebURL       string `json:"web_url"`
+	State        string `json:"state"`
+	IssueIID     int    `json:"issue_iid,omitempty"`
+	// PipelineStatus is the pipeline status recorded in local, it's refreshed by `feature pipeline`.
+	PipelineStatus string `json:"pipeline_status,omitempty"`
+	// AutoMergeError is the reason why merge request was not merged automatically.
+	AutoMergeError string `json:"auto_merge_error,omitempty"`
+	Draft          bool   `json:"draft,omitempty"`
+	// Assignees and Reviewers are usernames chosen while the merge request was created.
+	Assignees []string `json:"assignees,omitempty"`
+	Reviewers []string `json:"reviewers,omitempty"`
+}
+
+func newMergeRequestView(mr *repository.MergeRequestDO) *MergeRequestView {
+	return &MergeRequestView{
+		IID:          mr.MergeRequestIID,
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		WebURL:       mr.WebURL,
+		State:        mr.State,
+		IssueIID:     mr.IssueIID,
+
+		PipelineStatus: mr.PipelineStatus,
+		AutoMergeError: mr.AutoMergeError,
+		Draft:          mr.Draft,
+		Assignees:      mr.AssigneeList(),
+		Reviewers:      mr.ReviewerList(),
+	}
+}
+
+// ownerText shows assignees and reviewers of merge request, e.g. "alice (review: bob)".
+func (v *MergeRequestView) ownerText() string {
+	owner := strings.Join(v.Assignees, ",")
+	if len(v.Reviewers) != 0 {
+		owner = strings.TrimSpace(owner + " (review: " + strings.Join(v.Reviewers, ",") + ")")
+	}
+
+	return textOrDash(owner)
+}
+
+// stateText returns the state of merge request to display, unknown state
+// would be displayed as "-".
+func (v *MergeRequestView) stateText() string {
+	if v.State == "" {
+		return "-"
+	}
+	if v.State != repository.MergeRequestStateOpened {
+		return v.State
+	}
+
+	switch {
+	case v.Draft:
+		return v.State + " (draft)"
+	case v.AutoMergeError != "":
+		return v.State + " (auto merge failed: " + v.AutoMergeError + ")"
+	}
+
+	return v.State
+}
+
+// highlightTargetBranch highlights merge requests into master and test branch,
+// targetIdx is the index of target branch in the row.
+func highlightTargetBranch(targetIdx int) func(row []string) []tablewriter.Colors {
+	return func(row []string) []tablewriter.Colors {
+		var color int
+		switch row[targetIdx] {
+		case types.MasterBranch.String():
+			color = tablewriter.FgHiRedColor
+		case types.TestBranch.String():
+			color = tablewriter.FgHiGreenColor
+		default:
+			return nil
+		}
+
+		colors := make([]tablewriter.Colors, targetIdx+1)
+		for idx := range colors {
+			colors[idx] = tablewriter.Colors{tablewriter.Bold, color}
+		}
+		return colors
+	}
+}
+
+func projectFields(p ProjectView) []render.Field {
+	return []render.Field{
+		{Name: "🚗 Project's Name", Value: fmt.Sprintf("%s (ID:%d)", p.Name, p.ID)},
+		{Name: "🚕 Project's URL", Value: p.WebURL},
+	}
+}
+
+// FeatureDetailView is the detail of feature, includes milestone, merge requests and issues.
+type FeatureDetailView struct {
+	Project       ProjectView         `json:"project"`
+	Milestone     MilestoneView       `json:"milestone"`
+	FeatureBranch string              `json:"feature_branch"`
+	MergeRequests []*MergeRequestView `json:"merge_requests"`
+	Issues        []*IssueView        `json:"issues"`
+}
+
+var (
+	_featureDetailTblHeader = []string{
+		"MR#Src", "MR#Target", "MR#WebURL", "MR#State", "MR#Pipeline", "MR#Owner", "Issue#IID", "Issue#Desc"}
+	_featureDetailIssueTblHeader = []string{"Issue#IID", "Issue#Title", "Issue#Desc", "Issue#WebURL"}
+)
+
+func (v *FeatureDetailView) Sections() []*render.Section {
+	issueDesc := make(map[int]string, len(v.Issues))
+	issueRows := make([][]string, 0, len(v.Issues))
+	for _, issue := range v.Issues {
+		issueDesc[issue.IID] = issue.Desc
+		issueRows = append(issueRows, []string{strconv.Itoa(issue.IID), issue.Title, issue.Desc, issue.WebURL})
+	}
+
+	mrRows := make([][]string, 0, len(v.MergeRequests))
+	for _, mr := range v.MergeRequests {
+		mrRows = append(mrRows, []string{
+			mr.SourceBranch, mr.TargetBranch, mr.WebURL, mr.stateText(), textOrDash(mr.PipelineStatus),
+			mr.ownerText(), strconv.Itoa(mr.IssueIID), issueDesc[mr.IssueIID],
+		})
+	}
+
+	return []*render.Section{
+		{
+			Fields: append(projectFields(v.Project),
+				render.Field{Name: "🚌 Milestone Title", Value: fmt.Sprintf("%s (ID:%d)", v.Milestone.Title, v.Milestone.ID)},
+				render.Field{Name: "🎯 Milestone Desc", Value: v.Milestone.Desc},
+				render.Field{Name: "🤡 Feature Branch", Value: v.FeatureBranch},
+				render.Field{Name: "👽 Milestone URL", Value: v.Milestone.WebURL},
+			),
+		},
+		{
+			Title:     "All Merge Requests",
+			Header:    _featureDetailTblHeader,
+			Rows:      mrRows,
+			RowColors: highlightTargetBranch(1),
+		},
+		{
+			Title:  "All Issues",
+			Header: _featureDetailIssueTblHeader,
+			Rows:   issueRows,
+		},
+	}
+}
+
+// MilestoneMergeRequestView is a merge request of milestone in a project.
+type MilestoneMergeRequestView struct {
+	Project string `json:"project"`
+	*MergeRequestView
+}
+
+// MilestoneOverviewView is merge requests of a milestone in all projects.
+type MilestoneOverviewView struct {
+	Milestone     string                       `json:"milestone"`
+	MergeRequests []*MilestoneMergeRequestView `json:"merge_requests"`
+}
+
+var _milestoneOverviewTblHeader = []string{"🏝Project", "MR#Action", "🏕MR#WebURL"}
+
+func (v *MilestoneOverviewView) Sections() []*render.Section {
+	rows := make([][]string, 0, len(v.MergeRequests))
+	for _, mr := range v.MergeRequests {
+		rows = append(rows, []string{
+			mr.Project,
+			fmt.Sprintf("%s => %s", mr.SourceBranch, mr.TargetBranch),
+			mr.WebURL,
+		})
+	}
+
+	return []*render.Section{
+		{
+			Header: _milestoneOverviewTblHeader,
+			Rows:   rows,
+			ColumnColors: []tablewriter.Colors{
+				{tablewriter.Bold, tablewriter.FgGreenColor},
+				{},
+				{},
+			},
+			MergeCells: true,
+			RowLine:    true,
+		},
+	}
+}
+
+// HotfixDetailView is the detail of hotfix, includes the issue and all merge requests.
+type HotfixDetailView struct {
+	Project       ProjectView         `json:"project"`
+	HotfixBranch  string              `json:"hotfix_branch"`
+	Issue         *IssueView          `json:"issue"`
+	MergeRequests []*MergeRequestView `json:"merge_requests"`
+}
+
+var _mergeRequestTblHeader = []string{"MR#Src", "MR#Target", "MR#WebURL", "MR#State"}
+
+func mergeRequestRows(mrs []*MergeRequestView) [][]string {
+	rows := make([][]string, 0, len(mrs))
+	for _, mr := range mrs {
+		rows = append(rows, []string{mr.SourceBranch, mr.TargetBranch, mr.WebURL, mr.stateText()})
+	}
+
+	return rows
+}
+
+func (v *HotfixDetailView) Sections() []*render.Section {
+	return []*render.Section{
+		{
+			Fields: append(projectFields(v.Project),
+				render.Field{Name: "🚑 Hotfix Branch", Value: v.HotfixBranch},
+				render.Field{Name: "🎯 Issue Title", Value: fmt.Sprintf("%s (IID:%d)", v.Issue.Title, v.Issue.IID)},
+				render.Field{Name: "👽 Issue URL", Value: v.Issue.WebURL},
+			),
+		},
+		{
+			Title:     "All Merge Requests",
+			Header:    _mergeRequestTblHeader,
+			Rows:      mergeRequestRows(v.MergeRequests),
+			RowColors: highlightTargetBranch(1),
+		},
+	}
+}
+
+// ReleaseFeatureView is a feature attached to release.
+type ReleaseFeatureView struct {
+	FeatureBranch string        `json:"feature_branch"`
+	Milestone     MilestoneView `json:"milestone"`
+}
+
+// ReleaseDetailView is the detail of release, includes attached features and merge requests.
+type ReleaseDetailView struct {
+	Project       ProjectView           `json:"project"`
+	Version       string                `json:"version"`
+	Desc          string                `json:"desc"`
+	ReleaseBranch string                `json:"release_branch"`
+	BaseBranch    string                `json:"base_branch"`
+	Tag           string                `json:"tag"`
+	Features      []*ReleaseFeatureView `json:"features"`
+	MergeRequests []*MergeRequestView   `json:"merge_requests"`
+}
+
+var _releaseFeatureTblHeader = []string{"Feature#Branch", "Milestone#Title", "Milestone#WebURL"}
+
+func (v *ReleaseDetailView) Sections() []*render.Section {
+	featureRows := make([][]string, 0, len(v.Features))
+	for _, f := range v.Features {
+		featureRows = append(featureRows, []string{f.FeatureBranch, f.Milestone.Title, f.Milestone.WebURL})
+	}
+
+	return []*render.Section{
+		{
+			Fields: append(projectFields(v.Project),
+				render.Field{Name: "🚀 Release Version", Value: v.Version},
+				render.Field{Name: "🎯 Release Desc", Value: v.Desc},
+				render.Field{Name: "🤡 Release Branch", Value: fmt.Sprintf("%s (from %s)", v.ReleaseBranch, v.BaseBranch)},
+				render.Field{Name: "🏷 Release Tag", Value: textOrDash(v.Tag)},
+			),
+		},
+		{
+			Title:  "All Features",
+			Header: _releaseFeatureTblHeader,
+			Rows:   featureRows,
+		},
+		{
+			Title:     "All Merge Requests",
+			Header:    _mergeRequestTblHeader,
+			Rows:      mergeRequestRows(v.MergeRequests),
+			RowColors: highlightTargetBranch(1),
+		},
+	}
+}
+
+// ReleaseView is a release of project.
+type ReleaseView struct {
+	Branch     string   `json:"branch"`
+	BaseBranch string   `json:"base_branch"`
+	Tag        string   `json:"tag"`
+	Features   []string `json:"features"`
+}
+
+// ReleaseOverviewView is all releases of project.
+type ReleaseOverviewView struct {
+	Releases []*ReleaseView `json:"releases"`
+}
+
+var _releaseOverviewTblHeader = []string{"Release#Branch", "Release#Base", "Release#Tag", "Release#Features"}
+
+func (v *ReleaseOverviewView) Sections() []*render.Section {
+	rows := make([][]string, 0, len(v.Releases))
+	for _, r := range v.Releases {
+		rows = append(rows, []string{r.Branch, r.BaseBranch, textOrDash(r.Tag), strings.Join(r.Features, "\n")})
+	}
+
+	return []*render.Section{
+		{
+			Header:  _releaseOverviewTblHeader,
+			Rows:    rows,
+			RowLine: true,
+		},
+	}
+}
+
+// LinkView is a titled web URL.
+type LinkView struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// ProjectDetailView is the detail of project, includes links of project modules and
+// statistics of the project.
+type ProjectDetailView struct {
+	Project ProjectView       `json:"project"`
+	Links   []*LinkView       `json:"links"`
+	Stats   *ProjectStatsView `json:"stats,omitempty"`
+}
+
+var _projectLinkTblHeader = []string{"Link#Title", "Link#URL"}
+
+func (v *ProjectDetailView) Sections() []*render.Section {
+	sections := []*render.Section{{Fields: projectFields(v.Project)}}
+	if len(v.Links) != 0 {
+		rows := make([][]string, 0, len(v.Links))
+		for _, l := range v.Links {
+			rows = append(rows, []string{l.Title, l.URL})
+		}
+		sections = append(sections, &render.Section{Title: "All Links", Header: _projectLinkTblHeader, Rows: rows})
+	}
+	if v.Stats != nil {
+		sections = append(sections, v.Stats.Sections()...)
+	}
+
+	return sections
+}
+
+// ProjectStatsView is the statistics of project. Open features, issues and hotfixes are counted
+// from local records, open milestones and merge requests are counted from gitlab, they are
+// empty if gitlab could not be reached.
+type ProjectStatsView struct {
+	// Days is the time window of HotfixCount.
+	Days                      int                        `json:"days"`
+	OpenFeatures              int                        `json:"open_features"`
+	OpenMilestones            *int                       `json:"open_milestones,omitempty"`
+	MilestoneIssues           []*MilestoneIssueStatsView `json:"milestone_issues"`
+	OpenMergeRequests         *int                       `json:"open_merge_requests,omitempty"`
+	OpenMergeRequestsByTarget []*TargetBranchStatsView   `json:"open_merge_requests_by_target,omitempty"`
+	OldestOpenMergeRequest    *OpenMergeRequestAgeView   `json:"oldest_open_merge_request,omitempty"`
+	HotfixCount               int                        `json:"hotfix_count"`
+	// MergedFeatures is the count of features which have been merged into master,
+	// AvgFeatureLeadTimeHours is averaged over them.
+	MergedFeatures          int     `json:"merged_features"`
+	AvgFeatureLeadTimeHours float64 `json:"avg_feature_lead_time_hours"`
+}
+
+// MilestoneIssueStatsView is the count of issues in an open milestone.
+type MilestoneIssueStatsView struct {
+	MilestoneID  int    `json:"milestone_id"`
+	Title        string `json:"title"`
+	Issues       int    `json:"issues"`
+	ClosedIssues int    `json:"closed_issues"`
+}
+
+// TargetBranchStatsView is the count of open merge requests into the target branch.
+type TargetBranchStatsView struct {
+	TargetBranch string `json:"target_branch"`
+	Count        int    `json:"count"`
+}
+
+// OpenMergeRequestAgeView is an open merge request and how long it has been opened.
+type OpenMergeRequestAgeView struct {
+	*MergeRequestView
+	CreatedAt time.Time `json:"created_at"`
+	AgeHours  float64   `json:"age_hours"`
+}
+
+var (
+	_milestoneIssueStatsTblHeader = []string{"Milestone#ID", "Milestone#Title", "Issue#Count", "Issue#Closed"}
+	_targetBranchStatsTblHeader   = []string{"MR#Target", "MR#Count"}
+)
+
+func (v *ProjectStatsView) Sections() []*render.Section {
+	oldest := "-"
+	if mr := v.OldestOpenMergeRequest; mr != nil {
+		oldest = fmt.Sprintf("%s (%s)", formatHours(mr.AgeHours), mr.WebURL)
+	}
+	leadTime := "-"
+	if v.MergedFeatures != 0 {
+		leadTime = fmt.Sprintf("%s (%d features)", formatHours(v.AvgFeatureLeadTimeHours), v.MergedFeatures)
+	}
+
+	issueRows := make([][]string, 0, len(v.MilestoneIssues))
+	for _, m := range v.MilestoneIssues {
+		issueRows = append(issueRows, []string{
+			strconv.Itoa(m.MilestoneID), m.Title, strconv.Itoa(m.Issues), strconv.Itoa(m.ClosedIssues)})
+	}
+	mrRows := make([][]string, 0, len(v.OpenMergeRequestsByTarget))
+	for _, t := range v.OpenMergeRequestsByTarget {
+		mrRows = append(mrRows, []string{t.TargetBranch, strconv.Itoa(t.Count)})
+	}
+
+	return []*render.Section{
+		{
+			Fields: []render.Field{
+				{Name: "📌 Open Features", Value: strconv.Itoa(v.OpenFeatures)},
+				{Name: "🏁 Open Milestones", Value: formatCount(v.OpenMilestones)},
+				{Name: "🔀 Open MRs", Value: formatCount(v.OpenMergeRequests)},
+				{Name: "🐢 Oldest Open MR", Value: oldest},
+				{Name: "🚑 Hotfixes", Value: fmt.Sprintf("%d (last %d days)", v.HotfixCount, v.Days)},
+				{Name: "⏱  Feature Lead Time", Value: leadTime},
+			},
+		},
+		{Title: "Issues of Open Features", Header: _milestoneIssueStatsTblHeader, Rows: issueRows},
+		{
+			Title:     "Open MRs by Target",
+			Header:    _targetBranchStatsTblHeader,
+			Rows:      mrRows,
+			RowColors: highlightTargetBranch(0),
+		},
+	}
+}
+
+// formatCount formats count, "-" means the count is unknown.
+func formatCount(count *int) string {
+	if count == nil {
+		return "-"
+	}
+
+	return strconv.Itoa(*count)
+}
+
+// formatHours formats hours into days and hours, e.g. 26.5 => "1d 2.5h".
+func formatHours(hours float64) string {
+	days := int(hours / 24)
+	if days == 0 {
+		return fmt.Sprintf("%.1fh", hours)
+	}
+
+	return fmt.Sprintf("%dd %.1fh", days, hours-float64(days*24))
+}
+
+// ProjectItemView is a project recorded in local database, LocalDir is the directory
+// where the project was located.
+type ProjectItemView struct {
+	ProjectView
+	LocalDir string `json:"local_dir"`
+}
+
+// ProjectListView is all projects recorded in local database.
+type ProjectListView struct {
+	Projects []*ProjectItemView `json:"projects"`
+}
+
+var _projectListTblHeader = []string{"Project#ID", "Project#Name", "Project#Dir", "Project#WebURL"}
+
+func (v *ProjectListView) Sections() []*render.Section {
+	rows := make([][]string, 0, len(v.Projects))
+	for _, p := range v.Projects {
+		rows = append(rows, []string{strconv.Itoa(p.ID), p.Name, textOrDash(p.LocalDir), p.WebURL})
+	}
+
+	return []*render.Section{{Header: _projectListTblHeader, Rows: rows}}
+}
+
+// FeatureItemView is a feature of project, includes the milestone and the feature branch.
+type FeatureItemView struct {
+	Milestone     MilestoneView `json:"milestone"`
+	FeatureBranch string        `json:"feature_branch"`
+	GroupID       int           `json:"group_id,omitempty"`
+	Closed        bool          `json:"closed"`
+}
+
+// FeatureListView is all features of project, open features are listed first.
+type FeatureListView struct {
+	Project  ProjectView        `json:"project"`
+	Features []*FeatureItemView `json:"features"`
+}
+
+var _featureListTblHeader = []string{"Milestone#ID", "Milestone#Title", "Feature#Branch", "Feature#State"}
+
+func (v *FeatureListView) Sections() []*render.Section {
+	rows := make([][]string, 0, len(v.Features))
+	for _, f := range v.Features {
+		state := "opened"
+		if f.Closed {
+			state = "closed"
+		}
+		rows = append(rows, []string{strconv.Itoa(f.Milestone.ID), f.Milestone.Title, textOrDash(f.FeatureBranch), state})
+	}
+
+	return []*render.Section{
+		{Fields: projectFields(v.Project)},
+		{Title: "All Features", Header: _featureListTblHeader, Rows: rows},
+	}
+}
+
+// InFlightProjectView is open features of a project.
+type InFlightProjectView struct {
+	Project  ProjectView        `json:"project"`
+	Features []*FeatureItemView `json:"features"`
+}
+
+// InFlightView is open features of all projects.
+type InFlightView struct {
+	Projects []*InFlightProjectView `json:"projects"`
+}
+
+var _inFlightTblHeader = []string{"Project#Name", "Milestone#Title", "Feature#Branch", "Milestone#WebURL"}
+
+func (v *InFlightView) Sections() []*render.Section {
+	rows := make([][]string, 0, len(v.Projects))
+	for _, p := range v.Projects {
+		for _, f := range p.Features {
+			rows = append(rows, []string{p.Project.Name, f.Milestone.Title, textOrDash(f.FeatureBranch), f.Milestone.WebURL})
+		}
+	}
+
+	return []*render.Section{{Title: "In-flight Features", Header: _inFlightTblHeader, Rows: rows, MergeCells: true}}
+}
+
+// PipelineView is the latest pipeline of feature branch, or of merge request if MergeRequestIID
+// is not zero.
+type PipelineView struct {
+	ID              int        `json:"id"`
+	Ref             string     `json:"ref"`
+	SHA             string     `json:"sha"`
+	Source          string     `json:"source"`
+	Status          string     `json:"status"`
+	WebURL          string     `json:"web_url"`
+	MergeRequestIID int        `json:"merge_request_iid,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+func newPipelineView(p *gitlabop.PipelineShort, mergeRequestIID int) *PipelineView {
+	return &PipelineView{
+		ID:              p.ID,
+		Ref:             p.Ref,
+		SHA:             p.SHA,
+		Source:          p.Source,
+		Status:          p.Status,
+		WebURL:          p.WebURL,
+		MergeRequestIID: mergeRequestIID,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+func (v *PipelineView) Sections() []*render.Section {
+	ref := v.Ref
+	if v.MergeRequestIID != 0 {
+		ref = fmt.Sprintf("%s (MR !%d)", v.Ref, v.MergeRequestIID)
+	}
+	updatedAt := "-"
+	if v.UpdatedAt != nil {
+		updatedAt = v.UpdatedAt.Local().Format(time.DateTime)
+	}
+
+	return []*render.Section{
+		{
+			Fields: []render.Field{
+				{Name: "🚦 Pipeline Status", Value: v.Status},
+				{Name: "🔖 Pipeline Ref", Value: ref},
+				{Name: "🔑 Pipeline SHA", Value: v.SHA},
+				{Name: "⏰ Updated At", Value: updatedAt},
+				{Name: "🤡 Pipeline URL", Value: v.WebURL},
+			},
+		},
+	}
+}
+
+// FeatureBranchView is a branch of feature, IssueIID is zero means it's the feature branch.
+type FeatureBranchView struct {
+	IssueIID   int    `json:"issue_iid"`
+	BranchName string `json:"branch_name"`
+	IssueTitle string `json:"issue_title"`
+}
+
+// FeatureBranchesView is all branches of a feature.
+type FeatureBranchesView struct {
+	FeatureBranch string               `json:"feature_branch"`
+	Branches      []*FeatureBranchView `json:"branches"`
+}
+
+var _featureBranchTblHeader = []string{"Issue#IID", "Branch", "Issue#Title"}
+
+func (v *FeatureBranchesView) Sections() []*render.Section {
+	rows := make([][]string, 0, len(v.Branches))
+	for _, b := range v.Branches {
+		if b.IssueIID == 0 {
+			rows = append(rows, []string{"-", b.BranchName, "*FEATURE-BRANCH"})
+			continue
+		}
+		rows = append(rows, []string{"#" + strconv.Itoa(b.IssueIID), b.BranchName, b.IssueTitle})
+	}
+
+	return []*render.Section{
+		{
+			Header: _featureBranchTblHeader,
+			Rows:   rows,
+			RowColors: func(row []string) []tablewriter.Colors {
+				if row[0] != "-" {
+					return nil
+				}
+				return []tablewriter.Colors{{}, {tablewriter.Bold, tablewriter.FgHiGreenColor}}
+			},
+		},
+	}
+}
+
+// ChangelogView is changelog of milestones, it's always rendered as markdown
+// except json and yaml format.
+type ChangelogView struct {
+	Title    string              `json:"title,omitempty"`
+	Sections []*ChangelogSection `json:"sections"`
+}
+
+func (v *ChangelogView) Markdown() ([]byte, error) {
+	return renderChangelog(v.Title, v.Sections)
+}
+
+func textOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -9,16 +9,19 @@ import (
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	"github.com/yeqown/log"
 
 	"github.com/yeqown/gitlab-flow/internal/repository"
 	"github.com/yeqown/gitlab-flow/internal/types"
+	"github.com/yeqown/gitlab-flow/pkg"
 )
 
 // IFlow to control branches, MRs, milestones and issues.
 type IFlow interface {
 	IFeature
 	IHotfix
+	IRelease
 	ISync
 }
 
@@ -31,8 +34,15 @@ type IFeature interface {
 	FeatureDebugging(opc *types.OpFeatureContext) error
 	// FeatureTest open a MergeRequest of feature branch and types.TestBranch branch.
 	FeatureTest(opc *types.OpFeatureContext) error
-	// FeatureRelease open a MergeRequest of feature branch and types.MasterBranch branch.
+	// FeatureRelease open a MergeRequest of feature branch and types.MasterBranch branch, it's blocked
+	// if the latest pipeline of feature branch has failed, unless opc.IgnorePipeline is set.
+	// If opc.ReleaseTag is set, it waits the MergeRequest to be merged, then creates the tag on
+	// the merge commit and publishes a gitlab release.
 	FeatureRelease(opc *types.OpFeatureContext) error
+	// FeaturePipeline get the latest pipeline of feature branch, or the merge request from feature
+	// branch into targetBranch if it's not empty. If wait is true, it polls until the pipeline finishes,
+	// and error would be returned if the pipeline has failed.
+	FeaturePipeline(opc *types.OpFeatureContext, targetBranch string, wait bool) (*PipelineView, error)
 	// DONE(@yeqown) this would be useful while you merge feature into master, but there is conflict.
 
 	// FeatureResolveConflict will check out a new branch from the target branch,
@@ -40,16 +50,30 @@ type IFeature interface {
 	// newBranch = "resolve-conflict/featureBranchName-to-master"
 	FeatureResolveConflict(opc *types.OpFeatureContext, targetBranch types.BranchTyp) error
 
-	// FeatureBeginIssue checkout an issue branch from feature branch, also open a merge request
-	// which is from issue branch to feature branch.
+	// FeatureUpdate fetches and then merges types.MasterBranch into the feature branch, or rebases
+	// the feature branch onto it if opc.Rebase is true. If the update is stopped by conflicts,
+	// it could be continued or aborted by action.
+	FeatureUpdate(opc *types.OpFeatureContext, action types.UpdateAction) error
+	// FeatureUpdateIssue is the same as FeatureUpdate, but updates the issue branch from its feature branch.
+	FeatureUpdateIssue(opc *types.OpFeatureContext, issueBranchName string, action types.UpdateAction) error
+
+	// FeatureBeginIssue checkout an issue branch from feature branch, also open a draft merge request
+	// which is from issue branch to feature branch if opc.Draft is true.
 	FeatureBeginIssue(opc *types.OpFeatureContext, title, desc string) error
-	// FeatureFinishIssue open the WebURL of merge request which is from issue branch to feature branch.
+	// FeatureFinishIssue open the WebURL of merge request which is from issue branch to feature branch,
+	// the merge request would be marked as ready if it's draft.
 	FeatureFinishIssue(opc *types.OpFeatureContext, issueBranchName string) error
 
+	// FeatureClose close the feature after the merge request into types.MasterBranch has been merged.
+	// It closes the milestone and leftover issues both remote and local, marks local merge requests
+	// closed. If deleteBranch is true, the feature branch and its issue branches would be deleted
+	// locally and remotely.
+	FeatureClose(opc *types.OpFeatureContext, deleteBranch bool) error
+
 	// Checkout to branch related to current feature, feature branch or issue branches.
-	// Default is to check out the feature branch. It would list all branches if --list is set.
+	// Default is to check out the feature branch. All branches of the feature would be returned if listAll is set.
 	// It would interact with user to choose which branch to check out if --issue is set.
-	Checkout(opc *types.OpFeatureContext, listAll bool, issueID int)
+	Checkout(opc *types.OpFeatureContext, listAll bool, issueID int) *FeatureBranchesView
 }
 
 type IHotfix interface {
@@ -57,14 +81,30 @@ type IHotfix interface {
 	// which is from hotfix branch to types.MasterBranch.
 	HotfixBegin(opc *types.OpHotfixContext, title, desc string) error
 	// HotfixFinish open the WebURL of merge request which is from hotfix branch to types.MasterBranch.
+	// If opc.Backport is true, merge requests from hotfix branch into types.DevBranch and types.TestBranch
+	// would be opened too, conflict-resolve branch would be created if there is conflict.
 	HotfixFinish(opc *types.OpHotfixContext, hotfixBranchName string) error
 }
 
+type IRelease interface {
+	// ReleaseBegin checkout a release branch (release/version) from types.ReleaseBaseBranch.
+	ReleaseBegin(opc *types.OpReleaseContext, version, desc string) error
+	// ReleaseAttach attach feature milestones to the release, and open merge requests
+	// which are from feature branches to the release branch.
+	ReleaseAttach(opc *types.OpReleaseContext, featureBranchNames []string) error
+	// ReleaseFinish open a merge request which is from release branch to types.MasterBranch.
+	ReleaseFinish(opc *types.OpReleaseContext) error
+	// ReleaseClose tag types.MasterBranch after the release has been merged into master,
+	// the version of release would be used as tag name if tagName is empty.
+	ReleaseClose(opc *types.OpReleaseContext, tagName string) error
+}
+
 type ISync interface {
 	// SyncProject synchronize project information from remote gitlab server.
 	SyncProject(isDelete bool) error
 	// SyncMilestone synchronize remote repository milestone and related issues / merge requests to local.
-	SyncMilestone(milestoneID int, interact bool) error
+	// groupMilestone means milestoneID is a milestone of the group which the project belongs to.
+	SyncMilestone(milestoneID int, groupMilestone, interact bool) error
 }
 
 var (
@@ -105,6 +145,30 @@ func notBuiltinBranch(branchName string) bool {
 	return true
 }
 
+// relatedProjectIDs returns projects (except the current project) which issues or merge requests belong to,
+// the result is ordered by the first appearance.
+func relatedProjectIDs(
+	current int, issues []*repository.IssueDO, mrs []*repository.MergeRequestDO) []int {
+	seen := map[int]struct{}{current: {}}
+	out := make([]int, 0, 4)
+	add := func(pid int) {
+		if _, ok := seen[pid]; ok {
+			return
+		}
+		seen[pid] = struct{}{}
+		out = append(out, pid)
+	}
+
+	for _, v := range issues {
+		add(v.ProjectID)
+	}
+	for _, v := range mrs {
+		add(v.ProjectID)
+	}
+
+	return out
+}
+
 // genFeatureBranchName
 func genFeatureBranchName(name string) string {
 	if strings.HasPrefix(name, types.FeatureBranchPrefix) {
@@ -160,6 +224,73 @@ func genHotfixBranchName(name string) string {
 	return types.HotfixBranchPrefix + name
 }
 
+// genReleaseBranchName .
+// @result = release/version as default
+func genReleaseBranchName(version string) string {
+	if strings.HasPrefix(version, types.ReleaseBranchPrefix) {
+		return version
+	}
+
+	return types.ReleaseBranchPrefix + version
+}
+
+// genConflictResolveBranchName .
+// @result = conflict-resolve/name-to-targetBranch
+func genConflictResolveBranchName(srcBranch, targetBranch string) string {
+	name := strings.TrimPrefix(srcBranch, types.HotfixBranchPrefix)
+
+	return types.ConflictResolveBranchPrefix + name + "-to-" + targetBranch
+}
+
+// isReleaseName judge whether branchName is release branch or not.
+func isReleaseName(name string) bool {
+	return strings.HasPrefix(name, types.ReleaseBranchPrefix)
+}
+
+// genReleaseNotes generate release notes in markdown from issues and merge requests which
+// are stored in local repository, closed merge requests would be ignored.
+func genReleaseNotes(
+	tagName, title, desc string, issues []*repository.IssueDO, mrs []*repository.MergeRequestDO) string {
+	sb := strings.Builder{}
+	sb.WriteString("## " + tagName + "\n\n")
+	sb.WriteString("**" + title + "**\n\n")
+	if desc != "" {
+		sb.WriteString(desc + "\n\n")
+	}
+
+	if len(issues) != 0 {
+		sb.WriteString("### Issues\n\n")
+		for _, v := range issues {
+			sb.WriteString(fmt.Sprintf("- [#%d %s](%s)\n", v.IssueIID, v.Title, v.WebURL))
+		}
+		sb.WriteString("\n")
+	}
+
+	mrs = lo.Filter(mrs, func(v *repository.MergeRequestDO, _ int) bool {
+		return v.State != repository.MergeRequestStateClosed
+	})
+	if len(mrs) != 0 {
+		sb.WriteString("### Merge Requests\n\n")
+		for _, v := range mrs {
+			sb.WriteString(fmt.Sprintf("- [!%d %s => %s](%s)\n",
+				v.MergeRequestIID, v.SourceBranch, v.TargetBranch, v.WebURL))
+		}
+	}
+
+	return sb.String()
+}
+
+// nextSemver bumps the latest semantic version in versions, those are not semantic version
+// would be ignored. v0.0.0 is the base version if no valid version found.
+func nextSemver(versions []string, part pkg.BumpPart) string {
+	latest := pkg.LatestSemver(versions)
+	if latest == nil {
+		latest = &pkg.Semver{Prefix: "v"}
+	}
+
+	return latest.Bump(part).String()
+}
+
 // genMergeRequestName generate merge request name.
 func genMergeRequestName(srcBranch, targetBranch string) string {
 	return fmt.Sprintf("Merge %s into %s", srcBranch, targetBranch)
@@ -225,18 +356,85 @@ func parseFeatureFromIssueName(issueName string, compatible bool) string {
 	return issueName[:idx]
 }
 
+// projectSelector locates a project by name, ID or full path (namespace/name), ID and full path
+// are used to resolve projects with the same name.
+type projectSelector struct {
+	Name     string
+	ID       int
+	FullPath string
+}
+
+// parseProjectSelector parses --project flag, it could be project name, ID or full path.
+func parseProjectSelector(s string) projectSelector {
+	s = strings.Trim(strings.TrimSpace(s), "/")
+	if id, err := strconv.Atoi(s); err == nil && id > 0 {
+		return projectSelector{ID: id}
+	}
+	if idx := strings.LastIndex(s, "/"); idx >= 0 {
+		return projectSelector{Name: s[idx+1:], FullPath: s}
+	}
+
+	return projectSelector{Name: s}
+}
+
+// specific returns true if selector could locate one project without ambiguity.
+func (s projectSelector) specific() bool {
+	return s.ID != 0 || s.FullPath != ""
+}
+
+// match reports whether the project matches the ID or full path of selector, project's full path
+// is the path of WebURL.
+func (s projectSelector) match(p *repository.ProjectDO) bool {
+	if s.ID != 0 {
+		return p.ProjectID == s.ID
+	}
+	if s.FullPath != "" {
+		return strings.HasSuffix(strings.TrimSuffix(p.WebURL, "/"), "/"+s.FullPath)
+	}
+
+	return true
+}
+
+func (s projectSelector) String() string {
+	if s.ID != 0 {
+		return strconv.Itoa(s.ID)
+	}
+	if s.FullPath != "" {
+		return s.FullPath
+	}
+
+	return s.Name
+}
+
 // chooseOneProjectInteractively if there are not only one project matched from local or remote,
-// then let user know and do the choice.
-func chooseOneProjectInteractively(projects []*repository.ProjectDO) (*repository.ProjectDO, error) {
+// then let user know and do the choice. Projects are filtered by selector first, and in non-interactive
+// mode, an error with all candidates would be returned instead of asking user.
+func chooseOneProjectInteractively(
+	projects []*repository.ProjectDO, sel projectSelector, nonInteractive bool) (*repository.ProjectDO, error) {
 	if len(projects) == 0 {
 		return nil, errors.New("no project to choose")
 	}
 
+	if sel.specific() {
+		projects = lo.Filter(projects, func(p *repository.ProjectDO, _ int) bool { return sel.match(p) })
+		if len(projects) == 0 {
+			return nil, errors.Errorf("no project matched %s", sel)
+		}
+	}
+
 	if len(projects) == 1 {
 		// if only one project found, then use this as target project
 		return projects[0], nil
 	}
 
+	if nonInteractive {
+		candidates := lo.Map(projects, func(p *repository.ProjectDO, _ int) string {
+			return fmt.Sprintf("  - %d %s", p.ProjectID, p.WebURL)
+		})
+		return nil, errors.Errorf("%d projects matched, please specify one by --project "+
+			"with project ID or full path:\n%s", len(projects), strings.Join(candidates, "\n"))
+	}
+
 	projectOptions := make([]string, len(projects))
 	for idx, v := range projects {
 		projectOptions[idx] = fmt.Sprintf("%d::%s::%d::%s", idx, v.ProjectName, v.ProjectID, v.WebURL)
@@ -266,8 +464,10 @@ func chooseOneProjectInteractively(projects []*repository.ProjectDO) (*repositor
 }
 
 // chooseOneMilestoneInteractively if there are not only one milestone matched from local or remote,
-// then let user know and make a decision.
-func chooseOneMilestoneInteractively(milestones []*repository.MilestoneDO) (*repository.MilestoneDO, error) {
+// then let user know and make a decision. In non-interactive mode, an error with all candidates would
+// be returned instead of asking user.
+func chooseOneMilestoneInteractively(
+	milestones []*repository.MilestoneDO, nonInteractive bool) (*repository.MilestoneDO, error) {
 	if len(milestones) == 0 {
 		return nil, errors.New("no milestone to choose")
 	}
@@ -278,9 +478,20 @@ func chooseOneMilestoneInteractively(milestones []*repository.MilestoneDO) (*rep
 
 	}
 
+	if nonInteractive {
+		candidates := lo.Map(milestones, func(m *repository.MilestoneDO, _ int) string {
+			return fmt.Sprintf("  - %d %s", m.MilestoneID, m.Title)
+		})
+		return nil, errors.Errorf("%d milestones found, please specify one by --milestone_id:\n%s",
+			len(milestones), strings.Join(candidates, "\n"))
+	}
+
 	milestoneOptions := make([]string, len(milestones))
 	for idx, v := range milestones {
 		milestoneOptions[idx] = v.Title
+		if v.IsGroupMilestone() {
+			milestoneOptions[idx] += " (group)"
+		}
 	}
 
 	qs := []*survey.Question{
@@ -301,3 +512,31 @@ func chooseOneMilestoneInteractively(milestones []*repository.MilestoneDO) (*rep
 
 	return milestones[r.Idx], nil
 }
+
+// confirmInteractively ask user to confirm something, defaultValue would be returned if
+// survey failed or in non-interactive mode, the caller should tell how to change the answer
+// by flags in non-interactive mode.
+func confirmInteractively(message string, defaultValue, nonInteractive bool) bool {
+	if nonInteractive {
+		answer := "no"
+		if defaultValue {
+			answer = "yes"
+		}
+		log.Infof("%s (%s, non-interactive mode)", message, answer)
+		return defaultValue
+	}
+
+	ans := defaultValue
+	if err := survey.AskOne(&survey.Confirm{
+		Message: message,
+		Default: defaultValue,
+	}, &ans); err != nil {
+		if errors.Is(err, terminal.InterruptErr) {
+			log.Warn("user canceled the operation")
+		}
+
+		return defaultValue
+	}
+
+	return ans
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"sync"
@@ -38,22 +39,79 @@ type flowImpl struct {
 
 var (
 	errInvalidFeatureName = errors.New("feature branch could not be empty")
+	errFeatureNotReleased = errors.New("feature has not been merged into master")
+	errInvalidReleaseName = errors.New("release branch could not be empty")
+	errReleaseNotMerged   = errors.New("release has not been merged into master")
+	errReleaseClosed      = errors.New("release has been closed")
+	errPipelineFailed     = errors.New("pipeline failed")
 )
 
-// refreshOAuthAccessToken check access token is valid or not. If the access token becomes invalid,
-// then refresh it, if refresh failed, it leads to re-authorize.
-func refreshOAuthAccessToken(ctx *types.FlowContext, ch IConfigHelper) {
+// _renewTokenMu serializes renewing access token, since the global configuration is shared and
+// saved into file, and the refresh token could be used only once.
+var _renewTokenMu sync.Mutex
+
+// renewOAuthAccessToken check access token is valid or not. If the access token becomes invalid,
+// then refresh it, if refresh failed, it leads to re-authorize. The valid access token is returned,
+// error means the caller could not access gitlab, it could go on with other projects or skip the
+// remote part. In non-interactive mode, it never re-authorizes, see renewOAuthAccessTokenNonInteractive.
+func renewOAuthAccessToken(ctx *types.FlowContext, ch IConfigHelper) (string, error) {
+	_renewTokenMu.Lock()
+	defer _renewTokenMu.Unlock()
+
 	c := ch.Config(types.ConfigType_Global).AsGlobal()
+	if ctx.NonInteractive() {
+		return renewOAuthAccessTokenNonInteractive(ctx, ch, c)
+	}
+
 	oauth := gitlabop.NewOAuth2Support(gitlabop.NewOAuth2ConfigFrom(c))
 	if err := oauth.Enter(c.OAuth2.RefreshToken); err != nil {
 		log.
 			WithFields(log.Fields{"config": c}).
 			Errorf("NewGitlabOperator could not renew token: %v", err)
-		panic(err)
+		return "", errors.Wrap(err, "could not renew access token")
 	}
 
 	accessToken, refreshToken := oauth.Load()
+	saveOAuthTokens(ctx, ch, c, accessToken, refreshToken)
+
+	return accessToken, nil
+}
+
+// renewOAuthAccessTokenNonInteractive renews access token without authorization by browser, which
+// blocks until it's done by user. The access token of environment variable is used as-is, otherwise
+// it's refreshed by the refresh token of environment variable or configuration, otherwise the access
+// token of configuration is used as-is. Error is returned if re-authorization is required.
+func renewOAuthAccessTokenNonInteractive(ctx *types.FlowContext, ch IConfigHelper, c *types.Config) (string, error) {
+	accessTokenEnv := pkg.EnvName(types.EnvPrefix, "accessToken")
+	if accessToken := os.Getenv(accessTokenEnv); accessToken != "" {
+		return accessToken, nil
+	}
+
+	refreshToken := os.Getenv(pkg.EnvName(types.EnvPrefix, "refreshToken"))
+	if refreshToken == "" {
+		refreshToken = c.OAuth2.RefreshToken
+	}
+	if refreshToken == "" {
+		if c.OAuth2.AccessToken != "" {
+			return c.OAuth2.AccessToken, nil
+		}
+		return "", errors.Errorf("could not authorize in non-interactive mode, please set %s or %s",
+			accessTokenEnv, pkg.EnvName(types.EnvPrefix, "refreshToken"))
+	}
+
+	oauth := gitlabop.NewOAuth2Support(gitlabop.NewOAuth2ConfigFrom(c))
+	accessToken, refreshToken, err := oauth.Refresh(refreshToken)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not refresh access token in non-interactive mode, "+
+			"please authorize in interactive mode or set %s", accessTokenEnv)
+	}
+	saveOAuthTokens(ctx, ch, c, accessToken, refreshToken)
 
+	return accessToken, nil
+}
+
+// saveOAuthTokens updates tokens of context and global configuration, and saves the configuration.
+func saveOAuthTokens(ctx *types.FlowContext, ch IConfigHelper, c *types.Config, accessToken, refreshToken string) {
 	c.OAuth2.AccessToken = accessToken
 	c.OAuth2.RefreshToken = refreshToken
 	// update context oauth configuration
@@ -66,34 +124,60 @@ func refreshOAuthAccessToken(ctx *types.FlowContext, ch IConfigHelper) {
 	}
 }
 
+// NewRepository opens the local repository in the global configuration directory, the repository
+// could be shared by flows and dashes of different projects.
+func NewRepository(ctx *types.FlowContext, ch IConfigHelper) repository.IFlowRepository {
+	return impl.NewBasedSqlite3(impl.ConnectDB(ch.Context().GlobalConfPath, ctx.IsDebug()))
+}
+
 func NewFlow(ctx *types.FlowContext, ch IConfigHelper) IFlow {
 	if ctx == nil {
 		log.Fatal("empty FlowContext initialized")
 		panic("can not reach")
 	}
 
+	flow, err := NewFlowWithRepository(ctx, ch, NewRepository(ctx, ch))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	return flow
+}
+
+// NewFlowWithRepository constructs flow with the given repository like NewFlow, but returns error
+// rather than exiting, so that one project failed would not stop others.
+func NewFlowWithRepository(ctx *types.FlowContext, ch IConfigHelper, repo repository.IFlowRepository) (IFlow, error) {
+	if ctx == nil {
+		return nil, errors.New("empty FlowContext initialized")
+	}
+
 	log.
 		WithField("context", ctx).
 		Debugf("constructing flow")
 
-	refreshOAuthAccessToken(ctx, ch)
+	accessToken, err := renewOAuthAccessToken(ctx, ch)
+	if err != nil {
+		return nil, err
+	}
 
 	flow := &flowImpl{
 		ctx:            ctx,
-		gitlabOperator: gitlabop.NewGitlabOperator(ctx.GetOAuth().AccessToken, ctx.APIEndpoint()),
-		gitOperator:    gitop.NewBasedCmd(ctx.CWD()),
-		repo:           impl.NewBasedSqlite3(impl.ConnectDB(ch.Context().GlobalConfPath, ctx.IsDebug())),
+		gitlabOperator: gitlabop.NewGitlabOperator(accessToken, ctx.APIEndpoint()),
+		gitOperator:    gitop.New(ctx.Config().GitBackend, ctx.CWD(), ctx.Config().GitRemote),
+		repo:           repo,
 	}
 
 	// if flowContext has NONE project information, so we need to fill it.
-	if err := flow.fillContextWithProject(); err != nil {
-		log.
-			Fatalf("could not locate project(%s): %v", ctx.ProjectName(), err)
+	if err = flow.fillContextWithProject(); err != nil {
+		return nil, errors.Wrapf(err, "could not locate project(%s)", ctx.ProjectName())
 	}
 
-	return flow
+	return flow, nil
 }
 
+// _maxSearchedProjects is the max number of projects searched by project name from gitlab.
+const _maxSearchedProjects = 100
+
 // fillContextWithProject
 // FlowContext with null project information, so we need to fill it.
 // DONE(@yeqown): fill project information from local repository or remote gitlab repository.
@@ -101,6 +185,8 @@ func NewFlow(ctx *types.FlowContext, ch IConfigHelper) IFlow {
 func (f flowImpl) fillContextWithProject() error {
 	var (
 		projectName = f.ctx.ProjectName()
+		sel         = parseProjectSelector(projectName)
+		projects    []gitlabop.ProjectShort
 		err         error
 		injected    bool
 	)
@@ -111,7 +197,7 @@ func (f flowImpl) fillContextWithProject() error {
 	}
 
 	// get from local with name or workdir
-	injected, err = injectProjectIntoContext(f.repo, f.ctx, projectName, f.ctx.CWD())
+	injected, err = injectProjectIntoContext(f.repo, f.ctx, sel, f.ctx.CWD(), f.ctx.NonInteractive())
 	if err == nil && injected {
 		return nil
 	}
@@ -119,24 +205,40 @@ func (f flowImpl) fillContextWithProject() error {
 	log.Warnf("could not found project(%s) from local: %v", projectName, err)
 
 locateFromRemote:
-	// query from remote repository.
-	result, err := f.gitlabOperator.ListProjects(context.Background(), &gitlabop.ListProjectRequest{
-		Page:        1,
-		PerPage:     20,
-		ProjectName: projectName,
-	})
-	if err != nil {
-		return errors.Wrap(err, "requests remote repository failed")
+	// query from remote repository, project could be located by ID directly.
+	if sel.ID != 0 {
+		project, err2 := f.gitlabOperator.GetProject(context.Background(), &gitlabop.GetProjectRequest{ProjectID: sel.ID})
+		if err2 != nil {
+			return errors.Wrap(err2, "requests remote repository failed")
+		}
+		projects = []gitlabop.ProjectShort{*project}
+	} else {
+		// search by full path is narrower, if the search is still too wide, it's not
+		// worthy to request all pages of it, a more specific selector is required.
+		req := &gitlabop.ListProjectRequest{ProjectName: sel.Name}
+		if sel.FullPath != "" {
+			req.ProjectName, req.SearchNamespaces = sel.FullPath, true
+		}
+		projects, err = gitlabop.CollectN(
+			f.gitlabOperator.ListProjects(context.Background(), req), _maxSearchedProjects)
+		if errors.Is(err, gitlabop.ErrTooManyItems) {
+			return errors.Errorf("more than %d projects match %s in gitlab, "+
+				"please locate the project by ID or full path (namespace/name) with --project",
+				_maxSearchedProjects, projectName)
+		}
+		if err != nil {
+			return errors.Wrap(err, "requests remote repository failed")
+		}
 	}
 
-	log.WithFields(log.Fields{"project": projectName, "result": result}).
+	log.WithFields(log.Fields{"project": projectName, "result": projects}).
 		Debug("locate project from remote")
 
 	// found and match
 	// DONE(@yeqown): if remote(gitlab) has not only one project with projectName, then choose one as target.
 	remoteMatched := make([]*repository.ProjectDO, 0, 5)
-	for _, v := range result.Data {
-		if strings.Compare(projectName, v.Name) == 0 {
+	for _, v := range projects {
+		if sel.ID != 0 || strings.Compare(sel.Name, v.Name) == 0 {
 			// matched
 			log.
 				WithFields(log.Fields{
@@ -148,7 +250,7 @@ locateFromRemote:
 
 			// DONE(@yeqown): save into local database
 			projectDO := repository.ProjectDO{
-				ProjectName: projectName,
+				ProjectName: v.Name,
 				ProjectID:   v.ID,
 				LocalDir:    f.ctx.CWD(),
 				WebURL:      v.WebURL,
@@ -158,7 +260,7 @@ locateFromRemote:
 		}
 	}
 
-	matched, err := chooseOneProjectInteractively(remoteMatched)
+	matched, err := chooseOneProjectInteractively(remoteMatched, sel, f.ctx.NonInteractive())
 	if err == nil {
 		if err = f.repo.SaveProject(matched); err != nil {
 			log.
@@ -186,12 +288,51 @@ func (f flowImpl) FeatureBegin(opc *types.OpFeatureContext, title, desc string)
 		}).
 		Debug("FeatureBegin called")
 
+	if opc.Bump != "" {
+		part, err := pkg.ParseBumpPart(opc.Bump)
+		if err != nil {
+			return err
+		}
+		suggested, err := f.suggestNextVersion(part)
+		if err != nil {
+			return errors.Wrap(err, "suggest next version failed")
+		}
+		if title == "" {
+			title = suggested
+		}
+		log.Infof("suggested version: %s, milestone title: %s", suggested, title)
+	}
+
 	if err := blockingNamePrefix(title); err != nil {
 		return errors.Wrap(err, "blocking name prefix detected")
 	}
 
+	// milestone title must be a semantic version in semver mode.
+	version := ""
+	if f.ctx.Config().Semver || opc.Bump != "" {
+		v, err := pkg.ParseSemver(title)
+		if err != nil {
+			return errors.Wrap(err, "milestone title must be a semantic version in semver mode")
+		}
+		version = v.String()
+	}
+
+	groupID := 0
+	if opc.GroupMilestone {
+		project, err := f.gitlabOperator.GetProject(context.Background(), &gitlabop.GetProjectRequest{
+			ProjectID: f.ctx.Project().ID,
+		})
+		if err != nil {
+			return errors.Wrap(err, "get project failed")
+		}
+		if project.GroupID == 0 {
+			return errors.Errorf("project(%s) does not belong to a group", project.PathWithNamespace)
+		}
+		groupID = project.GroupID
+	}
+
 	// create a milestone
-	result, err := f.createMilestone(title, desc)
+	result, err := f.createMilestone(title, desc, version, groupID)
 	if err != nil {
 		return errors.Wrap(err, "CreateMilestone failed")
 	}
@@ -229,6 +370,9 @@ func (f flowImpl) FeatureDebugging(opc *types.OpFeatureContext) (err error) {
 	if opc.FeatureBranchName, err = f.extractFeatureBranchName(opc); err != nil {
 		return err
 	}
+	if resolved, err := f.resolveConflictIfPredicted(opc, types.DevBranch); resolved || err != nil {
+		return err
+	}
 	return f.featureProcessMR(opc.FeatureBranchName, types.DevBranch, opc.ForceCreateMergeRequest, opc.AutoMergeRequest)
 }
 
@@ -236,6 +380,9 @@ func (f flowImpl) FeatureTest(opc *types.OpFeatureContext) (err error) {
 	if opc.FeatureBranchName, err = f.extractFeatureBranchName(opc); err != nil {
 		return err
 	}
+	if resolved, err := f.resolveConflictIfPredicted(opc, types.TestBranch); resolved || err != nil {
+		return err
+	}
 	return f.featureProcessMR(opc.FeatureBranchName, types.TestBranch, opc.ForceCreateMergeRequest, opc.AutoMergeRequest)
 }
 
@@ -243,103 +390,315 @@ func (f flowImpl) FeatureRelease(opc *types.OpFeatureContext) (err error) {
 	if opc.FeatureBranchName, err = f.extractFeatureBranchName(opc); err != nil {
 		return err
 	}
-	return f.featureProcessMR(opc.FeatureBranchName, types.MasterBranch, opc.ForceCreateMergeRequest, opc.AutoMergeRequest)
-}
-
-func (f flowImpl) FeatureResolveConflict(opc *types.OpFeatureContext, targetBranch types.BranchTyp) (err error) {
-	if opc.FeatureBranchName, err = f.extractFeatureBranchName(opc); err != nil {
+	if err = f.checkFeaturePipeline(context.Background(), opc); err != nil {
+		return err
+	}
+	if resolved, err := f.resolveConflictIfPredicted(opc, types.MasterBranch); resolved || err != nil {
+		if err == nil && opc.ReleaseTag != "" {
+			err = errors.Errorf("conflicts of %s are resolved in a conflict-resolve branch rather than releasing, "+
+				"tag %s is not created, please release with --tag again after conflicts are resolved",
+				opc.FeatureBranchName, opc.ReleaseTag)
+		}
+		return err
+	}
+	err = f.featureProcessMR(opc.FeatureBranchName, types.MasterBranch, opc.ForceCreateMergeRequest, opc.AutoMergeRequest)
+	if err != nil || opc.ReleaseTag == "" {
 		return err
 	}
 
-	// feature/branch-1 => conflict-resolve/branch-1
-	resolveConflictBranch := strings.Replace(opc.FeatureBranchName, types.FeatureBranchPrefix, types.ConflictResolveBranchPrefix, 1)
+	return f.featurePublishRelease(opc)
+}
 
-	// locate feature branch
-	featureBranch, err := f.repo.QueryBranch(&repository.BranchDO{
-		ProjectID:  f.ctx.Project().ID,
-		BranchName: opc.FeatureBranchName,
+// checkFeaturePipeline blocks releasing if the latest pipeline of feature branch has failed,
+// it only warns if opc.IgnorePipeline is true. Pipeline could not be checked is not an error.
+func (f flowImpl) checkFeaturePipeline(ctx context.Context, opc *types.OpFeatureContext) error {
+	pipeline, err := f.gitlabOperator.GetLatestPipeline(ctx, &gitlabop.GetLatestPipelineRequest{
+		ProjectID: f.ctx.Project().ID,
+		Ref:       opc.FeatureBranchName,
 	})
 	if err != nil {
-		return errors.Wrap(err, "locate feature branch failed")
+		if !errors.Is(err, gitlabop.ErrPipelineNotFound) {
+			log.
+				WithFields(log.Fields{"featureBranch": opc.FeatureBranchName}).
+				Warnf("could not check pipeline of feature branch: %v", err)
+		}
+		return nil
 	}
 
-	// create resolve conflict branch.
-	// Notice: createBranch would create branch which checkout to new branch automatically.
-	if _, err = f.createBranch(
-		resolveConflictBranch, targetBranch.String(), featureBranch.MilestoneID, 0); err != nil {
-		return err
+	switch {
+	case !pipeline.Finished():
+		log.Warnf("pipeline(%s) of %s is still %s", pipeline.WebURL, opc.FeatureBranchName, pipeline.Status)
+	case pipeline.Failed() && opc.IgnorePipeline:
+		log.Warnf("pipeline(%s) of %s has %s, release it anyway", pipeline.WebURL, opc.FeatureBranchName, pipeline.Status)
+	case pipeline.Failed():
+		return errors.Wrapf(errPipelineFailed, "pipeline(%s) of %s is %s, fix it or release with --ignore-pipeline",
+			pipeline.WebURL, opc.FeatureBranchName, pipeline.Status)
 	}
 
-	// check out one branch from target branch, and open a MergeRequest.
-	if err = f.featureProcessMR(resolveConflictBranch, targetBranch, opc.ForceCreateMergeRequest, opc.AutoMergeRequest); err != nil {
-		return err
+	return nil
+}
+
+// resolveConflictIfPredicted predicts conflicts of merging feature branch into targetBranch before
+// the merge request is opened. If conflicts are predicted, it offers to run FeatureResolveConflict
+// rather than opening a merge request which could not be merged, and true is returned if it has run.
+// It runs without asking if opc.ResolveConflict is true, and it's never run in non-interactive mode
+// unless opc.ResolveConflict is true. Conflicts could not be predicted is not an error.
+func (f flowImpl) resolveConflictIfPredicted(opc *types.OpFeatureContext, targetBranch types.BranchTyp) (bool, error) {
+	conflicted, files, err := f.predictConflicts(opc, targetBranch)
+	if err != nil {
+		log.
+			WithFields(log.Fields{"featureBranch": opc.FeatureBranchName, "targetBranch": targetBranch}).
+			Warnf("could not predict conflicts: %v", err)
+		return false, nil
+	}
+	if !conflicted {
+		return false, nil
 	}
 
-	// then local git command to use git merge
-	//  --no-ff `featureBranchName`
-	return f.gitOperator.Merge(opc.FeatureBranchName, resolveConflictBranch)
+	message := fmt.Sprintf("%s could not be merged into %s without conflicts", opc.FeatureBranchName, targetBranch)
+	if len(files) != 0 {
+		message += ", the following files are conflicted:\n" + formatFiles(files)
+	}
+
+	// go-git dry runs by files rather than lines, files changed by both branches may be merged cleanly,
+	// so the prediction is only advisory.
+	if len(files) != 0 && f.ctx.Config().GitBackend == gitop.BackendGoGit {
+		log.Warnf("%s\nthe prediction of go-git backend is by files, the merge request is opened anyway, "+
+			"run `feature resolve-conflict` if gitlab reports conflicts", message)
+		return false, nil
+	}
+	log.Warn(message)
+
+	if !opc.ResolveConflict && !confirmInteractively("Would you like to resolve conflicts in a conflict-resolve "+
+		"branch rather than opening the merge request?", false, f.ctx.NonInteractive()) {
+		if f.ctx.NonInteractive() {
+			log.Info("run with --resolve-conflict to resolve conflicts in a conflict-resolve branch instead")
+		}
+		return false, nil
+	}
+
+	return true, f.FeatureResolveConflict(opc, targetBranch)
 }
 
-func (f flowImpl) FeatureBeginIssue(opc *types.OpFeatureContext, title, desc string) error {
-	// DONE(@yeqown): is featureBranchName empty, use current branch name.
-	if opc.FeatureBranchName == "" {
-		opc.FeatureBranchName, _ = f.gitOperator.CurrentBranch()
+// predictConflicts checks the mergeability of opened merge request by gitlab, otherwise dry runs merging
+// the remote feature branch into the remote targetBranch locally. Conflicted files are returned by the
+// dry run only.
+func (f flowImpl) predictConflicts(
+	opc *types.OpFeatureContext, targetBranch types.BranchTyp) (conflicted bool, files []string, err error) {
+	ctx := context.Background()
+	if !opc.ForceCreateMergeRequest {
+		mr, err := f.repo.QueryMergeRequest(&repository.MergeRequestDO{
+			ProjectID:    f.ctx.Project().ID,
+			SourceBranch: opc.FeatureBranchName,
+			TargetBranch: targetBranch.String(),
+		})
+		if err != nil && !repository.IsErrNotFound(err) {
+			return false, nil, errors.Wrap(err, "query merge request failed")
+		}
+		if mr != nil && f.refreshMergeRequestState(ctx, mr) == nil && mr.State == repository.MergeRequestStateOpened {
+			conflicted, err = f.checkMergeRequestConflicts(ctx, mr.MergeRequestIID)
+			return conflicted, nil, err
+		}
 	}
-	if opc.FeatureBranchName == "" {
-		return errInvalidFeatureName
+
+	if err = f.gitOperator.FetchOrigin(); err != nil {
+		return false, nil, errors.Wrap(err, "fetch failed")
 	}
-	opc.FeatureBranchName = genFeatureBranchName(opc.FeatureBranchName)
+	files, err = f.gitOperator.MergeTree(f.remoteBranch(opc.FeatureBranchName), f.remoteBranch(targetBranch.String()))
+	if err != nil {
+		return false, nil, err
+	}
+
+	return len(files) != 0, files, nil
+}
+
+func (f flowImpl) FeaturePipeline(
+	opc *types.OpFeatureContext, targetBranch string, wait bool) (view *PipelineView, err error) {
+	if opc.FeatureBranchName, err = f.extractFeatureBranchName(opc); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	req := &gitlabop.GetLatestPipelineRequest{
+		ProjectID: f.ctx.Project().ID,
+		Ref:       opc.FeatureBranchName,
+	}
+
+	var mr *repository.MergeRequestDO
+	if targetBranch != "" {
+		mr, err = f.repo.QueryMergeRequest(&repository.MergeRequestDO{
+			ProjectID:    f.ctx.Project().ID,
+			SourceBranch: opc.FeatureBranchName,
+			TargetBranch: targetBranch,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "locate merge request into %s failed", targetBranch)
+		}
+		req.MergeRequestIID = mr.MergeRequestIID
+	}
+
+	var pipeline *gitlabop.PipelineShort
+	if wait {
+		pipeline, err = f.waitPipelineFinished(ctx, req)
+	} else {
+		pipeline, err = f.gitlabOperator.GetLatestPipeline(ctx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if mr != nil {
+		mr.PipelineStatus = pipeline.Status
+		if err = f.repo.UpdateMergeRequestState(mr); err != nil {
+			log.
+				WithFields(log.Fields{"mergeRequestIID": mr.MergeRequestIID}).
+				Warnf("could not save pipeline status of merge request: %v", err)
+		}
+	}
+
+	view = newPipelineView(pipeline, req.MergeRequestIID)
+	if wait && pipeline.Failed() {
+		return view, errors.Wrapf(errPipelineFailed, "pipeline(%s) is %s", pipeline.WebURL, pipeline.Status)
+	}
+
+	return view, nil
+}
+
+// featurePublishRelease wait the merge request from feature branch into types.MasterBranch to be merged,
+// then create the tag on the merge commit and publish a gitlab release with notes of the milestone.
+func (f flowImpl) featurePublishRelease(opc *types.OpFeatureContext) error {
+	ctx := context.Background()
+	projectID := f.ctx.Project().ID
 
 	featureBranch, err := f.repo.QueryBranch(&repository.BranchDO{
-		ProjectID:  f.ctx.Project().ID,
+		ProjectID:  projectID,
 		BranchName: opc.FeatureBranchName,
 	})
 	if err != nil {
-		return errors.Wrap(err, "locate feature branch from local failed")
+		return errors.Wrap(err, "locate feature branch failed")
 	}
-
-	// query milestone
 	milestone, err := f.repo.QueryMilestone(&repository.MilestoneDO{
-		ProjectID:   f.ctx.Project().ID,
+		ProjectID:   projectID,
 		MilestoneID: featureBranch.MilestoneID,
 	})
 	if err != nil {
 		return errors.Wrap(err, "locate milestone failed")
 	}
 
-	if len(title) == 0 {
-		title = milestone.Title
-	} else {
-		if err := blockingNamePrefix(title); err != nil {
-			return errors.Wrap(err, "blocking name prefix detected")
-		}
+	mr, err := f.repo.QueryMergeRequest(&repository.MergeRequestDO{
+		ProjectID:    projectID,
+		MilestoneID:  milestone.MilestoneID,
+		SourceBranch: opc.FeatureBranchName,
+		TargetBranch: types.MasterBranch.String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "locate feature merge request failed")
 	}
-	if len(desc) == 0 {
-		desc = milestone.Desc
+	if err = f.waitMergeRequestMerged(ctx, mr); err != nil {
+		return err
 	}
 
-	// create and save issue
-	issue, err := f.createIssue(title, desc, featureBranch.BranchName, milestone.MilestoneID)
+	issues, err := f.repo.QueryIssues(&repository.IssueDO{
+		ProjectID:   projectID,
+		MilestoneID: milestone.MilestoneID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "query issues failed")
+	}
+	mrs, err := f.repo.QueryMergeRequests(&repository.MergeRequestDO{
+		ProjectID:   projectID,
+		MilestoneID: milestone.MilestoneID,
+	})
 	if err != nil {
+		return errors.Wrap(err, "query merge requests failed")
+	}
+
+	notes := genReleaseNotes(opc.ReleaseTag, milestone.Title, milestone.Desc, issues, mrs)
+	return f.publishRelease(ctx, opc.ReleaseTag, mr.MergeCommitSHA, notes, []string{milestone.Title})
+}
+
+func (f flowImpl) FeatureResolveConflict(opc *types.OpFeatureContext, targetBranch types.BranchTyp) (err error) {
+	if opc.FeatureBranchName, err = f.extractFeatureBranchName(opc); err != nil {
 		return err
 	}
 
-	// create issue branch
-	issueBranchName := genIssueBranchName(milestone.Title, issue.IID)
-	_, err = f.createBranch(issueBranchName, featureBranch.BranchName, milestone.MilestoneID, issue.IID)
+	// feature/branch-1 => conflict-resolve/branch-1
+	resolveConflictBranch := strings.Replace(opc.FeatureBranchName, types.FeatureBranchPrefix, types.ConflictResolveBranchPrefix, 1)
+
+	// locate feature branch
+	featureBranch, err := f.repo.QueryBranch(&repository.BranchDO{
+		ProjectID:  f.ctx.Project().ID,
+		BranchName: opc.FeatureBranchName,
+	})
 	if err != nil {
-		return errors.Wrap(err, "create branch failed")
+		return errors.Wrap(err, "locate feature branch failed")
 	}
 
-	f.printAndOpenBrowser("Open Issue", issue.WebURL)
+	return f.resolveConflict(opc.FeatureBranchName, resolveConflictBranch, targetBranch, featureBranch.MilestoneID, 0,
+		func() error {
+			return f.featureProcessMR(
+				resolveConflictBranch, targetBranch, opc.ForceCreateMergeRequest, opc.AutoMergeRequest)
+		})
+}
+
+// resolveConflict checkout resolveConflictBranch from targetBranch, and open a merge request by openMR,
+// then merge srcBranch into resolveConflictBranch locally, so that conflicts could be resolved in
+// resolveConflictBranch rather than targetBranch. The merged resolveConflictBranch is pushed to update
+// the merge request, if there are conflicts, they should be resolved and pushed manually.
+func (f flowImpl) resolveConflict(
+	srcBranch, resolveConflictBranch string, targetBranch types.BranchTyp,
+	milestoneID, issueIID int, openMR func() error) error {
+	restore, err := f.guardWorkingTree(fmt.Sprintf("merge %s into %s", srcBranch, resolveConflictBranch), "")
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	// create resolve conflict branch.
+	// Notice: createBranch would create branch which checkout to new branch automatically.
+	if _, err := f.createBranch(resolveConflictBranch, targetBranch.String(), milestoneID, issueIID); err != nil {
+		return err
+	}
+
+	// check out one branch from target branch, and open a MergeRequest.
+	if err := openMR(); err != nil {
+		return err
+	}
+
+	// then local git command to use git merge
+	//  --no-ff `srcBranch`
+	if err := f.gitOperator.Merge(srcBranch, resolveConflictBranch); err != nil {
+		return errors.Wrapf(err, "merge %s into %s failed, please resolve conflicts, commit and push %s manually",
+			srcBranch, resolveConflictBranch, resolveConflictBranch)
+	}
+
+	if err := f.gitOperator.Push(resolveConflictBranch, true); err != nil {
+		return errors.Wrapf(err, "push %s failed, please push it manually", resolveConflictBranch)
+	}
 
 	return nil
 }
 
-// FeatureFinishIssue implements IFlow.FeatureFinishIssue
-// DONE(@yeqown): issue merge request should be called here, rather than FeatureBeginIssue
-func (f flowImpl) FeatureFinishIssue(opc *types.OpFeatureContext, issueBranchName string) (err error) {
-	// DONE(@yeqown): if issueBranchName is empty, make the current branch name as default.
+// FeatureUpdate implements IFlow.FeatureUpdate
+func (f flowImpl) FeatureUpdate(opc *types.OpFeatureContext, action types.UpdateAction) (err error) {
+	if opc.FeatureBranchName, err = f.extractFeatureBranchName(opc); err != nil {
+		return err
+	}
+
+	// locate feature branch
+	if _, err = f.repo.QueryBranch(&repository.BranchDO{
+		ProjectID:  f.ctx.Project().ID,
+		BranchName: opc.FeatureBranchName,
+	}); err != nil {
+		return errors.Wrap(err, "locate feature branch failed")
+	}
+
+	return f.updateBranch(opc.FeatureBranchName, types.MasterBranch.String(), opc.Rebase, action)
+}
+
+// FeatureUpdateIssue implements IFlow.FeatureUpdateIssue
+func (f flowImpl) FeatureUpdateIssue(
+	opc *types.OpFeatureContext, issueBranchName string, action types.UpdateAction) error {
 	if issueBranchName == "" {
 		issueBranchName, _ = f.gitOperator.CurrentBranch()
 	}
@@ -347,22 +706,32 @@ func (f flowImpl) FeatureFinishIssue(opc *types.OpFeatureContext, issueBranchNam
 		return errors.New("issue branch could not be empty")
 	}
 
-	var (
-		milestoneID = 0
-		issueIID    = 0
-	)
-	// locate issue branch.
-	if b, err := f.repo.QueryBranch(&repository.BranchDO{
+	// locate issue branch, the feature branch is the branch of the same milestone without issue.
+	issueBranch, err := f.repo.QueryBranch(&repository.BranchDO{
 		ProjectID:  f.ctx.Project().ID,
 		BranchName: issueBranchName,
-	}); err != nil {
+	})
+	if err != nil {
 		return errors.Wrapf(err, "locate issue branch(%s) failed", issueBranchName)
-	} else {
-		milestoneID = b.MilestoneID
-		issueIID = b.IssueIID
+	}
+	if issueBranch.IssueIID == 0 {
+		return errors.Errorf("%s is not an issue branch", issueBranchName)
 	}
 
-	// DONE(@yeqown) get feature branch name from issueBranchName
+	if opc.FeatureBranchName == "" {
+		branches, err := f.repo.QueryBranches(&repository.BranchDO{
+			ProjectID:   f.ctx.Project().ID,
+			MilestoneID: issueBranch.MilestoneID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "locate feature branch of milestone(%d) failed", issueBranch.MilestoneID)
+		}
+		if featureBranch, ok := lo.Find(branches, func(v *repository.BranchDO) bool {
+			return v.IssueIID == 0 && strings.HasPrefix(v.BranchName, types.FeatureBranchPrefix)
+		}); ok {
+			opc.FeatureBranchName = featureBranch.BranchName
+		}
+	}
 	if opc.FeatureBranchName == "" {
 		opc.FeatureBranchName = parseFeatureFromIssueName(issueBranchName, opc.ParseIssueCompatible)
 	}
@@ -370,11 +739,194 @@ func (f flowImpl) FeatureFinishIssue(opc *types.OpFeatureContext, issueBranchNam
 		return errInvalidFeatureName
 	}
 	opc.FeatureBranchName = genFeatureBranchName(opc.FeatureBranchName)
-	// if _, err := f.repo.QueryBranch(&repository.BranchDO{
-	//	ProjectID:   f.helperContext.Project().ID,
-	//	BranchName:  featureBranchName,
-	//	MilestoneID: milestoneID,
-	// }); err != nil {
+
+	return f.updateBranch(issueBranchName, opc.FeatureBranchName, opc.Rebase, action)
+}
+
+// updateBranch fetches and then merges the base of remote into branch, or rebases branch onto it
+// if rebase is true. Conflicts stop the update, and it could be continued or aborted by action.
+func (f flowImpl) updateBranch(branch, base string, rebase bool, action types.UpdateAction) error {
+	switch action {
+	case types.UpdateActionContinue:
+		status, err := f.gitOperator.Status()
+		if err != nil {
+			return errors.Wrap(err, "could not check working tree")
+		}
+		if len(status.Conflicted) != 0 {
+			return errors.Errorf("could not continue: the following files are still conflicted:\n%s\n"+
+				"please resolve conflicts and `git add` them first", formatFiles(status.Conflicted))
+		}
+		if err = f.gitOperator.Continue(); err != nil {
+			return errors.Wrapf(err, "continue updating %s failed", branch)
+		}
+		log.Infof("%s has been updated from %s", branch, base)
+		f.restoreAutoStash()
+		return nil
+	case types.UpdateActionAbort:
+		if err := f.gitOperator.Abort(); err != nil {
+			return errors.Wrapf(err, "abort updating %s failed", branch)
+		}
+		log.Infof("updating %s from %s has been aborted", branch, base)
+		f.restoreAutoStash()
+		return nil
+	}
+
+	restore, err := f.guardWorkingTree(fmt.Sprintf("update %s from %s", branch, base),
+		"they would be restored after running with --continue or --abort")
+	if err != nil {
+		return err
+	}
+	defer restore()
+
+	if currentBranch, _ := f.gitOperator.CurrentBranch(); currentBranch != branch {
+		if err = f.gitOperator.Checkout(branch, false); err != nil {
+			return errors.Wrapf(err, "checkout %s failed", branch)
+		}
+	}
+	if err = f.gitOperator.FetchOrigin(); err != nil {
+		return errors.Wrap(err, "fetch failed")
+	}
+
+	upstream := f.remoteBranch(base)
+	if rebase {
+		err = f.gitOperator.Rebase(upstream)
+	} else {
+		err = f.gitOperator.Merge(upstream, branch)
+	}
+	if err != nil {
+		if status, err2 := f.gitOperator.Status(); err2 == nil && status.InProgress != "" {
+			return errors.Wrapf(err, "update %s from %s stopped by conflicts, please resolve conflicts and "+
+				"`git add` them, then run with --continue, or run with --abort", branch, upstream)
+		}
+		return errors.Wrapf(err, "update %s from %s failed", branch, upstream)
+	}
+
+	if rebase {
+		log.Infof("%s has been rebased onto %s, push it with --force-with-lease if it has been pushed", branch, upstream)
+		return nil
+	}
+	log.Infof("%s has been updated from %s", branch, upstream)
+	return nil
+}
+
+// remoteBranch returns the name of remote-tracking branch of branch, e.g. "origin/master".
+func (f flowImpl) remoteBranch(branch string) string {
+	remote := f.ctx.Config().GitRemote
+	if remote == "" {
+		remote = gitop.DefaultRemote
+	}
+
+	return remote + "/" + branch
+}
+
+func (f flowImpl) FeatureBeginIssue(opc *types.OpFeatureContext, title, desc string) error {
+	// DONE(@yeqown): is featureBranchName empty, use current branch name.
+	if opc.FeatureBranchName == "" {
+		opc.FeatureBranchName, _ = f.gitOperator.CurrentBranch()
+	}
+	if opc.FeatureBranchName == "" {
+		return errInvalidFeatureName
+	}
+	opc.FeatureBranchName = genFeatureBranchName(opc.FeatureBranchName)
+
+	featureBranch, err := f.repo.QueryBranch(&repository.BranchDO{
+		ProjectID:  f.ctx.Project().ID,
+		BranchName: opc.FeatureBranchName,
+	})
+	if err != nil {
+		return errors.Wrap(err, "locate feature branch from local failed")
+	}
+
+	// query milestone
+	milestone, err := f.repo.QueryMilestone(&repository.MilestoneDO{
+		ProjectID:   f.ctx.Project().ID,
+		MilestoneID: featureBranch.MilestoneID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "locate milestone failed")
+	}
+
+	if len(title) == 0 {
+		title = milestone.Title
+	} else {
+		if err := blockingNamePrefix(title); err != nil {
+			return errors.Wrap(err, "blocking name prefix detected")
+		}
+	}
+	if len(desc) == 0 {
+		desc = milestone.Desc
+	}
+
+	// create and save issue
+	issue, err := f.createIssue(title, desc, featureBranch.BranchName, milestone.MilestoneID)
+	if err != nil {
+		return err
+	}
+
+	// create issue branch
+	issueBranchName := genIssueBranchName(milestone.Title, issue.IID)
+	_, err = f.createBranch(issueBranchName, featureBranch.BranchName, milestone.MilestoneID, issue.IID)
+	if err != nil {
+		return errors.Wrap(err, "create branch failed")
+	}
+
+	f.printAndOpenBrowser("Open Issue", issue.WebURL)
+
+	if opc.Draft {
+		// open a draft merge request so that CI runs on issue branch early, it would be
+		// marked as ready by FeatureFinishIssue.
+		result, err := f.createMergeRequest(
+			genMergeRequestName(issueBranchName, featureBranch.BranchName), "",
+			milestone.MilestoneID, issue.IID, issueBranchName, featureBranch.BranchName, false, true)
+		if err != nil {
+			return errors.Wrap(err, "create draft merge request failed")
+		}
+
+		f.printAndOpenBrowser("Draft Merge Request", result.WebURL)
+	}
+
+	return nil
+}
+
+// FeatureFinishIssue implements IFlow.FeatureFinishIssue
+// DONE(@yeqown): issue merge request should be called here, rather than FeatureBeginIssue
+func (f flowImpl) FeatureFinishIssue(opc *types.OpFeatureContext, issueBranchName string) (err error) {
+	// DONE(@yeqown): if issueBranchName is empty, make the current branch name as default.
+	if issueBranchName == "" {
+		issueBranchName, _ = f.gitOperator.CurrentBranch()
+	}
+	if issueBranchName == "" {
+		return errors.New("issue branch could not be empty")
+	}
+
+	var (
+		milestoneID = 0
+		issueIID    = 0
+	)
+	// locate issue branch.
+	if b, err := f.repo.QueryBranch(&repository.BranchDO{
+		ProjectID:  f.ctx.Project().ID,
+		BranchName: issueBranchName,
+	}); err != nil {
+		return errors.Wrapf(err, "locate issue branch(%s) failed", issueBranchName)
+	} else {
+		milestoneID = b.MilestoneID
+		issueIID = b.IssueIID
+	}
+
+	// DONE(@yeqown) get feature branch name from issueBranchName
+	if opc.FeatureBranchName == "" {
+		opc.FeatureBranchName = parseFeatureFromIssueName(issueBranchName, opc.ParseIssueCompatible)
+	}
+	if opc.FeatureBranchName == "" {
+		return errInvalidFeatureName
+	}
+	opc.FeatureBranchName = genFeatureBranchName(opc.FeatureBranchName)
+	// if _, err := f.repo.QueryBranch(&repository.BranchDO{
+	//	ProjectID:   f.helperContext.Project().ID,
+	//	BranchName:  featureBranchName,
+	//	MilestoneID: milestoneID,
+	// }); err != nil {
 	//	return errors.Wrapf(err, "locate feature branch(%s) failed", featureBranchName)
 	// }
 
@@ -414,6 +966,16 @@ func (f flowImpl) FeatureFinishIssue(opc *types.OpFeatureContext, issueBranchNam
 			}).
 			Debug("issue info")
 
+		if f.shouldRecreateMergeRequest(mr) {
+			goto issueCreateMR
+		}
+
+		if mr.Draft && mr.State == repository.MergeRequestStateOpened {
+			if err = f.markMergeRequestReady(mr, opc.AutoMergeRequest); err != nil {
+				return err
+			}
+		}
+
 		f.printAndOpenBrowser("Issue Merge Request", mr.WebURL)
 		return nil
 	}
@@ -422,7 +984,7 @@ issueCreateMR:
 	title := genMergeRequestName(issueBranchName, opc.FeatureBranchName)
 	desc := ""
 	result, err := f.createMergeRequest(
-		title, desc, milestoneID, issueIID, issueBranchName, opc.FeatureBranchName, opc.AutoMergeRequest)
+		title, desc, milestoneID, issueIID, issueBranchName, opc.FeatureBranchName, opc.AutoMergeRequest, false)
 	if err != nil {
 		return errors.Wrap(err, "create issue merge request failed")
 	}
@@ -440,7 +1002,283 @@ issueCreateMR:
 	return nil
 }
 
-func (f flowImpl) Checkout(opc *types.OpFeatureContext, listAll bool, issueID int) {
+// markMergeRequestReady marks the draft merge request as ready for review, then merges it
+// automatically if autoMerge is true.
+func (f flowImpl) markMergeRequestReady(mr *repository.MergeRequestDO, autoMerge bool) error {
+	ctx := context.Background()
+	result, err := f.gitlabOperator.UpdateMergeRequest(ctx, &gitlabop.UpdateMergeRequestRequest{
+		MergeRequestIID: mr.MergeRequestIID,
+		ProjectID:       mr.ProjectID,
+		Draft:           gogitlab.Ptr(false),
+	})
+	if err != nil {
+		return errors.Wrap(err, "mark merge request as ready failed")
+	}
+
+	mr.Draft = result.Draft
+	if err = f.repo.UpdateMergeRequestState(mr); err != nil {
+		log.
+			WithFields(log.Fields{"mergeRequestIID": mr.MergeRequestIID}).
+			Warnf("could not save merge request state: %v", err)
+	}
+	log.Infof("merge request(!%d) has been marked as ready", mr.MergeRequestIID)
+
+	if autoMerge {
+		if err = f.autoMergeMR(ctx, mr.MergeRequestIID, mr.SourceBranch, mr.TargetBranch); err != nil {
+			log.WithFields(log.Fields{"URL": mr.WebURL, "mergeRequestIID": mr.MergeRequestIID}).
+				Warnf("auto merge failed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// FeatureClose implements IFlow.FeatureClose
+func (f flowImpl) FeatureClose(opc *types.OpFeatureContext, deleteBranch bool) (err error) {
+	if opc.FeatureBranchName, err = f.extractFeatureBranchName(opc); err != nil {
+		return err
+	}
+
+	var (
+		ctx       = context.Background()
+		projectID = f.ctx.Project().ID
+	)
+
+	featureBranch, err := f.repo.QueryBranch(&repository.BranchDO{
+		ProjectID:  projectID,
+		BranchName: opc.FeatureBranchName,
+	})
+	if err != nil {
+		return errors.Wrap(err, "locate feature branch failed")
+	}
+	milestoneID := featureBranch.MilestoneID
+
+	// the feature could be merged into master by feature branch or conflict-resolve branch,
+	// so all merge requests into master of the milestone should be checked.
+	mrs, err := f.repo.QueryMergeRequests(&repository.MergeRequestDO{
+		ProjectID:    projectID,
+		MilestoneID:  milestoneID,
+		TargetBranch: types.MasterBranch.String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "query merge requests failed")
+	}
+
+	// the feature could also be released by a release branch.
+	if !f.anyMergeRequestMerged(ctx, mrs) && !f.featureReleasedByRelease(ctx, projectID, milestoneID) {
+		return errors.Wrapf(errFeatureNotReleased, "feature(%s)", opc.FeatureBranchName)
+	}
+
+	// close leftover issues, the local issue is kept open if it could not be closed in gitlab,
+	// so that it could be closed by running again.
+	issues, err := f.repo.QueryIssues(&repository.IssueDO{
+		ProjectID:   projectID,
+		MilestoneID: milestoneID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "query issues failed")
+	}
+	var failedIssues []string
+	for _, issue := range issues {
+		if issue.ClosedAt != nil {
+			continue
+		}
+
+		if err = f.gitlabOperator.CloseIssue(ctx, &gitlabop.CloseIssueRequest{
+			IssueIID:  issue.IssueIID,
+			ProjectID: projectID,
+		}); err != nil {
+			log.
+				WithFields(log.Fields{"issueIID": issue.IssueIID, "URL": issue.WebURL}).
+				Warnf("could not close issue: %v", err)
+			failedIssues = append(failedIssues, fmt.Sprintf("#%d", issue.IssueIID))
+			continue
+		}
+		if err = f.repo.CloseIssue(projectID, milestoneID, issue.IssueIID); err != nil {
+			log.
+				WithFields(log.Fields{"issueIID": issue.IssueIID}).
+				Warnf("could not close local issue: %v", err)
+		}
+	}
+	if len(failedIssues) != 0 {
+		return errors.Errorf("could not close issues %s of feature(%s), please close the feature again",
+			strings.Join(failedIssues, ", "), opc.FeatureBranchName)
+	}
+
+	// close milestone, group milestone is closed in the group.
+	groupID := 0
+	if milestone, err2 := f.repo.QueryMilestone(&repository.MilestoneDO{
+		ProjectID:   projectID,
+		MilestoneID: milestoneID,
+	}); err2 == nil {
+		groupID = milestone.GroupID
+	}
+	if err = f.gitlabOperator.CloseMilestone(ctx, &gitlabop.CloseMilestoneRequest{
+		MilestoneID: milestoneID,
+		ProjectID:   projectID,
+		GroupID:     groupID,
+	}); err != nil {
+		return errors.Wrap(err, "close milestone failed")
+	}
+	if err = f.repo.CloseMilestone(projectID, milestoneID); err != nil {
+		return errors.Wrap(err, "close local milestone failed")
+	}
+
+	// mark all merge requests of the milestone as closed.
+	allMRs, err := f.repo.QueryMergeRequests(&repository.MergeRequestDO{
+		ProjectID:   projectID,
+		MilestoneID: milestoneID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "query merge requests failed")
+	}
+	for _, mr := range allMRs {
+		if mr.ClosedAt != nil {
+			continue
+		}
+		if err = f.repo.CloseMergeRequest(projectID, milestoneID, mr.MergeRequestIID); err != nil {
+			log.
+				WithFields(log.Fields{"mergeRequestIID": mr.MergeRequestIID}).
+				Warnf("could not close local merge request: %v", err)
+		}
+	}
+
+	if deleteBranch {
+		f.featureDeleteBranches(ctx, milestoneID)
+	}
+
+	log.
+		WithFields(log.Fields{
+			"featureBranch": opc.FeatureBranchName,
+			"milestoneID":   milestoneID,
+			"deleteBranch":  deleteBranch,
+		}).
+		Info("feature has been closed")
+
+	return nil
+}
+
+// featureReleasedByRelease returns true if the milestone has been attached to a release which has
+// been closed, and it has been merged into the release branch. Closed release means it has been merged
+// into master, but the feature could be attached after that, or its merge request could be closed.
+func (f flowImpl) featureReleasedByRelease(ctx context.Context, projectID, milestoneID int) bool {
+	attached, err := f.repo.QueryReleaseMilestones(&repository.ReleaseMilestoneDO{
+		ProjectID:   projectID,
+		MilestoneID: milestoneID,
+	})
+	if err != nil {
+		log.Warnf("could not query releases of milestone(%d): %v", milestoneID, err)
+		return false
+	}
+
+	for _, v := range attached {
+		release, err := f.repo.QueryRelease(&repository.ReleaseDO{
+			ProjectID:  projectID,
+			BranchName: v.ReleaseBranch,
+		})
+		if err != nil {
+			log.
+				WithFields(log.Fields{"releaseBranch": v.ReleaseBranch}).
+				Warnf("could not locate release: %v", err)
+			continue
+		}
+		if release.ClosedAt == nil {
+			continue
+		}
+
+		// the feature could be merged into release by feature branch or conflict-resolve branch.
+		mrs, err := f.repo.QueryMergeRequests(&repository.MergeRequestDO{
+			ProjectID:    projectID,
+			MilestoneID:  milestoneID,
+			TargetBranch: release.BranchName,
+		})
+		if err != nil {
+			log.
+				WithFields(log.Fields{"releaseBranch": v.ReleaseBranch}).
+				Warnf("could not query merge requests into release: %v", err)
+			continue
+		}
+		if f.anyMergeRequestMerged(ctx, mrs) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// anyMergeRequestMerged refreshes states of mrs, and returns true if any of them has been merged.
+func (f flowImpl) anyMergeRequestMerged(ctx context.Context, mrs []*repository.MergeRequestDO) bool {
+	for _, mr := range mrs {
+		if err := f.refreshMergeRequestState(ctx, mr); err != nil {
+			log.
+				WithFields(log.Fields{"mergeRequestIID": mr.MergeRequestIID, "URL": mr.WebURL}).
+				Warnf("could not refresh merge request state: %v", err)
+			continue
+		}
+
+		if mr.IsMerged() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// featureDeleteBranches delete all branches related to the milestone both locally and remotely,
+// it checkouts to types.MasterBranch first if the current branch is one of them.
+func (f flowImpl) featureDeleteBranches(ctx context.Context, milestoneID int) {
+	branches, err := f.repo.QueryBranches(&repository.BranchDO{
+		ProjectID:   f.ctx.Project().ID,
+		MilestoneID: milestoneID,
+	})
+	if err != nil {
+		log.Warnf("could not query branches of milestone(%d): %v", milestoneID, err)
+		return
+	}
+
+	currentBranch, _ := f.gitOperator.CurrentBranch()
+	_, onDeleting := lo.Find(branches, func(v *repository.BranchDO) bool {
+		return v.BranchName == currentBranch
+	})
+	if onDeleting {
+		restore, err := f.guardWorkingTree("checkout "+types.MasterBranch.String(), "")
+		if err != nil {
+			log.Warnf("skip deleting branches: %v", err)
+			return
+		}
+		defer restore()
+
+		if err = f.gitOperator.Checkout(types.MasterBranch.String(), false); err != nil {
+			log.Warnf("could not checkout to %s, skip deleting branches: %v", types.MasterBranch, err)
+			return
+		}
+	}
+
+	for _, b := range branches {
+		if !notBuiltinBranch(b.BranchName) {
+			continue
+		}
+
+		if err = f.gitlabOperator.DeleteBranch(ctx, &gitlabop.DeleteBranchRequest{
+			BranchName: b.BranchName,
+			ProjectID:  f.ctx.Project().ID,
+		}); err != nil {
+			log.
+				WithFields(log.Fields{"branch": b.BranchName}).
+				Warnf("could not delete remote branch: %v", err)
+		}
+
+		// the branch has been merged on the remote, but local master may be behind,
+		// so force deleting is necessary.
+		if err = f.gitOperator.DeleteLocalBranch(b.BranchName, true); err != nil {
+			log.
+				WithFields(log.Fields{"branch": b.BranchName}).
+				Warnf("could not delete local branch: %v", err)
+		}
+	}
+}
+
+func (f flowImpl) Checkout(opc *types.OpFeatureContext, listAll bool, issueID int) *FeatureBranchesView {
 	currentBranch, _ := f.gitOperator.CurrentBranch()
 	// the current branch must be feature branch or issue branch
 	featureBranch, matched := tryParseFeatureNameFrom(currentBranch, false)
@@ -448,7 +1286,7 @@ func (f flowImpl) Checkout(opc *types.OpFeatureContext, listAll bool, issueID in
 		log.
 			WithFields(log.Fields{"currentBranch": currentBranch}).
 			Warn("current branch is not feature branch or issue branch, could not checkout")
-		return
+		return nil
 	}
 
 	log.
@@ -461,7 +1299,14 @@ func (f flowImpl) Checkout(opc *types.OpFeatureContext, listAll bool, issueID in
 		Debug("Checkout called")
 
 	checkout := func(branchName string) {
-		if err := f.gitOperator.Checkout(branchName, false); err != nil {
+		restore, err := f.guardWorkingTree("checkout "+branchName, "")
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		defer restore()
+
+		if err = f.gitOperator.Checkout(branchName, false); err != nil {
 			log.
 				WithFields(log.Fields{
 					"branchName": branchName,
@@ -476,12 +1321,12 @@ func (f flowImpl) Checkout(opc *types.OpFeatureContext, listAll bool, issueID in
 	if !listAll && issueID == 0 {
 		if currentBranch == featureBranch {
 			// do nothing
-			return
+			return nil
 		}
 
 		// default is to check out feature branch
 		checkout(featureBranch)
-		return
+		return nil
 	}
 
 	// locate feature branch and milestoneID
@@ -492,13 +1337,14 @@ func (f flowImpl) Checkout(opc *types.OpFeatureContext, listAll bool, issueID in
 	if err != nil {
 		log.WithFields(log.Fields{"error": err, "branch": featureBranch, "projectID": f.ctx.Project().ID}).
 			Error("query branch failed")
-		return
+		return nil
 	}
 	branches, _ := f.repo.QueryBranches(&repository.BranchDO{
 		ProjectID:   f.ctx.Project().ID,
 		MilestoneID: branch.MilestoneID,
 	})
 
+	var view *FeatureBranchesView
 	if listAll {
 		issues, _ := f.repo.QueryIssues(&repository.IssueDO{
 			ProjectID:   f.ctx.Project().ID,
@@ -520,17 +1366,17 @@ func (f flowImpl) Checkout(opc *types.OpFeatureContext, listAll bool, issueID in
 			return "untitled"
 		}
 
-		fmt.Println()
-		fmt.Printf("\t🚀 ISSURE IID \tBRANCH NAME \t\tISSUE NAME\n")
+		view = &FeatureBranchesView{
+			FeatureBranch: featureBranch,
+			Branches:      make([]*FeatureBranchView, 0, len(branches)),
+		}
 		for _, v := range branches {
-			issueIDStr := pkg.FormatNum(v.IssueIID, 5)
-			if v.IssueIID == 0 {
-				fmt.Printf("\t🦁 #%s\t%s \t%s\n", issueIDStr, v.BranchName, "*FEATURE-BRANCH")
-				continue
+			b := &FeatureBranchView{IssueIID: v.IssueIID, BranchName: v.BranchName}
+			if v.IssueIID != 0 {
+				b.IssueTitle = getIssueName(v.IssueIID)
 			}
-			fmt.Printf("\t🐔 #%s\t%s\t%s\n", issueIDStr, v.BranchName, getIssueName(v.IssueIID))
+			view.Branches = append(view.Branches, b)
 		}
-		fmt.Println()
 	}
 
 	if issueID != 0 {
@@ -541,16 +1387,29 @@ func (f flowImpl) Checkout(opc *types.OpFeatureContext, listAll bool, issueID in
 			log.
 				WithFields(log.Fields{"issueID": issueID}).
 				Warn("could not locate issue branch")
-			return
+			return view
 		}
 
 		checkout(b.BranchName)
 	}
+
+	return view
 }
 
 func (f flowImpl) HotfixBegin(opc *types.OpHotfixContext, title, desc string) error {
 	hotfixBranchName := genHotfixBranchName(title)
 
+	if f.ctx.Config().Semver {
+		// hotfix always bumps the patch version, it's only a suggestion which could be
+		// used as the tag while closing the hotfix.
+		suggested, err := f.suggestNextVersion(pkg.BumpPatch)
+		if err != nil {
+			log.Warnf("could not suggest hotfix version: %v", err)
+		} else {
+			log.Infof("suggested hotfix version: %s, tag it by `hotfix close -t %s`", suggested, suggested)
+		}
+	}
+
 	// create ISSUE
 	issue, err := f.createIssue(title, desc, hotfixBranchName, 0)
 	if err != nil {
@@ -565,78 +1424,497 @@ func (f flowImpl) HotfixBegin(opc *types.OpHotfixContext, title, desc string) er
 
 	log.
 		WithFields(log.Fields{
-			"issue":  issue,
-			"branch": branch,
+			"issue":  issue,
+			"branch": branch,
+		}).
+		Debug("hotfix begin finished")
+
+	return nil
+}
+
+func (f flowImpl) HotfixFinish(opc *types.OpHotfixContext, hotfixBranchName string) error {
+	if hotfixBranchName == "" {
+		hotfixBranchName, _ = f.gitOperator.CurrentBranch()
+	}
+
+	hotfixBranchName = genHotfixBranchName(hotfixBranchName)
+	_, err := f.repo.QueryBranch(&repository.BranchDO{
+		ProjectID:  f.ctx.Project().ID,
+		BranchName: hotfixBranchName,
+	})
+	if err != nil {
+		return errors.Wrap(err, "locate hotfix branch failed")
+	}
+
+	// locate issue
+	issue, err := f.repo.QueryIssue(&repository.IssueDO{
+		ProjectID:     f.ctx.Project().ID,
+		RelatedBranch: hotfixBranchName,
+	})
+	if err != nil {
+		return errors.Wrap(err, "locate issue failed")
+	}
+
+	if err = f.hotfixProcessMR(opc, hotfixBranchName, issue); err != nil {
+		return err
+	}
+
+	if opc.Backport {
+		if err = f.hotfixBackport(opc, hotfixBranchName, issue); err != nil {
+			return err
+		}
+	}
+
+	if opc.ReleaseTag != "" {
+		return f.hotfixPublishRelease(opc, hotfixBranchName, issue)
+	}
+
+	return nil
+}
+
+// hotfixPublishRelease wait the merge request from hotfix branch into types.MasterBranch to be merged,
+// then create the tag on the merge commit and publish a gitlab release with notes of the hotfix.
+func (f flowImpl) hotfixPublishRelease(
+	opc *types.OpHotfixContext, hotfixBranchName string, issue *repository.IssueDO) error {
+	ctx := context.Background()
+
+	mr, err := f.repo.QueryMergeRequest(&repository.MergeRequestDO{
+		ProjectID:    f.ctx.Project().ID,
+		IssueIID:     issue.IssueIID,
+		SourceBranch: hotfixBranchName,
+		TargetBranch: types.MasterBranch.String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "locate hotfix merge request failed")
+	}
+	if err = f.waitMergeRequestMerged(ctx, mr); err != nil {
+		return err
+	}
+
+	mrs, err := f.repo.QueryMergeRequests(&repository.MergeRequestDO{
+		ProjectID: f.ctx.Project().ID,
+		IssueIID:  issue.IssueIID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "query merge requests failed")
+	}
+
+	notes := genReleaseNotes(opc.ReleaseTag, issue.Title, issue.Desc, []*repository.IssueDO{issue}, mrs)
+	return f.publishRelease(ctx, opc.ReleaseTag, mr.MergeCommitSHA, notes, nil)
+}
+
+// hotfixProcessMR open the merge request from hotfix branch into types.MasterBranch, the merge request
+// would be located from local first.
+func (f flowImpl) hotfixProcessMR(
+	opc *types.OpHotfixContext, hotfixBranchName string, issue *repository.IssueDO) (err error) {
+	var mr *repository.MergeRequestDO
+	if opc.ForceCreateMergeRequest {
+		goto hotfixCreateMR
+	}
+
+	// locate MR first
+	mr, err = f.repo.QueryMergeRequest(&repository.MergeRequestDO{
+		ProjectID:   f.ctx.Project().ID,
+		MilestoneID: issue.MilestoneID,
+		IssueIID:    issue.IssueIID,
+		// SourceBranch:   "",
+		TargetBranch: types.MasterBranch.String(),
+	})
+	if err != nil && !repository.IsErrNotFound(err) {
+		return errors.Wrap(err, "query database failed")
+	}
+
+	// hit hotfix merge request
+	if mr != nil {
+		if f.shouldRecreateMergeRequest(mr) {
+			goto hotfixCreateMR
+		}
+
+		f.printAndOpenBrowser("Hotfix Merge Request", mr.WebURL)
+		return nil
+	}
+
+hotfixCreateMR:
+	// then create MR to master
+	masterBranch := types.MasterBranch.String()
+	title := genMergeRequestName(hotfixBranchName, masterBranch)
+	result, err := f.createMergeRequest(
+		title, issue.Desc, 0, issue.IssueIID, hotfixBranchName, masterBranch, false, true)
+	if err != nil {
+		return errors.Wrap(err, "create hotfix MR failed")
+	}
+
+	f.printAndOpenBrowser("Hotfix Merge Request", result.WebURL)
+
+	log.
+		WithFields(log.Fields{
+			"issue":        issue,
+			"mergeRequest": result,
+		}).
+		Debug("hotfix finish done")
+
+	return nil
+}
+
+// hotfixBackport open merge requests from hotfix branch into types.DevBranch and types.TestBranch.
+// If the merge request has conflicts, a conflict-resolve branch would be checked out from the target
+// branch, and hotfix branch would be merged into it locally, then user could resolve conflicts there.
+func (f flowImpl) hotfixBackport(
+	opc *types.OpHotfixContext, hotfixBranchName string, issue *repository.IssueDO) error {
+	ctx := context.Background()
+
+	for _, targetBranch := range []types.BranchTyp{types.DevBranch, types.TestBranch} {
+		target := targetBranch.String()
+		mergeRequestIID, webURL := 0, ""
+		if !opc.ForceCreateMergeRequest {
+			mr, err := f.repo.QueryMergeRequest(&repository.MergeRequestDO{
+				ProjectID:    f.ctx.Project().ID,
+				IssueIID:     issue.IssueIID,
+				SourceBranch: hotfixBranchName,
+				TargetBranch: target,
+			})
+			if err != nil && !repository.IsErrNotFound(err) {
+				return errors.Wrap(err, "query database failed")
+			}
+
+			if mr != nil && !f.shouldRecreateMergeRequest(mr) {
+				f.printAndOpenBrowser("Hotfix Backport Merge Request", mr.WebURL)
+				if mr.State != repository.MergeRequestStateOpened {
+					continue
+				}
+				// the opened merge request may have conflicts too.
+				mergeRequestIID, webURL = mr.MergeRequestIID, mr.WebURL
+			}
+		}
+
+		if mergeRequestIID == 0 {
+			title := genMergeRequestName(hotfixBranchName, target)
+			result, err := f.createMergeRequest(
+				title, issue.Desc, 0, issue.IssueIID, hotfixBranchName, target, false, true)
+			if err != nil {
+				return errors.Wrapf(err, "create hotfix backport MR into %s failed", target)
+			}
+			f.printAndOpenBrowser("Hotfix Backport Merge Request", result.WebURL)
+			mergeRequestIID, webURL = result.IID, result.WebURL
+		}
+
+		hasConflicts, err := f.checkMergeRequestConflicts(ctx, mergeRequestIID)
+		if err != nil {
+			log.
+				WithFields(log.Fields{"mergeRequestIID": mergeRequestIID, "URL": webURL}).
+				Warnf("could not check conflicts of merge request: %v", err)
+			continue
+		}
+		if !hasConflicts {
+			continue
+		}
+
+		// hotfix/branch-1 => conflict-resolve/branch-1-to-develop
+		resolveConflictBranch := genConflictResolveBranchName(hotfixBranchName, target)
+		log.
+			WithFields(log.Fields{"targetBranch": target, "resolveConflictBranch": resolveConflictBranch}).
+			Warn("hotfix backport has conflicts, resolve them in conflict-resolve branch")
+
+		if err = f.resolveConflict(hotfixBranchName, resolveConflictBranch, targetBranch, 0, issue.IssueIID,
+			func() error {
+				title := genMergeRequestName(resolveConflictBranch, target)
+				result, err := f.createMergeRequest(
+					title, issue.Desc, 0, issue.IssueIID, resolveConflictBranch, target, false, true)
+				if err != nil {
+					return errors.Wrap(err, "create conflict-resolve MR failed")
+				}
+
+				f.printAndOpenBrowser("Conflict Resolve Merge Request", result.WebURL)
+				return nil
+			}); err != nil {
+			// conflicts are left in the working tree, so the other target branches should be
+			// processed after conflicts resolved.
+			return errors.Wrapf(err, "merge %s into %s failed, resolve conflicts and commit, "+
+				"then run hotfix close --backport again", hotfixBranchName, resolveConflictBranch)
+		}
+	}
+
+	return nil
+}
+
+// checkMergeRequestConflicts wait gitlab to finish checking the mergeability of the merge request,
+// then return whether the merge request has conflicts.
+func (f flowImpl) checkMergeRequestConflicts(ctx context.Context, mergeRequestIID int) (bool, error) {
+	retryBackoff := backoff.NewExponentialBackOff()
+	retryBackoff.MaxElapsedTime = 15 * time.Second
+	retryBackoff.MaxInterval = 4 * time.Second
+	retryBackoff.InitialInterval = 1 * time.Second
+
+	var result *gitlabop.GetMergeRequestResult
+	err := backoff.Retry(func() error {
+		r, err := f.gitlabOperator.GetMergeRequest(ctx, &gitlabop.GetMergeRequestRequest{
+			MergeRequestIID: mergeRequestIID,
+			ProjectID:       f.ctx.Project().ID,
+		})
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		result = r
+		if r.MergeStatusChecking() {
+			return fmt.Errorf("mergeability is still checking: %s", r.DetailedMergeStatus)
+		}
+		return nil
+	}, retryBackoff)
+	if err != nil {
+		return false, err
+	}
+
+	return result.HasConflicts, nil
+}
+
+func (f flowImpl) ReleaseBegin(opc *types.OpReleaseContext, version, desc string) error {
+	log.
+		WithFields(log.Fields{
+			"version": version,
+			"desc":    desc,
+		}).
+		Debug("ReleaseBegin called")
+
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return errInvalidReleaseName
+	}
+	releaseBranchName := genReleaseBranchName(version)
+	version = strings.TrimPrefix(releaseBranchName, types.ReleaseBranchPrefix)
+	baseBranch := types.ReleaseBaseBranch.String()
+
+	if _, err := f.createBranch(releaseBranchName, baseBranch, 0, 0); err != nil {
+		return err
+	}
+
+	if err := f.repo.SaveRelease(&repository.ReleaseDO{
+		ProjectID:  f.ctx.Project().ID,
+		Version:    version,
+		Desc:       strings.TrimSpace(desc),
+		BranchName: releaseBranchName,
+		BaseBranch: baseBranch,
+	}); err != nil {
+		return errors.Wrap(err, "save release failed")
+	}
+
+	log.
+		WithFields(log.Fields{
+			"releaseBranch": releaseBranchName,
+			"baseBranch":    baseBranch,
 		}).
-		Debug("hotfix begin finished")
+		Info("release branch has been created")
 
 	return nil
 }
 
-func (f flowImpl) HotfixFinish(opc *types.OpHotfixContext, hotfixBranchName string) error {
-	if hotfixBranchName == "" {
-		hotfixBranchName, _ = f.gitOperator.CurrentBranch()
+// locateRelease locate the release from local by opc.ReleaseBranchName, the current branch
+// would be used if opc.ReleaseBranchName is empty.
+func (f flowImpl) locateRelease(opc *types.OpReleaseContext) (*repository.ReleaseDO, error) {
+	if opc.ReleaseBranchName == "" {
+		currentBranch, _ := f.gitOperator.CurrentBranch()
+		if isReleaseName(currentBranch) {
+			opc.ReleaseBranchName = currentBranch
+		}
+	}
+	if opc.ReleaseBranchName == "" {
+		return nil, errInvalidReleaseName
 	}
+	opc.ReleaseBranchName = genReleaseBranchName(opc.ReleaseBranchName)
 
-	hotfixBranchName = genHotfixBranchName(hotfixBranchName)
-	_, err := f.repo.QueryBranch(&repository.BranchDO{
+	release, err := f.repo.QueryRelease(&repository.ReleaseDO{
 		ProjectID:  f.ctx.Project().ID,
-		BranchName: hotfixBranchName,
+		BranchName: opc.ReleaseBranchName,
 	})
 	if err != nil {
-		return errors.Wrap(err, "locate hotfix branch failed")
+		return nil, errors.Wrapf(err, "locate release(%s) failed", opc.ReleaseBranchName)
 	}
 
-	// locate issue
-	issue, err := f.repo.QueryIssue(&repository.IssueDO{
-		ProjectID:     f.ctx.Project().ID,
-		RelatedBranch: hotfixBranchName,
-	})
+	return release, nil
+}
+
+func (f flowImpl) ReleaseAttach(opc *types.OpReleaseContext, featureBranchNames []string) error {
+	release, err := f.locateRelease(opc)
 	if err != nil {
-		return errors.Wrap(err, "locate issue failed")
+		return err
+	}
+	if release.ClosedAt != nil {
+		return errors.Wrapf(errReleaseClosed, "release(%s)", release.BranchName)
+	}
+	if len(featureBranchNames) == 0 {
+		return errInvalidFeatureName
+	}
+
+	for _, name := range featureBranchNames {
+		featureBranchName := genFeatureBranchName(name)
+		featureBranch, err := f.repo.QueryBranch(&repository.BranchDO{
+			ProjectID:  f.ctx.Project().ID,
+			BranchName: featureBranchName,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "locate feature branch(%s) failed", featureBranchName)
+		}
+
+		if err = f.repo.SaveReleaseMilestone(&repository.ReleaseMilestoneDO{
+			ProjectID:     f.ctx.Project().ID,
+			ReleaseBranch: release.BranchName,
+			MilestoneID:   featureBranch.MilestoneID,
+			FeatureBranch: featureBranchName,
+		}); err != nil {
+			return errors.Wrapf(err, "attach feature(%s) failed", featureBranchName)
+		}
+
+		if err = f.featureProcessMR(featureBranchName, types.BranchTyp(release.BranchName),
+			opc.ForceCreateMergeRequest, opc.AutoMergeRequest); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f flowImpl) ReleaseFinish(opc *types.OpReleaseContext) (err error) {
+	release, err := f.locateRelease(opc)
+	if err != nil {
+		return err
 	}
 
+	masterBranch := types.MasterBranch.String()
 	var mr *repository.MergeRequestDO
 	if opc.ForceCreateMergeRequest {
-		goto hotfixCreateMR
+		goto releaseCreateMR
 	}
 
-	// locate MR first
 	mr, err = f.repo.QueryMergeRequest(&repository.MergeRequestDO{
-		ProjectID:   f.ctx.Project().ID,
-		MilestoneID: issue.MilestoneID,
-		IssueIID:    issue.IssueIID,
-		// SourceBranch:   "",
-		TargetBranch: types.MasterBranch.String(),
+		ProjectID:    f.ctx.Project().ID,
+		SourceBranch: release.BranchName,
+		TargetBranch: masterBranch,
 	})
 	if err != nil && !repository.IsErrNotFound(err) {
-		return errors.Wrap(err, "query database failed")
+		return errors.Wrap(err, "query merge request failed")
 	}
-
-	// hit hotfix merge request
 	if mr != nil {
-		f.printAndOpenBrowser("Hotfix Merge Request", mr.WebURL)
+		if f.shouldRecreateMergeRequest(mr) {
+			goto releaseCreateMR
+		}
+
+		f.printAndOpenBrowser("Release Merge Request", mr.WebURL)
 		return nil
 	}
 
-hotfixCreateMR:
-	// then create MR to master
-	masterBranch := types.MasterBranch.String()
-	title := genMergeRequestName(hotfixBranchName, masterBranch)
+releaseCreateMR:
+	title := genMergeRequestName(release.BranchName, masterBranch)
 	result, err := f.createMergeRequest(
-		title, issue.Desc, 0, issue.IssueIID, hotfixBranchName, masterBranch, false)
+		title, f.genReleaseDescription(release), 0, 0,
+		release.BranchName, masterBranch, opc.AutoMergeRequest, !opc.AutoMergeRequest)
 	if err != nil {
-		return errors.Wrap(err, "create hotfix MR failed")
+		return errors.Wrap(err, "create release merge request failed")
 	}
 
-	f.printAndOpenBrowser("Hotfix Merge Request", result.WebURL)
+	f.printAndOpenBrowser("Release Merge Request", result.WebURL)
 
-	log.
-		WithFields(log.Fields{
-			"issue":        issue,
-			"mergeRequest": result,
-		}).
-		Debug("hotfix finish done")
+	return nil
+}
+
+// genReleaseDescription generate the description of release merge request, which contains
+// all features attached to the release.
+func (f flowImpl) genReleaseDescription(release *repository.ReleaseDO) string {
+	attached, err := f.repo.QueryReleaseMilestones(&repository.ReleaseMilestoneDO{
+		ProjectID:     release.ProjectID,
+		ReleaseBranch: release.BranchName,
+	})
+	if err != nil {
+		log.
+			WithFields(log.Fields{"releaseBranch": release.BranchName}).
+			Warnf("could not query attached features: %v", err)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(release.Desc)
+	if len(attached) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\n\nFeatures:\n")
+	for _, v := range attached {
+		title := v.FeatureBranch
+		milestone, err := f.repo.QueryMilestone(&repository.MilestoneDO{
+			ProjectID:   v.ProjectID,
+			MilestoneID: v.MilestoneID,
+		})
+		if err == nil {
+			title = fmt.Sprintf("%s (%s)", milestone.Title, v.FeatureBranch)
+		}
+		sb.WriteString("- " + title + "\n")
+	}
+
+	return sb.String()
+}
+
+func (f flowImpl) ReleaseClose(opc *types.OpReleaseContext, tagName string) error {
+	release, err := f.locateRelease(opc)
+	if err != nil {
+		return err
+	}
+	if release.ClosedAt != nil {
+		return errors.Wrapf(errReleaseClosed, "release(%s) tagged as %s", release.BranchName, release.TagName)
+	}
+
+	ctx := context.Background()
+	mrs, err := f.repo.QueryMergeRequests(&repository.MergeRequestDO{
+		ProjectID:    f.ctx.Project().ID,
+		SourceBranch: release.BranchName,
+		TargetBranch: types.MasterBranch.String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "query merge requests failed")
+	}
+
+	var merged *repository.MergeRequestDO
+	for _, mr := range mrs {
+		if err2 := f.refreshMergeRequestState(ctx, mr); err2 != nil {
+			log.
+				WithFields(log.Fields{"mergeRequestIID": mr.MergeRequestIID, "URL": mr.WebURL}).
+				Warnf("could not refresh merge request state: %v", err2)
+			continue
+		}
+
+		if mr.IsMerged() {
+			merged = mr
+			break
+		}
+	}
+	if merged == nil {
+		return errors.Wrapf(errReleaseNotMerged, "release(%s)", release.BranchName)
+	}
+
+	// tag the merge commit, so that anything merged into master after the release is not included.
+	ref := merged.MergeCommitSHA
+	if ref == "" {
+		ref = types.MasterBranch.String()
+		log.
+			WithFields(log.Fields{"mergeRequestIID": merged.MergeRequestIID, "URL": merged.WebURL}).
+			Warnf("merge commit of release is unknown, tag the HEAD of %s instead", ref)
+	}
+
+	if tagName == "" {
+		tagName = release.Version
+	}
+	tag, err := f.gitlabOperator.CreateTag(ctx, &gitlabop.CreateTagRequest{
+		TagName:   tagName,
+		Ref:       ref,
+		Message:   release.Desc,
+		ProjectID: f.ctx.Project().ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create tag failed")
+	}
+
+	if err = f.repo.CloseRelease(f.ctx.Project().ID, release.BranchName, tag.Name); err != nil {
+		return errors.Wrap(err, "close local release failed")
+	}
+
+	f.printAndOpenBrowser("Release Tag", genProjectURL(f.ctx.Project().WebURL, "/-/tags/"+tag.Name))
 
 	return nil
 }
@@ -646,49 +1924,65 @@ hotfixCreateMR:
 // 1. pull milestone + MergeRequest + Issues by `milestoneID`.
 // 2. parse `IssueID` from MR description.
 // 3. handle and save data.
-func (f flowImpl) SyncMilestone(milestoneID int, interact bool) error {
+func (f flowImpl) SyncMilestone(milestoneID int, groupMilestone, interact bool) error {
 	ctx := context.Background()
 	projectId := f.ctx.Project().ID
+	groupID := 0
 
 	// parameter checking
 	if milestoneID == 0 && !interact {
 		return errors.New("milestoneID could not be zero")
 	}
 
+	if groupMilestone && milestoneID != 0 {
+		project, err := f.gitlabOperator.GetProject(ctx, &gitlabop.GetProjectRequest{ProjectID: projectId})
+		if err != nil {
+			return errors.Wrap(err, "get project failed")
+		}
+		if project.GroupID == 0 {
+			return errors.Errorf("project(%s) does not belong to a group", project.PathWithNamespace)
+		}
+		groupID = project.GroupID
+	}
+
 	// interact mode
 	if interact && milestoneID == 0 {
 		// if interact to choose a milestone, and milestoneID is empty.
-		result, err := f.gitlabOperator.ListMilestones(ctx, &gitlabop.ListMilestoneRequest{
-			Page:      1,
-			PerPage:   20,
-			ProjectID: projectId,
+		// group milestones are listed too, since the project could use them.
+		it := f.gitlabOperator.ListMilestones(ctx, &gitlabop.ListMilestoneRequest{
+			ProjectID:               projectId,
+			IncludeParentMilestones: true,
 		})
-		if err != nil {
-			return errors.Wrap(err, "list milestones failed")
-		}
 
-		milestones := make([]*repository.MilestoneDO, len(result.Data))
-		for idx, v := range result.Data {
-			milestones[idx] = &repository.MilestoneDO{
+		milestones := make([]*repository.MilestoneDO, 0, 20)
+		for it.Next() {
+			v := it.Value()
+			milestones = append(milestones, &repository.MilestoneDO{
 				ProjectID:   projectId,
 				MilestoneID: v.ID,
 				Title:       v.Name,
 				Desc:        v.Description,
 				WebURL:      v.WebURL,
-			}
+				GroupID:     v.GroupID,
+			})
+		}
+		if err := it.Err(); err != nil {
+			return errors.Wrap(err, "list milestones failed")
 		}
 
-		milestone, err := chooseOneMilestoneInteractively(milestones)
+		milestone, err := chooseOneMilestoneInteractively(milestones, f.ctx.NonInteractive())
 		if err != nil {
 			return errors.Wrap(err, "chooseOneMilestoneInteractively failed")
 		}
 		milestoneID = milestone.MilestoneID
+		groupID = milestone.GroupID
 	}
 
 	log.Info("Querying remote repository data")
 	milestoneResult, err := f.gitlabOperator.GetMilestone(ctx, &gitlabop.GetMilestoneRequest{
 		ProjectID:   projectId,
 		MilestoneID: milestoneID,
+		GroupID:     groupID,
 	})
 	if err != nil {
 		return errors.Wrap(err, "get milestone failed")
@@ -698,6 +1992,7 @@ func (f flowImpl) SyncMilestone(milestoneID int, interact bool) error {
 		GetMilestoneMergeRequests(ctx, &gitlabop.GetMilestoneMergeRequestsRequest{
 			ProjectID:   projectId,
 			MilestoneID: milestoneID,
+			GroupID:     groupID,
 		})
 	if err != nil {
 		return errors.Wrap(err, "get milestone merge requests failed")
@@ -707,6 +2002,7 @@ func (f flowImpl) SyncMilestone(milestoneID int, interact bool) error {
 		&gitlabop.GetMilestoneIssuesRequest{
 			ProjectID:   projectId,
 			MilestoneID: milestoneID,
+			GroupID:     groupID,
 		})
 	if err != nil {
 		return errors.Wrap(err, "get milestone issues failed")
@@ -725,16 +2021,31 @@ func (f flowImpl) SyncMilestone(milestoneID int, interact bool) error {
 	// save data models
 	log.Info("Saving remote repository data into local database...")
 	tx := f.repo.StartTransaction()
-	err = f.repo.SaveMilestone(&repository.MilestoneDO{
+	milestone := &repository.MilestoneDO{
 		ProjectID:   projectId,
 		MilestoneID: milestoneResult.ID,
 		Title:       milestoneResult.Title,
 		Desc:        milestoneResult.Description,
 		WebURL:      milestoneResult.WebURL,
-	}, tx)
+		GroupID:     milestoneResult.GroupID,
+	}
+	if f.ctx.Config().Semver {
+		if v, err2 := pkg.ParseSemver(milestone.Title); err2 == nil {
+			milestone.Version = v.String()
+		}
+	}
+	err = f.repo.SaveMilestone(milestone, tx)
 	if err != nil {
 		return errors.Wrap(err, "save milestone failed")
 	}
+	// group milestone would be saved into other projects which have issues or merge requests in it.
+	for _, pid := range relatedProjectIDs(projectId, i, mr) {
+		copied := *milestone
+		copied.ProjectID = pid
+		if err = f.repo.SaveMilestone(&copied, tx); err != nil {
+			return errors.Wrapf(err, "save milestone of project(%d) failed", pid)
+		}
+	}
 	err = f.repo.BatchCreateIssue(i, tx)
 	if err != nil {
 		return errors.Wrap(err, "save issues failed")
@@ -779,6 +2090,12 @@ func (f flowImpl) SyncProject(isDelete bool) error {
 	return nil
 }
 
+// syncItemKey identifies an issue (by IID) or a branch (by name) in a project.
+type syncItemKey struct {
+	projectID int
+	key       interface{}
+}
+
 // syncFormatResultIntoDO rebuild local data from remote gitlab repository.
 // @return issues, mrs, branches, featureBranchName
 func (f flowImpl) syncFormatResultIntoDO(
@@ -790,28 +2107,39 @@ func (f flowImpl) syncFormatResultIntoDO(
 		issueDO    = make([]*repository.IssueDO, 0, 10)
 		mrDO       = make([]*repository.MergeRequestDO, 0, 10)
 		branchDO   = make([]*repository.BranchDO, 0, 10)
-		branchUniq = make(map[string]struct{})
+		branchUniq = make(map[syncItemKey]struct{})
 
-		c                 = make(map[int]*repository.IssueDO)
+		c                 = make(map[syncItemKey]*repository.IssueDO)
 		featureBranchName string
 		projectID         = f.ctx.Project().ID
 		milestoneID       = milestone.ID
 	)
 
+	// group milestone contains issues and merge requests of all projects in the group,
+	// so they are saved into their own project.
+	projectOf := func(id int) int {
+		if id != 0 {
+			return id
+		}
+		return projectID
+	}
+
 	// pre-handle issue into cache
 	for _, v := range issues {
-		c[v.IID] = &repository.IssueDO{
+		c[syncItemKey{projectOf(v.ProjectID), v.IID}] = &repository.IssueDO{
 			IssueIID:    v.IID,
 			Title:       v.Title,
 			Desc:        v.Description,
-			ProjectID:   projectID,
+			ProjectID:   projectOf(v.ProjectID),
 			MilestoneID: milestoneID,
 			WebURL:      v.WebURL,
+			Labels:      strings.Join(v.Labels, ","),
 			// RelatedBranch: ,
 		}
 	}
 
 	for _, mr := range mrs {
+		mrProjectID := projectOf(mr.ProjectID)
 		issueIID := parseIssueIIDFromMergeRequestIssue(mr.Description)
 		log.
 			WithFields(log.Fields{
@@ -823,7 +2151,7 @@ func (f flowImpl) syncFormatResultIntoDO(
 
 		if issueIID != 0 {
 			// 如果 MR 关联了Issue, 才会处理该issue到本地数据中
-			issue, ok := c[issueIID]
+			issue, ok := c[syncItemKey{mrProjectID, issueIID}]
 			if !ok {
 				// 记录日志
 				log.WithFields(log.Fields{
@@ -831,23 +2159,23 @@ func (f flowImpl) syncFormatResultIntoDO(
 					"issues":      issues,
 					"issuesCache": c,
 				}).Warn("从里程碑issue清单中locate issue failed")
-
+			} else {
+				// 生成数据
+				issueDO = append(issueDO, &repository.IssueDO{
+					IssueIID:      issueIID,
+					Title:         issue.Title,
+					Desc:          issue.Desc,
+					ProjectID:     mrProjectID,
+					MilestoneID:   milestoneID,
+					RelatedBranch: mr.SourceBranch,
+					WebURL:        issue.WebURL,
+					Labels:        issue.Labels,
+				})
 			}
-
-			// 生成数据
-			issueDO = append(issueDO, &repository.IssueDO{
-				IssueIID:      issueIID,
-				Title:         issue.Title,
-				Desc:          issue.Desc,
-				ProjectID:     projectID,
-				MilestoneID:   milestoneID,
-				RelatedBranch: mr.SourceBranch,
-				WebURL:        issue.WebURL,
-			})
 		}
 
 		mrDO = append(mrDO, &repository.MergeRequestDO{
-			ProjectID:       projectID,
+			ProjectID:       mrProjectID,
 			MilestoneID:     milestoneID,
 			IssueIID:        issueIID,
 			MergeRequestID:  mr.ID,
@@ -855,6 +2183,12 @@ func (f flowImpl) syncFormatResultIntoDO(
 			SourceBranch:    mr.SourceBranch,
 			TargetBranch:    mr.TargetBranch,
 			WebURL:          mr.WebURL,
+			State:           string(mr.State),
+			MergedAt:        mr.MergedAt,
+			Author:          mr.Author,
+			PipelineStatus:  mr.PipelineStatus,
+			MergeCommitSHA:  mr.MergeCommitSHA,
+			Draft:           mr.Draft,
 		})
 
 		// featureBranchName
@@ -865,24 +2199,26 @@ func (f flowImpl) syncFormatResultIntoDO(
 			featureBranchName = mr.TargetBranch
 		}
 
-		if _, ok := branchUniq[mr.SourceBranch]; !ok {
+		srcKey := syncItemKey{mrProjectID, mr.SourceBranch}
+		if _, ok := branchUniq[srcKey]; !ok {
 			branchDO = append(branchDO, &repository.BranchDO{
-				ProjectID:   projectID,
+				ProjectID:   mrProjectID,
 				MilestoneID: milestoneID,
 				IssueIID:    issueIID,
 				BranchName:  mr.SourceBranch,
 			})
-			branchUniq[mr.SourceBranch] = struct{}{}
+			branchUniq[srcKey] = struct{}{}
 		}
 		// targetBranch should synchronize too.
-		if _, ok := branchUniq[mr.TargetBranch]; !ok && notBuiltinBranch(mr.TargetBranch) {
+		targetKey := syncItemKey{mrProjectID, mr.TargetBranch}
+		if _, ok := branchUniq[targetKey]; !ok && notBuiltinBranch(mr.TargetBranch) {
 			branchDO = append(branchDO, &repository.BranchDO{
-				ProjectID:   projectID,
+				ProjectID:   mrProjectID,
 				MilestoneID: milestoneID,
 				IssueIID:    issueIID,
 				BranchName:  mr.TargetBranch,
 			})
-			branchUniq[mr.TargetBranch] = struct{}{}
+			branchUniq[targetKey] = struct{}{}
 		}
 	}
 
@@ -892,6 +2228,13 @@ func (f flowImpl) syncFormatResultIntoDO(
 // createBranch .
 func (f flowImpl) createBranch(
 	targetBranchName, srcBranch string, milestoneID, issueIID int) (*gitlabop.CreateBranchResult, error) {
+	// refuse before the branch is created remotely, so that nothing needs to be rolled back.
+	restore, err := f.guardWorkingTree("checkout "+targetBranchName, "")
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
 	wg := sync.WaitGroup{}
 	ctx := context.Background()
 	req := gitlabop.CreateBranchRequest{
@@ -925,6 +2268,18 @@ func (f flowImpl) createBranch(
 					"error":        err,
 				}).
 				Errorf("Checkout branch failed")
+			return
+		}
+
+		// the branch is created remotely, track it explicitly rather than relying on
+		// the remote guessed by checkout.
+		if err := f.gitOperator.SetUpstream(targetBranchName); err != nil {
+			log.
+				WithFields(log.Fields{
+					"targetBranch": targetBranchName,
+					"error":        err,
+				}).
+				Warnf("SetUpstream failed")
 		}
 	}()
 
@@ -955,7 +2310,7 @@ func (f flowImpl) createBranch(
 }
 
 // createMilestone create Milestone
-func (f flowImpl) createMilestone(title, desc string) (*gitlabop.CreateMilestoneResult, error) {
+func (f flowImpl) createMilestone(title, desc, version string, groupID int) (*gitlabop.CreateMilestoneResult, error) {
 	title = strings.TrimSpace(title)
 	desc = strings.TrimSpace(desc)
 
@@ -964,6 +2319,7 @@ func (f flowImpl) createMilestone(title, desc string) (*gitlabop.CreateMilestone
 		Title:     title,
 		Desc:      desc,
 		ProjectID: f.ctx.Project().ID,
+		GroupID:   groupID,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "CreateMilestone failed")
@@ -975,6 +2331,8 @@ func (f flowImpl) createMilestone(title, desc string) (*gitlabop.CreateMilestone
 		Title:       title,
 		Desc:        desc,
 		WebURL:      result.WebURL,
+		Version:     version,
+		GroupID:     groupID,
 	}); err != nil {
 		log.WithFields(log.Fields{
 			"milestone": result,
@@ -985,18 +2343,51 @@ func (f flowImpl) createMilestone(title, desc string) (*gitlabop.CreateMilestone
 	return result, nil
 }
 
+// suggestNextVersion suggests the next semantic version by bumping the latest version among
+// remote tags and milestones (both active and closed) of the project.
+func (f flowImpl) suggestNextVersion(part pkg.BumpPart) (string, error) {
+	ctx := context.Background()
+	projectID := f.ctx.Project().ID
+	versions := make([]string, 0, 32)
+
+	tagIt := f.gitlabOperator.ListTags(ctx, &gitlabop.ListTagRequest{ProjectID: projectID})
+	for tagIt.Next() {
+		versions = append(versions, tagIt.Value().Name)
+	}
+	if err := tagIt.Err(); err != nil {
+		return "", errors.Wrap(err, "list tags failed")
+	}
+
+	milestoneIt := f.gitlabOperator.ListMilestones(ctx, &gitlabop.ListMilestoneRequest{
+		ProjectID: projectID,
+		State:     gitlabop.MilestoneStateAll,
+	})
+	for milestoneIt.Next() {
+		versions = append(versions, milestoneIt.Value().Name)
+	}
+	if err := milestoneIt.Err(); err != nil {
+		return "", errors.Wrap(err, "list milestones failed")
+	}
+
+	return nextSemver(versions, part), nil
+}
+
 // createIssue .
 func (f flowImpl) createIssue(title, desc, relatedBranch string, milestoneID int) (*gitlabop.CreateIssueResult, error) {
+	title, desc = f.issueTemplates(f.newTemplateData(title, desc, milestoneID, 0, relatedBranch, ""))
 	title = strings.TrimSpace(title)
 	desc = strings.TrimSpace(desc)
 
 	ctx := context.Background()
+	people := f.resolveParticipants(ctx, flowStepOf(relatedBranch, ""))
 	result, err := f.gitlabOperator.CreateIssue(ctx, &gitlabop.CreateIssueRequest{
 		Title:         title,
 		Desc:          desc,
 		RelatedBranch: relatedBranch,
 		MilestoneID:   milestoneID,
 		ProjectID:     f.ctx.Project().ID,
+		AssigneeIDs:   people.assigneeIDs,
+		Labels:        people.labels,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "create Issue failed")
@@ -1010,6 +2401,8 @@ func (f flowImpl) createIssue(title, desc, relatedBranch string, milestoneID int
 		MilestoneID:   milestoneID,
 		RelatedBranch: relatedBranch,
 		WebURL:        result.WebURL,
+		Labels:        strings.Join(people.labels, ","),
+		Assignees:     strings.Join(people.assignees, ","),
 	}); err != nil {
 		log.
 			WithFields(log.Fields{
@@ -1022,20 +2415,25 @@ func (f flowImpl) createIssue(title, desc, relatedBranch string, milestoneID int
 	return result, nil
 }
 
-// CreateMergeRequest create MergeRequest
+// CreateMergeRequest create MergeRequest, draft merge request would not be merged automatically
+// even if autoMerge is true. Merge requests are drafts as default (they were prefixed with "WIP: "),
+// draft is false only if the merge request is ready for review (close-issue) or merged automatically.
 func (f flowImpl) createMergeRequest(
 	title, desc string,
 	milestoneID, issueIID int,
 	srcBranch, targetBranch string,
-	autoMerge bool,
+	autoMerge, draft bool,
 ) (*gitlabop.CreateMergeResult, error) {
 	ctx := context.Background()
-	title = "WIP: " + title
+	title, desc = f.mergeRequestTemplates(ctx,
+		f.newTemplateData(title, desc, milestoneID, issueIID, srcBranch, targetBranch))
+	autoMerge = autoMerge && !draft
 	// Closes related issue
 	if issueIID != 0 {
 		desc = fmt.Sprintf("Closes #%d\n", issueIID) + desc
 	}
 
+	people := f.resolveParticipants(ctx, flowStepOf(srcBranch, targetBranch))
 	result, err := f.gitlabOperator.CreateMergeRequest(ctx, &gitlabop.CreateMergeRequest{
 		Title:        title,
 		Desc:         desc,
@@ -1045,6 +2443,10 @@ func (f flowImpl) createMergeRequest(
 		IssueIID:     issueIID,
 		ProjectID:    f.ctx.Project().ID,
 		AutoMerge:    autoMerge,
+		Draft:        draft,
+		AssigneeIDs:  people.assigneeIDs,
+		ReviewerIDs:  people.reviewerIDs,
+		Labels:       people.labels,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "create MR failed")
@@ -1057,13 +2459,16 @@ func (f flowImpl) createMergeRequest(
 			"target": targetBranch,
 			"url":    result.WebURL,
 			"title":  title,
+			"draft":  result.Draft,
 		}).
 		Debug("create mr success")
 
+	var autoMergeError string
 	if autoMerge {
-		if err = f.autoMergeMR(ctx, result.IID); err != nil {
+		if err = f.autoMergeMR(ctx, result.IID, srcBranch, targetBranch); err != nil {
 			log.WithFields(log.Fields{"mergeRequestID": result.ID, "URL": result.WebURL, "mergeRequestIID": result.IID}).
 				Warnf("auto merge failed: %v", err)
+			autoMergeError = err.Error()
 		}
 	}
 
@@ -1076,6 +2481,12 @@ func (f flowImpl) createMergeRequest(
 		SourceBranch:    srcBranch,
 		TargetBranch:    targetBranch,
 		WebURL:          result.WebURL,
+		State:           string(result.State),
+		Author:          result.Author,
+		AutoMergeError:  autoMergeError,
+		Draft:           result.Draft,
+		Assignees:       strings.Join(people.assignees, ","),
+		Reviewers:       strings.Join(people.reviewers, ","),
 	}); err != nil {
 		log.
 			WithFields(log.Fields{
@@ -1087,10 +2498,21 @@ func (f flowImpl) createMergeRequest(
 	return result, nil
 }
 
-func (f flowImpl) autoMergeMR(ctx context.Context, mergeRequestIID int) error {
+// autoMergeMR sets the merge request to be merged when its pipeline succeeds, so that gitlab would
+// merge it later rather than retrying here. Only retries while gitlab is still checking whether the
+// merge request could be merged (406 or 405) right after it's created.
+func (f flowImpl) autoMergeMR(ctx context.Context, mergeRequestIID int, srcBranch, targetBranch string) error {
 	req := &gitlabop.MergeMergeRequest{
-		ProjectID:      f.ctx.Project().ID,
-		MergeRequestID: mergeRequestIID,
+		ProjectID:                 f.ctx.Project().ID,
+		MergeRequestID:            mergeRequestIID,
+		MergeWhenPipelineSucceeds: true,
+	}
+	if setting := f.ctx.Config().Merge; setting != nil {
+		req.Squash = setting.Squash
+		// feature branch is still used by merge requests into other branches, so it could
+		// only be removed after merged into master.
+		req.ShouldRemoveSourceBranch = setting.RemoveSourceBranch &&
+			(strings.HasPrefix(srcBranch, types.IssueBranchPrefix) || targetBranch == types.MasterBranch.String())
 	}
 
 	// construct a backoff strategy with max retries time (5), and max interval(13s)
@@ -1102,31 +2524,226 @@ func (f flowImpl) autoMergeMR(ctx context.Context, mergeRequestIID int) error {
 	retryBackoff.InitialInterval = 1 * time.Second
 
 	// retryBackoff to merge
-	retries := 0
-	err := backoff.Retry(func() error {
-		err := f.gitlabOperator.MergeMergeRequest(ctx, req)
+	var (
+		result  *gitlabop.MergeMergeRequestResult
+		retries = 0
+	)
+	err := backoff.Retry(func() (err error) {
+		result, err = f.gitlabOperator.MergeMergeRequest(ctx, req)
 		if err == nil {
 			return nil
 		}
 
 		retries++
 		// analyze error and decide if we should retry
-		// only 406 is allowed to retry.
+		// only 406 and 405 are allowed to retry.
 		var errResp = new(gogitlab.ErrorResponse)
 		if !errors.As(err, &errResp) {
 			// not a gogitlab.ErrorResponse type, so we should not retry.
 			return backoff.Permanent(err)
 		}
 
-		if errResp.Response.StatusCode == 406 {
-			// 406 is not allowed to merge, since it's not ready. so we should retry.
+		switch errResp.Response.StatusCode {
+		case http.StatusNotAcceptable, http.StatusMethodNotAllowed:
+			// merge request is not ready to merge, since gitlab is still checking it. so we should retry.
 			log.Infof("auto merge failed(%s), retrying... %d", errResp.Message, retries)
 			return err
 		}
 		return backoff.Permanent(err)
 	}, retryBackoff)
+	if err != nil {
+		return err
+	}
+
+	if result.MergeWhenPipelineSucceeds {
+		log.Infof("merge request(!%d) would be merged after its pipeline succeeded", mergeRequestIID)
+	}
+
+	return nil
+}
+
+// refreshMergeRequestState query the latest state of merge request from remote,
+// then update mr and persist it into local database.
+func (f flowImpl) refreshMergeRequestState(ctx context.Context, mr *repository.MergeRequestDO) error {
+	return refreshMergeRequest(ctx, f.gitlabOperator, f.repo, mr)
+}
+
+// refreshMergeRequest refreshes the state and pipeline status of mr from gitlab, and saves them
+// into local repository.
+func refreshMergeRequest(
+	ctx context.Context,
+	gitlabOperator gitlabop.IGitlabOperator,
+	repo repository.IFlowRepository,
+	mr *repository.MergeRequestDO,
+) error {
+	result, err := gitlabOperator.GetMergeRequest(ctx, &gitlabop.GetMergeRequestRequest{
+		MergeRequestIID: mr.MergeRequestIID,
+		ProjectID:       mr.ProjectID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "get merge request failed")
+	}
+
+	mr.State = string(result.State)
+	mr.MergedAt = result.MergedAt
+	mr.Author = result.Author
+	mr.PipelineStatus = result.PipelineStatus
+	mr.MergeCommitSHA = result.MergeCommitSHA
+	mr.Draft = result.Draft
+	if err = repo.UpdateMergeRequestState(mr); err != nil {
+		log.
+			WithFields(log.Fields{"mergeRequestIID": mr.MergeRequestIID}).
+			Warnf("could not save merge request state: %v", err)
+	}
+
+	return nil
+}
+
+const (
+	_waitMergeRequestInterval = 10 * time.Second
+	_waitMergeRequestTimeout  = 30 * time.Minute
+)
+
+// waitMergeRequestMerged polls the state of merge request until it has been merged, error would be
+// returned if it has been closed or waiting timeout.
+func (f flowImpl) waitMergeRequestMerged(ctx context.Context, mr *repository.MergeRequestDO) error {
+	ctx, cancel := context.WithTimeout(ctx, _waitMergeRequestTimeout)
+	defer cancel()
 
-	return err
+	waiting := false
+	policy := backoff.WithContext(backoff.NewConstantBackOff(_waitMergeRequestInterval), ctx)
+	err := backoff.Retry(func() error {
+		if err := f.refreshMergeRequestState(ctx, mr); err != nil {
+			return backoff.Permanent(err)
+		}
+
+		switch mr.State {
+		case repository.MergeRequestStateMerged:
+			return nil
+		case repository.MergeRequestStateClosed, repository.MergeRequestStateLocked:
+			return backoff.Permanent(fmt.Errorf("merge request(%s) has been %s", mr.WebURL, mr.State))
+		}
+
+		if !waiting {
+			waiting = true
+			log.Infof("waiting for merge request to be merged: %s", mr.WebURL)
+		}
+		return fmt.Errorf("merge request(%s) is %s", mr.WebURL, mr.State)
+	}, policy)
+	if err != nil {
+		return errors.Wrap(err, "wait merge request merged failed")
+	}
+
+	return nil
+}
+
+const (
+	_waitPipelineInterval = 10 * time.Second
+	_waitPipelineTimeout  = time.Hour
+)
+
+// waitPipelineFinished polls the latest pipeline until it finishes. The pipeline may not be created yet
+// right after pushing, so ErrPipelineNotFound would be retried too.
+func (f flowImpl) waitPipelineFinished(
+	ctx context.Context, req *gitlabop.GetLatestPipelineRequest) (*gitlabop.PipelineShort, error) {
+	ctx, cancel := context.WithTimeout(ctx, _waitPipelineTimeout)
+	defer cancel()
+
+	var (
+		pipeline *gitlabop.PipelineShort
+		status   string
+	)
+	policy := backoff.WithContext(backoff.NewConstantBackOff(_waitPipelineInterval), ctx)
+	err := backoff.Retry(func() error {
+		p, err := f.gitlabOperator.GetLatestPipeline(ctx, req)
+		if err != nil {
+			if errors.Is(err, gitlabop.ErrPipelineNotFound) {
+				return err
+			}
+			return backoff.Permanent(err)
+		}
+
+		pipeline = p
+		if p.Finished() {
+			return nil
+		}
+
+		if p.Status != status {
+			status = p.Status
+			log.Infof("waiting for pipeline to finish: %s (%s)", p.WebURL, p.Status)
+		}
+		return fmt.Errorf("pipeline(%s) is %s", p.WebURL, p.Status)
+	}, policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "wait pipeline finished failed")
+	}
+
+	return pipeline, nil
+}
+
+// publishRelease create tag on ref and publish a gitlab release of the tag with notes. If ref is empty,
+// types.MasterBranch would be used.
+func (f flowImpl) publishRelease(ctx context.Context, tagName, ref, notes string, milestones []string) error {
+	if ref == "" {
+		ref = types.MasterBranch.String()
+		log.
+			WithFields(log.Fields{"tag": tagName}).
+			Warnf("merge commit is unknown, tag the HEAD of %s instead", ref)
+	}
+
+	tag, err := f.gitlabOperator.CreateTag(ctx, &gitlabop.CreateTagRequest{
+		TagName:   tagName,
+		Ref:       ref,
+		ProjectID: f.ctx.Project().ID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create tag failed")
+	}
+
+	if _, err = f.gitlabOperator.CreateRelease(ctx, &gitlabop.CreateReleaseRequest{
+		TagName:     tag.Name,
+		Name:        tag.Name,
+		Description: notes,
+		Milestones:  milestones,
+		ProjectID:   f.ctx.Project().ID,
+	}); err != nil {
+		return errors.Wrap(err, "create release failed")
+	}
+
+	log.
+		WithFields(log.Fields{"tag": tag.Name, "commit": tag.CommitSHA}).
+		Debug("release published")
+
+	f.printAndOpenBrowser("Release", genProjectURL(f.ctx.Project().WebURL, "/-/releases/"+tag.Name))
+	return nil
+}
+
+// shouldRecreateMergeRequest refresh the state of merge request which is located from local, and
+// tells user the merge request has been merged or closed, then let user decide whether to create
+// a new merge request. It returns false if the merge request is still opened.
+func (f flowImpl) shouldRecreateMergeRequest(mr *repository.MergeRequestDO) bool {
+	if err := f.refreshMergeRequestState(context.Background(), mr); err != nil {
+		log.
+			WithFields(log.Fields{"mergeRequestIID": mr.MergeRequestIID, "URL": mr.WebURL}).
+			Warnf("could not refresh merge request state: %v", err)
+		return false
+	}
+
+	switch mr.State {
+	case repository.MergeRequestStateMerged, repository.MergeRequestStateClosed:
+	default:
+		return false
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, _mergeRequestStateTpl, mr.SourceBranch, mr.TargetBranch, mr.State, mr.WebURL)
+	if !confirmInteractively("Would you like to create a new merge request?", false, f.ctx.NonInteractive()) {
+		if f.ctx.NonInteractive() {
+			log.Info("run with --force-create-mr to create a new merge request")
+		}
+		return false
+	}
+
+	return true
 }
 
 // featureProcessMR is a process for creating a merge request for feature branch to target branch. If
@@ -1158,6 +2775,10 @@ func (f flowImpl) featureProcessMR(
 		return errors.Wrap(err, "query merge request failed")
 	}
 	if mr != nil {
+		if f.shouldRecreateMergeRequest(mr) {
+			goto featureCreateMR
+		}
+
 		f.printAndOpenBrowser("Feature Merge Request", mr.WebURL)
 		return nil
 	}
@@ -1173,7 +2794,7 @@ featureCreateMR:
 	targetBranch := targetBranchName.String()
 	title := genMergeRequestName(featureBranchName, targetBranch)
 	result, err := f.createMergeRequest(
-		title, milestone.Desc, milestone.MilestoneID, 0, featureBranch.BranchName, targetBranch, autoMerge)
+		title, milestone.Desc, milestone.MilestoneID, 0, featureBranch.BranchName, targetBranch, autoMerge, !autoMerge)
 	if err != nil {
 		return errors.Wrapf(err, "featureProcessMR failed to create merge request")
 	}
@@ -1188,6 +2809,11 @@ const _printTpl = `
 	🤡 URL	: %s
 `
 
+const _mergeRequestStateTpl = `
+	🔔 Merge Request (%s => %s) has been %s
+	🤡 URL	: %s
+`
+
 // printAndOpenBrowser print WebURL into stdout and open web browser.
 func (f flowImpl) printAndOpenBrowser(title, url string) {
 	if len(title) == 0 && len(url) == 0 {
@@ -1,11 +1,20 @@
 package internal
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
+	gitlabop "github.com/yeqown/gitlab-flow/internal/gitlab-operator"
+	"github.com/yeqown/gitlab-flow/internal/repository"
+	"github.com/yeqown/gitlab-flow/internal/repository/impl"
 	"github.com/yeqown/gitlab-flow/internal/types"
+	"github.com/yeqown/gitlab-flow/pkg"
 )
 
 type testFlowSuite struct {
@@ -35,6 +44,229 @@ func (s testFlowSuite) Test_parseFeatureFromIssueName_compatible() {
 
 }
 
+func (s *testFlowSuite) Test_genReleaseBranchName() {
+	name := genReleaseBranchName("1.2")
+	s.Equal(types.ReleaseBranchPrefix+"1.2", name)
+	s.Equal(name, genReleaseBranchName(name))
+	s.True(isReleaseName(name))
+	s.False(isReleaseName(genFeatureBranchName("1.2")))
+}
+
+func (s *testFlowSuite) Test_genConflictResolveBranchName() {
+	name := genConflictResolveBranchName(genHotfixBranchName("fix-login"), "develop")
+	s.Equal(types.ConflictResolveBranchPrefix+"fix-login-to-develop", name)
+
+	// custom prefix without slash or with more than one slash.
+	defer func(prefix string) { types.HotfixBranchPrefix = prefix }(types.HotfixBranchPrefix)
+	for _, prefix := range []string{"hf-", "team/hotfix/"} {
+		types.SetBranchPrefix("", prefix, "", "", "")
+		name = genConflictResolveBranchName(genHotfixBranchName("fix-login"), "develop")
+		s.Equal(types.ConflictResolveBranchPrefix+"fix-login-to-develop", name)
+	}
+}
+
+func (s *testFlowSuite) Test_genReleaseNotes() {
+	issues := []*repository.IssueDO{
+		{IssueIID: 1, Title: "login page", WebURL: "https://gitlab.example.com/p/-/issues/1"},
+	}
+	mrs := []*repository.MergeRequestDO{
+		{MergeRequestIID: 2, SourceBranch: "feature/login", TargetBranch: "master", State: "merged"},
+		{MergeRequestIID: 3, SourceBranch: "feature/login", TargetBranch: "develop", State: "closed"},
+	}
+
+	notes := genReleaseNotes("v1.0.0", "login", "support login", issues, mrs)
+	s.Contains(notes, "## v1.0.0")
+	s.Contains(notes, "support login")
+	s.Contains(notes, "[#1 login page](https://gitlab.example.com/p/-/issues/1)")
+	s.Contains(notes, "!2 feature/login => master")
+	s.NotContains(notes, "!3")
+}
+
+func (s *testFlowSuite) Test_nextSemver() {
+	versions := []string{"v1.2.0", "feature-login", "v1.10.0", "v1.3.1"}
+	s.Equal("v2.0.0", nextSemver(versions, pkg.BumpMajor))
+	s.Equal("v1.11.0", nextSemver(versions, pkg.BumpMinor))
+	s.Equal("v1.10.1", nextSemver(versions, pkg.BumpPatch))
+	s.Equal("v0.1.0", nextSemver(nil, pkg.BumpMinor))
+}
+
+func (s *testFlowSuite) Test_relatedProjectIDs() {
+	cases := []struct {
+		name   string
+		issues []*repository.IssueDO
+		mrs    []*repository.MergeRequestDO
+		want   []int
+	}{
+		{
+			name:   "ordered by first appearance",
+			issues: []*repository.IssueDO{{ProjectID: 1}, {ProjectID: 3}},
+			mrs:    []*repository.MergeRequestDO{{ProjectID: 2}, {ProjectID: 3}, {ProjectID: 1}},
+			want:   []int{3, 2},
+		},
+		{
+			name: "only current project",
+			mrs:  []*repository.MergeRequestDO{{ProjectID: 1}},
+			want: []int{},
+		},
+		{
+			name: "empty",
+			want: []int{},
+		},
+	}
+
+	for _, c := range cases {
+		s.Equal(c.want, relatedProjectIDs(1, c.issues, c.mrs), c.name)
+	}
+}
+
+func (s *testFlowSuite) Test_syncFormatResultIntoDO_group() {
+	ctx := types.NewContext("", "a", &types.Config{}, false, true)
+	ctx.InjectProject(&types.ProjectBasics{ID: 1, Name: "a"})
+	f := flowImpl{ctx: ctx}
+
+	// issues of different projects in the group milestone share the same IID.
+	issues := []gitlabop.IssueShort{
+		{IID: 1, ProjectID: 1, Title: "a"},
+		{IID: 1, ProjectID: 2, Title: "b"},
+	}
+	mrs := []gitlabop.MergeRequestShort{
+		{IID: 5, ProjectID: 2, SourceBranch: "issue/login-1", TargetBranch: "feature/login", Description: "Closes #1"},
+		{IID: 6, ProjectID: 0, SourceBranch: "issue/login-1", TargetBranch: "feature/login", Description: "Closes #1"},
+	}
+
+	issueDO, mrDO, branchDO, feature := f.syncFormatResultIntoDO(&gitlabop.GetMilestoneResult{ID: 9}, mrs, issues)
+	s.Equal("feature/login", feature)
+
+	s.Require().Len(issueDO, 2)
+	s.Equal(2, issueDO[0].ProjectID)
+	s.Equal("b", issueDO[0].Title)
+	s.Equal(1, issueDO[1].ProjectID)
+	s.Equal("a", issueDO[1].Title)
+
+	s.Require().Len(mrDO, 2)
+	s.Equal(2, mrDO[0].ProjectID)
+	s.Equal(1, mrDO[1].ProjectID, "merge request without project should belong to the current project")
+
+	// branches with the same name in different projects are kept separately.
+	s.Len(branchDO, 4)
+	for _, b := range branchDO {
+		s.Equal(9, b.MilestoneID)
+	}
+}
+
+func (s *testFlowSuite) Test_parseProjectSelector() {
+	s.Equal(projectSelector{Name: "flow"}, parseProjectSelector("flow"))
+	s.Equal(projectSelector{ID: 1024}, parseProjectSelector("1024"))
+	s.Equal(projectSelector{Name: "flow", FullPath: "group/sub/flow"}, parseProjectSelector("/group/sub/flow/"))
+
+	sel := parseProjectSelector("group/flow")
+	s.True(sel.match(&repository.ProjectDO{WebURL: "https://gitlab.example.com/group/flow"}))
+	s.False(sel.match(&repository.ProjectDO{WebURL: "https://gitlab.example.com/other-group/flow"}))
+}
+
+func (s *testFlowSuite) Test_flowStepOf() {
+	s.Equal(types.FlowStepIssue, flowStepOf(types.FeatureBranchPrefix+"login", ""))
+	s.Equal(types.FlowStepIssue, flowStepOf(genIssueBranchName("login", 1), types.FeatureBranchPrefix+"login"))
+	s.Equal(types.FlowStepDebug, flowStepOf(types.FeatureBranchPrefix+"login", types.DevBranch.String()))
+	s.Equal(types.FlowStepTest, flowStepOf(types.FeatureBranchPrefix+"login", types.TestBranch.String()))
+	s.Equal(types.FlowStepRelease, flowStepOf(types.ReleaseBranchPrefix+"v1.0.0", types.MasterBranch.String()))
+	s.Equal(types.FlowStepHotfix, flowStepOf(types.HotfixBranchPrefix+"crash", types.MasterBranch.String()))
+}
+
+func (s *testFlowSuite) Test_confirmInteractively_nonInteractive() {
+	s.False(confirmInteractively("Would you like to create a new merge request?", false, true))
+	s.True(confirmInteractively("Would you like to create a new merge request?", true, true))
+}
+
+func (s *testFlowSuite) Test_featureReleasedByRelease() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var iid int
+		_, _ = fmt.Sscanf(r.URL.Path, "/api/v4/projects/1/merge_requests/%d", &iid)
+		states := map[int]string{5: "closed", 6: "merged"}
+		_, _ = fmt.Fprintf(w, `{"id":%d,"iid":%d,"project_id":1,"state":"%s"}`, iid, iid, states[iid])
+	}))
+	defer server.Close()
+
+	repo := impl.NewBasedSqlite3(impl.ConnectDB(s.T().TempDir(), false))
+	f := flowImpl{
+		ctx:            types.NewContext("", "a", &types.Config{}, false, true),
+		gitlabOperator: gitlabop.NewGitlabOperator("token", server.URL+"/api/v4"),
+		repo:           repo,
+	}
+	closedAt := time.Now()
+	for _, release := range []string{"release/v1.0.0", "release/v1.1.0"} {
+		s.Require().NoError(repo.SaveRelease(&repository.ReleaseDO{ProjectID: 1, BranchName: release, ClosedAt: &closedAt}))
+	}
+	// milestone 1 is attached but never merged, milestone 2 is closed without merged.
+	s.Require().NoError(repo.SaveReleaseMilestone(&repository.ReleaseMilestoneDO{
+		ProjectID: 1, ReleaseBranch: "release/v1.0.0", MilestoneID: 1, FeatureBranch: "feature/a"}))
+	s.Require().NoError(repo.SaveReleaseMilestone(&repository.ReleaseMilestoneDO{
+		ProjectID: 1, ReleaseBranch: "release/v1.0.0", MilestoneID: 2, FeatureBranch: "feature/b"}))
+	s.Require().NoError(repo.SaveMergeRequest(&repository.MergeRequestDO{
+		ProjectID: 1, MilestoneID: 2, MergeRequestIID: 5, SourceBranch: "feature/b", TargetBranch: "release/v1.0.0"}))
+	s.False(f.featureReleasedByRelease(context.Background(), 1, 1))
+	s.False(f.featureReleasedByRelease(context.Background(), 1, 2))
+
+	// milestone 2 is merged into another release later.
+	s.Require().NoError(repo.SaveReleaseMilestone(&repository.ReleaseMilestoneDO{
+		ProjectID: 1, ReleaseBranch: "release/v1.1.0", MilestoneID: 2, FeatureBranch: "feature/b"}))
+	s.Require().NoError(repo.SaveMergeRequest(&repository.MergeRequestDO{
+		ProjectID: 1, MilestoneID: 2, MergeRequestIID: 6, SourceBranch: "feature/b", TargetBranch: "release/v1.1.0"}))
+	s.True(f.featureReleasedByRelease(context.Background(), 1, 2))
+}
+
+// testConfigHelper serves the global configuration only, the configuration is saved into dir.
+type testConfigHelper struct {
+	cfg *types.Config
+	dir string
+}
+
+func (h testConfigHelper) Context() *ConfigHelperContext              { return &ConfigHelperContext{} }
+func (h testConfigHelper) Config(types.ConfigType) types.ConfigHolder { return h.cfg }
+func (h testConfigHelper) SaveTo(types.ConfigType) string             { return h.dir }
+
+func (s *testFlowSuite) Test_renewOAuthAccessToken_nonInteractive() {
+	// refresh token has expired, authorization is required.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"expired"}`))
+	}))
+	defer server.Close()
+
+	appID, err := pkg.DesEncrypt([]byte("app"), []byte(gitlabop.SecretKey))
+	s.Require().NoError(err)
+	newConfig := func(accessToken, refreshToken string) *types.Config {
+		return &types.Config{
+			GitlabHost: server.URL,
+			OAuth2: &types.OAuth{
+				AppID:        appID,
+				AppSecret:    appID,
+				AccessToken:  accessToken,
+				RefreshToken: refreshToken,
+				CallbackHost: "127.0.0.1:0",
+			},
+		}
+	}
+	renew := func(cfg *types.Config) (string, error) {
+		ctx := types.NewContext("", "a", cfg, false, true)
+		return renewOAuthAccessToken(ctx, testConfigHelper{cfg: cfg, dir: s.T().TempDir()})
+	}
+
+	token, err := renew(newConfig("configured", ""))
+	s.NoError(err)
+	s.Equal("configured", token)
+
+	_, err = renew(newConfig("", ""))
+	s.ErrorContains(err, "could not authorize in non-interactive mode")
+
+	_, err = renew(newConfig("configured", "expired"))
+	s.ErrorContains(err, "could not refresh access token in non-interactive mode")
+
+	s.T().Setenv("GITLAB_FLOW_ACCESS_TOKEN", "env")
+	token, err = renew(newConfig("configured", "expired"))
+	s.NoError(err)
+	s.Equal("env", token)
+}
+
 func Test_flowSuite(t *testing.T) {
 	suite.Run(t, new(testFlowSuite))
 }
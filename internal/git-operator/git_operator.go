@@ -1,5 +1,10 @@
 package gitop
 
+import (
+	"github.com/pkg/errors"
+	"github.com/yeqown/log"
+)
+
 // IGitOperator supports to manage the local git repository.
 type IGitOperator interface {
 	// Checkout local branch
@@ -14,4 +19,134 @@ type IGitOperator interface {
 	// Merge would merge source into target branch. If current branch is not your target branch,
 	// this function would automatically check out, then execute the merge command.
 	Merge(source, target string) error
+
+	// DeleteLocalBranch delete the local branch, force means deleting the branch
+	// even if it has not been merged into its upstream branch.
+	DeleteLocalBranch(branchName string, force bool) error
+
+	// Push pushes local branch to the remote, setUpstream means the local branch
+	// would track the remote branch after pushed.
+	Push(branchName string, setUpstream bool) error
+
+	// Pull fetches the branch from the remote and fast-forwards current branch to it,
+	// it fails if current branch has diverged from the remote branch.
+	Pull(branchName string) error
+
+	// SetUpstream sets the remote branch with the same name as upstream of local branch.
+	SetUpstream(branchName string) error
+
+	// DeleteRemoteBranch deletes the branch of the remote.
+	DeleteRemoteBranch(branchName string) error
+
+	// ListBranches lists local branches, or branches of the remote which have been
+	// fetched if remote is true, names of remote branches have no remote prefix.
+	ListBranches(remote bool) ([]string, error)
+
+	// Status returns the status of working tree.
+	Status() (*Status, error)
+
+	// Stash saves changes of tracked files into stash with message, and reverts the working tree
+	// to HEAD, untracked files are kept in the working tree.
+	Stash(message string) error
+
+	// StashPop restores the latest stash into the working tree and drops it.
+	StashPop() error
+
+	// StashList returns messages of stashes, the latest one comes first.
+	StashList() ([]string, error)
+
+	// Rebase rebases current branch onto upstream, e.g. "origin/master". If there are conflicts,
+	// the rebase stops, and it could be continued by Continue or aborted by Abort.
+	Rebase(upstream string) error
+
+	// Continue continues the merge or rebase which is in progress after conflicts are resolved
+	// and staged.
+	Continue() error
+
+	// Abort aborts the merge or rebase which is in progress, and restores the branch.
+	Abort() error
+
+	// MergeTree dry runs merging source into target without touching the working tree and index,
+	// it returns files which would be conflicted, empty means source could be merged cleanly.
+	MergeTree(source, target string) ([]string, error)
+}
+
+// ErrNotSupported means the operation is not supported by the backend.
+var ErrNotSupported = errors.New("not supported by the git backend")
+
+const (
+	OperationMerge      = "merge"
+	OperationRebase     = "rebase"
+	OperationCherryPick = "cherry-pick"
+	OperationRevert     = "revert"
+)
+
+// Status is the status of working tree.
+type Status struct {
+	// Branch is the current branch, it's "HEAD" if HEAD is detached.
+	Branch string
+	// InProgress is the operation which is in progress, e.g. OperationMerge, empty means
+	// there is no operation in progress.
+	InProgress string
+	// Changed are tracked files which have staged or unstaged changes, conflicted files included.
+	Changed []string
+	// Conflicted are files which have unresolved conflicts.
+	Conflicted []string
+	// Untracked are files which are not tracked, they never block switching branches.
+	Untracked []string
+}
+
+// Detached returns true if HEAD is not pointing to any branch.
+func (s *Status) Detached() bool {
+	return s.Branch == "HEAD"
+}
+
+// Clean returns true if there is no changes of tracked files and no operation in progress.
+func (s *Status) Clean() bool {
+	return len(s.Changed) == 0 && s.InProgress == ""
+}
+
+// inProgressOperation returns the operation in progress by files in git directory,
+// exists reports whether the file exists in git directory.
+func inProgressOperation(exists func(name string) bool) string {
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return OperationRebase
+	case exists("MERGE_HEAD"):
+		return OperationMerge
+	case exists("CHERRY_PICK_HEAD"):
+		return OperationCherryPick
+	case exists("REVERT_HEAD"):
+		return OperationRevert
+	}
+
+	return ""
+}
+
+const (
+	// BackendCmd is the backend which executes local `git` executable file, it's the default one.
+	BackendCmd = "cmd"
+	// BackendGoGit is the backend based on go-git, which does not depend on `git` executable file.
+	BackendGoGit = "go-git"
+)
+
+// DefaultRemote is the remote name used while remote is not specified.
+const DefaultRemote = "origin"
+
+// New generate a git operator of backend, empty or unknown backend means BackendCmd,
+// and empty remote means DefaultRemote.
+func New(backend, dir, remote string) IGitOperator {
+	switch backend {
+	case BackendGoGit:
+		return NewBasedGoGit(dir, remote)
+	case "", BackendCmd:
+	default:
+		log.
+			WithFields(log.Fields{
+				"backend": backend,
+			}).
+			Warnf("unknown git backend, %s is used", BackendCmd)
+	}
+
+	return NewBasedCmd(dir, remote)
 }
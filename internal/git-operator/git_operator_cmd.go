@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -21,24 +22,61 @@ type operatorBasedCmd struct {
 	dir string
 	// verbose mode indicates print more information into os.Stdout
 	verbose bool
+	// remote is the name of remote which branches are pushed to and pulled from.
+	remote string
 
 	// commands
 	fetchCmd         string // fetch command
 	checkoutCmd      string // checkout command
 	currentBranchCmd string
 	mergeCmd         string
+	deleteBranchCmd  string
+	pushCmd          string
+	pullCmd          string
+	setUpstreamCmd   string
+	deleteRemoteCmd  string
+	listBranchesCmd  string
+	statusCmd        string
+	gitDirCmd        string
+	stashCmd         string
+	stashPopCmd      string
+	stashListCmd     string
+	rebaseCmd        string
+	continueCmd      string
+	abortCmd         string
+	mergeTreeCmd     string
 }
 
-// NewBasedCmd generate a git operator based command line.
-func NewBasedCmd(dir string) IGitOperator {
+// NewBasedCmd generate a git operator based command line, empty remote means DefaultRemote.
+func NewBasedCmd(dir, remote string) IGitOperator {
+	if remote == "" {
+		remote = DefaultRemote
+	}
+
 	return operatorBasedCmd{
 		cmd:              "git",
 		dir:              dir,
 		verbose:          true,
+		remote:           remote,
 		fetchCmd:         "fetch {arg}",
 		checkoutCmd:      "checkout {createFlag}{branch}",
 		currentBranchCmd: "rev-parse --abbrev-ref HEAD",
 		mergeCmd:         "merge --no-ff {branch}",
+		deleteBranchCmd:  "branch {deleteFlag} {branch}",
+		pushCmd:          "push {upstreamFlag} {remote} {branch}",
+		pullCmd:          "pull --ff-only {remote} {branch}",
+		setUpstreamCmd:   "branch --set-upstream-to={remote}/{branch} {branch}",
+		deleteRemoteCmd:  "push {remote} --delete {branch}",
+		listBranchesCmd:  "for-each-ref --format=%(refname) {prefix}",
+		statusCmd:        "status --porcelain -z",
+		gitDirCmd:        "rev-parse --git-dir",
+		stashCmd:         "stash push -m {message}",
+		stashPopCmd:      "stash pop",
+		stashListCmd:     "stash list --format=%gs",
+		rebaseCmd:        "rebase {upstream}",
+		continueCmd:      "{operation} --continue",
+		abortCmd:         "{operation} --abort",
+		mergeTreeCmd:     "merge-tree --write-tree --name-only --no-messages {target} {source}",
 	}
 }
 
@@ -48,14 +86,20 @@ func (c operatorBasedCmd) run(dir string, cmd string, keyval ...string) error {
 	return err
 }
 
-func (c operatorBasedCmd) run1(dir string, cmdline string, keyvalPairs []string) ([]byte, error) {
+// run1 runs cmdline and returns the output, quietCodes are exit codes which are expected
+// by caller, so they are not logged as failures.
+func (c operatorBasedCmd) run1(dir string, cmdline string, keyvalPairs []string, quietCodes ...int) ([]byte, error) {
 	m := make(map[string]string)
 	for i := 0; i < len(keyvalPairs); i += 2 {
 		m[keyvalPairs[i]] = keyvalPairs[i+1]
 	}
-	args := strings.Fields(cmdline)
-	for i, arg := range args {
-		args[i] = expand(m, arg)
+	fields := strings.Fields(cmdline)
+	args := make([]string, 0, len(fields))
+	for _, arg := range fields {
+		// optional flags are expanded into empty arguments, drop them.
+		if arg = expand(m, arg); arg != "" {
+			args = append(args, arg)
+		}
 	}
 
 	_, err := exec.LookPath(c.cmd)
@@ -77,6 +121,15 @@ func (c operatorBasedCmd) run1(dir string, cmdline string, keyvalPairs []string)
 	err = cmd.Run()
 	out := buf.Bytes()
 	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			for _, code := range quietCodes {
+				if exitErr.ExitCode() == code {
+					return out, err
+				}
+			}
+		}
+
 		log.
 			WithFields(log.Fields{
 				"dir":   dir,
@@ -151,6 +204,266 @@ func (c operatorBasedCmd) Merge(source, target string) error {
 	return err
 }
 
+// DeleteLocalBranch delete local branch with `git branch -d`, if force is true
+// `git branch -D` would be used instead.
+func (c operatorBasedCmd) DeleteLocalBranch(branchName string, force bool) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of DeleteLocalBranch")
+	}
+
+	deleteFlag := "-d"
+	if force {
+		deleteFlag = "-D"
+	}
+	return c.run(c.dir, c.deleteBranchCmd, "deleteFlag", deleteFlag, "branch", branchName)
+}
+
+// Push pushes local branch to the remote with `git push`, `-u` flag would be used if
+// setUpstream is true.
+func (c operatorBasedCmd) Push(branchName string, setUpstream bool) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of Push")
+	}
+
+	upstreamFlag := ""
+	if setUpstream {
+		upstreamFlag = "-u"
+	}
+	return c.run(c.dir, c.pushCmd, "upstreamFlag", upstreamFlag, "remote", c.remote, "branch", branchName)
+}
+
+// Pull fast-forwards current branch to the remote branch with `git pull --ff-only`.
+func (c operatorBasedCmd) Pull(branchName string) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of Pull")
+	}
+
+	return c.run(c.dir, c.pullCmd, "remote", c.remote, "branch", branchName)
+}
+
+// SetUpstream sets upstream of local branch with `git branch --set-upstream-to`.
+func (c operatorBasedCmd) SetUpstream(branchName string) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of SetUpstream")
+	}
+
+	return c.run(c.dir, c.setUpstreamCmd, "remote", c.remote, "branch", branchName)
+}
+
+// DeleteRemoteBranch deletes the branch of the remote with `git push --delete`.
+func (c operatorBasedCmd) DeleteRemoteBranch(branchName string) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of DeleteRemoteBranch")
+	}
+
+	return c.run(c.dir, c.deleteRemoteCmd, "remote", c.remote, "branch", branchName)
+}
+
+// ListBranches lists branches with `git for-each-ref`, full reference names are used
+// so that the output is not affected by ambiguous short names.
+func (c operatorBasedCmd) ListBranches(remote bool) ([]string, error) {
+	prefix := "refs/heads/"
+	if remote {
+		prefix = "refs/remotes/" + c.remote + "/"
+	}
+
+	out, err := c.run1(c.dir, c.listBranchesCmd, []string{"prefix", prefix})
+	if err != nil {
+		return nil, errors.Wrap(err, "list branches failed")
+	}
+
+	branches := make([]string, 0, 8)
+	for _, line := range strings.Split(string(out), "\n") {
+		name := strings.TrimPrefix(strings.TrimSpace(line), prefix)
+		if name == "" || name == "HEAD" {
+			continue
+		}
+		branches = append(branches, name)
+	}
+
+	return branches, nil
+}
+
+// Status returns the status of working tree by `git status --porcelain`, which is stable
+// and not affected by git configs or locales.
+func (c operatorBasedCmd) Status() (*Status, error) {
+	branch, err := c.CurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := c.run1(c.dir, c.gitDirCmd, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "get git directory failed")
+	}
+	gitDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(c.dir, gitDir)
+	}
+
+	if out, err = c.run1(c.dir, c.statusCmd, nil); err != nil {
+		return nil, errors.Wrap(err, "get status failed")
+	}
+
+	status := &Status{
+		Branch: branch,
+		InProgress: inProgressOperation(func(name string) bool {
+			_, err := os.Stat(filepath.Join(gitDir, name))
+			return err == nil
+		}),
+	}
+	status.Changed, status.Conflicted, status.Untracked = parsePorcelainStatus(out)
+
+	return status, nil
+}
+
+// parsePorcelainStatus parses output of `git status --porcelain -z`, each entry is "XY PATH",
+// and renamed or copied entry is followed by the original path.
+func parsePorcelainStatus(out []byte) (changed, conflicted, untracked []string) {
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+
+		x, y, name := entry[0], entry[1], entry[3:]
+		switch {
+		case x == '?' && y == '?':
+			untracked = append(untracked, name)
+			continue
+		case x == '!' && y == '!':
+			continue
+		case x == 'U' || y == 'U' || (x == 'A' && y == 'A') || (x == 'D' && y == 'D'):
+			conflicted = append(conflicted, name)
+		}
+		changed = append(changed, name)
+
+		if x == 'R' || x == 'C' {
+			// skip the original path.
+			i++
+		}
+	}
+
+	return changed, conflicted, untracked
+}
+
+// Stash saves changes with `git stash push`.
+func (c operatorBasedCmd) Stash(message string) error {
+	return c.run(c.dir, c.stashCmd, "message", message)
+}
+
+// StashPop restores the latest stash with `git stash pop`.
+func (c operatorBasedCmd) StashPop() error {
+	return c.run(c.dir, c.stashPopCmd)
+}
+
+// StashList lists messages of stashes with `git stash list`, each message is prefixed with
+// the branch where it's stashed, e.g. "On master: message".
+func (c operatorBasedCmd) StashList() ([]string, error) {
+	out, err := c.run1(c.dir, c.stashListCmd, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			messages = append(messages, line)
+		}
+	}
+
+	return messages, nil
+}
+
+// Rebase rebases current branch onto upstream with `git rebase`, the output is printed
+// in the same format as Merge.
+func (c operatorBasedCmd) Rebase(upstream string) error {
+	if upstream == "" {
+		return errors.New("invalid branch parameter of Rebase")
+	}
+
+	b, err := c.CurrentBranch()
+	if err != nil {
+		return errors.Wrapf(err, "Rebase => c.CurrentBranch() failed")
+	}
+
+	output, err := c.run1(c.dir, c.rebaseCmd, []string{"upstream", upstream})
+	if len(output) != 0 {
+		title := fmt.Sprintf("\nRebase Output (%s => %s):\n", upstream, b)
+		_, _ = fmt.Fprint(os.Stdout, title+string(output))
+	}
+
+	return err
+}
+
+// Continue continues the operation in progress with `git merge --continue` or `git rebase --continue`.
+func (c operatorBasedCmd) Continue() error {
+	return c.runInProgress(c.continueCmd, "continue")
+}
+
+// Abort aborts the operation in progress with `git merge --abort` or `git rebase --abort`.
+func (c operatorBasedCmd) Abort() error {
+	return c.runInProgress(c.abortCmd, "abort")
+}
+
+// runInProgress runs cmdline against the merge or rebase which is in progress.
+func (c operatorBasedCmd) runInProgress(cmdline, action string) error {
+	status, err := c.Status()
+	if err != nil {
+		return err
+	}
+
+	switch status.InProgress {
+	case OperationMerge, OperationRebase:
+	case "":
+		return errors.Errorf("could not %s: no merge or rebase is in progress", action)
+	default:
+		return errors.Errorf("could not %s: %s is in progress, please %s it manually",
+			action, status.InProgress, action)
+	}
+
+	output, err := c.run1(c.dir, cmdline, []string{"operation", status.InProgress})
+	if len(output) != 0 {
+		op := strings.ToUpper(status.InProgress[:1]) + status.InProgress[1:]
+		title := fmt.Sprintf("\n%s Output (%s):\n", op, action)
+		_, _ = fmt.Fprint(os.Stdout, title+string(output))
+	}
+
+	return err
+}
+
+// MergeTree dry runs the merge with `git merge-tree --write-tree` which requires git 2.38 or later,
+// it exits with 1 if there are conflicts, and conflicted files are listed after the tree.
+func (c operatorBasedCmd) MergeTree(source, target string) ([]string, error) {
+	if source == "" || target == "" {
+		return nil, errors.New("invalid branch parameter of MergeTree")
+	}
+
+	out, err := c.run1(c.dir, c.mergeTreeCmd, []string{"source", source, "target", target}, 1)
+	if err == nil {
+		return nil, nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		return nil, errors.Wrapf(err, "dry run merging %s into %s failed: %s", source, target, strings.TrimSpace(string(out)))
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	conflicts := make([]string, 0, len(lines))
+	seen := make(map[string]struct{}, len(lines))
+	// the first line is the tree object.
+	for _, name := range lines[1:] {
+		if _, ok := seen[name]; ok || name == "" {
+			continue
+		}
+		seen[name] = struct{}{}
+		conflicts = append(conflicts, name)
+	}
+
+	return conflicts, nil
+}
+
 // expand rewrites s to replace {k} with match[k] for each key k in match.
 func expand(match map[string]string, s string) string {
 	for k, v := range match {
@@ -164,11 +477,13 @@ func expand(match map[string]string, s string) string {
 // The environment is the current process's environment
 // but with an updated $PWD, so that an os.Getwd in the
 // child will be faster.
+// Commands are not attached to terminal, so editor is disabled,
+// default messages are used by `merge --continue` and `rebase --continue`.
 func envForDir(dir string) []string {
 	env := os.Environ()
 	// Internally we only use rooted paths, so dir is rooted.
 	// Even if dir is not rooted, no harm done.
-	return mergeEnvLists([]string{"PWD=" + dir}, env)
+	return mergeEnvLists([]string{"PWD=" + dir, "GIT_EDITOR=true"}, env)
 }
 
 // mergeEnvLists merges the two environment lists such that
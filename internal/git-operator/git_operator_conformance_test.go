@@ -0,0 +1,646 @@
+package gitop
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conformance cases run against temporary repositories for all backends, so that backends
+// keep the same semantics. Each case gets a clone of a fresh origin repository which has
+// one commit on master.
+var conformanceCases = []struct {
+	name string
+	run  func(t *testing.T, op IGitOperator, r *testRepos)
+}{
+	{
+		name: "CurrentBranch",
+		run: func(t *testing.T, op IGitOperator, _ *testRepos) {
+			b, err := op.CurrentBranch()
+			require.NoError(t, err)
+			assert.Equal(t, "master", b)
+		},
+	},
+	{
+		name: "Checkout_create",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.assertCurrentBranch(t, op, "feature/a")
+			// create an existed branch.
+			assert.Error(t, op.Checkout("master", true))
+			r.assertCurrentBranch(t, op, "feature/a")
+		},
+	},
+	{
+		name: "Checkout_local",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+			require.NoError(t, op.Checkout("master", false))
+			r.assertCurrentBranch(t, op, "master")
+			assert.NoFileExists(t, filepath.Join(r.workDir, "a.txt"))
+
+			require.NoError(t, op.Checkout("feature/a", false))
+			assert.FileExists(t, filepath.Join(r.workDir, "a.txt"))
+		},
+	},
+	{
+		name: "Checkout_not_found",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			assert.Error(t, op.Checkout("not-found", false))
+			r.assertCurrentBranch(t, op, "master")
+		},
+	},
+	{
+		name: "Checkout_local_changes",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "README.md", "feature a")
+			require.NoError(t, op.Checkout("master", false))
+			r.write(t, "README.md", "local changes")
+
+			assert.Error(t, op.Checkout("feature/a", false))
+			r.assertCurrentBranch(t, op, "master")
+			assert.Equal(t, "local changes", r.read(t, "README.md"))
+		},
+	},
+	{
+		name: "FetchOrigin_and_Checkout_remote",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			r.publish(t, "dev", "dev.txt", "dev")
+
+			require.NoError(t, op.FetchOrigin())
+			_, err := r.open(t, r.workDir).Reference(plumbing.NewRemoteReferenceName(r.remote, "dev"), true)
+			require.NoError(t, err)
+
+			require.NoError(t, op.Checkout("dev", false))
+			r.assertCurrentBranch(t, op, "dev")
+			assert.Equal(t, "dev", r.read(t, "dev.txt"))
+		},
+	},
+	{
+		name: "Merge",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			feature := r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+			require.NoError(t, op.Checkout("master", false))
+			master := r.commit(t, r.open(t, r.workDir), "b.txt", "b")
+			require.NoError(t, op.Checkout("feature/a", false))
+
+			require.NoError(t, op.Merge("feature/a", "master"))
+			r.assertCurrentBranch(t, op, "master")
+			assert.Equal(t, "a", r.read(t, "a.txt"))
+			assert.Equal(t, "b", r.read(t, "b.txt"))
+
+			head := r.head(t)
+			assert.Equal(t, []plumbing.Hash{master, feature}, head.ParentHashes)
+			r.assertClean(t)
+
+			// merge again, nothing changed.
+			require.NoError(t, op.Merge("feature/a", "master"))
+			assert.Equal(t, head.Hash, r.head(t).Hash)
+		},
+	},
+	{
+		name: "Merge_no_fast_forward",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			master := r.head(t).Hash
+			require.NoError(t, op.Checkout("feature/a", true))
+			feature := r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+
+			require.NoError(t, op.Merge("feature/a", "master"))
+			r.assertCurrentBranch(t, op, "master")
+			assert.Equal(t, []plumbing.Hash{master, feature}, r.head(t).ParentHashes)
+		},
+	},
+	{
+		name: "Merge_conflict",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "README.md", "feature a")
+			require.NoError(t, op.Checkout("master", false))
+			master := r.commit(t, r.open(t, r.workDir), "README.md", "master")
+
+			assert.Error(t, op.Merge("feature/a", "master"))
+			assert.Equal(t, master, r.head(t).Hash)
+		},
+	},
+	{
+		name: "Merge_different_lines",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			r.commit(t, r.open(t, r.workDir), "lines.txt", "1\n2\n3\n4\n5\n")
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "lines.txt", "one\n2\n3\n4\n5\n")
+			require.NoError(t, op.Checkout("master", false))
+			master := r.commit(t, r.open(t, r.workDir), "lines.txt", "1\n2\n3\n4\nfive\n")
+
+			err := op.Merge("feature/a", "master")
+			if errors.Is(err, ErrNotSupported) {
+				// the file is changed by both, nothing has been changed.
+				assert.Equal(t, master, r.head(t).Hash)
+				assert.Equal(t, "1\n2\n3\n4\nfive\n", r.read(t, "lines.txt"))
+				r.assertClean(t)
+				t.Skip(err.Error())
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "one\n2\n3\n4\nfive\n", r.read(t, "lines.txt"))
+			r.assertClean(t)
+		},
+	},
+	{
+		name: "Merge_staged_changes",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+			require.NoError(t, op.Checkout("master", false))
+			master := r.commit(t, r.open(t, r.workDir), "b.txt", "b")
+			r.write(t, "README.md", "staged changes")
+			cmd := exec.Command("git", "add", "README.md")
+			cmd.Dir = r.workDir
+			require.NoError(t, cmd.Run())
+
+			// staged changes are never committed into the merge commit.
+			assert.Error(t, op.Merge("feature/a", "master"))
+			assert.Equal(t, master, r.head(t).Hash)
+			assert.Equal(t, "staged changes", r.read(t, "README.md"))
+			assert.NoFileExists(t, filepath.Join(r.workDir, "a.txt"))
+		},
+	},
+	{
+		name: "DeleteLocalBranch",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+			require.NoError(t, op.Checkout("master", false))
+
+			// not merged
+			assert.Error(t, op.DeleteLocalBranch("feature/a", false))
+			require.NoError(t, op.DeleteLocalBranch("feature/a", true))
+			_, err := r.open(t, r.workDir).Reference(plumbing.NewBranchReferenceName("feature/a"), true)
+			assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+			// merged
+			require.NoError(t, op.Checkout("feature/b", true))
+			require.NoError(t, op.Checkout("master", false))
+			require.NoError(t, op.DeleteLocalBranch("feature/b", false))
+
+			// current branch
+			assert.Error(t, op.DeleteLocalBranch("master", true))
+		},
+	},
+	{
+		name: "Push",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			hash := r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+
+			require.NoError(t, op.Push("feature/a", false))
+			assert.Equal(t, hash, r.originHash(t, "feature/a"))
+			assert.Empty(t, r.upstream(t, "feature/a"))
+
+			hash = r.commit(t, r.open(t, r.workDir), "a.txt", "aa")
+			require.NoError(t, op.Push("feature/a", true))
+			assert.Equal(t, hash, r.originHash(t, "feature/a"))
+			assert.Equal(t, r.remote, r.upstream(t, "feature/a"))
+
+			// nothing to push.
+			require.NoError(t, op.Push("feature/a", false))
+		},
+	},
+	{
+		name: "Pull",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			hash := r.publish(t, "master", "b.txt", "b")
+
+			require.NoError(t, op.Pull("master"))
+			assert.Equal(t, hash, r.head(t).Hash)
+			assert.Equal(t, "b", r.read(t, "b.txt"))
+			r.assertClean(t)
+
+			// up to date.
+			require.NoError(t, op.Pull("master"))
+
+			// diverged.
+			r.publish(t, "master", "c.txt", "c")
+			local := r.commit(t, r.open(t, r.workDir), "d.txt", "d")
+			assert.Error(t, op.Pull("master"))
+			assert.Equal(t, local, r.head(t).Hash)
+		},
+	},
+	{
+		name: "SetUpstream",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			r.publish(t, "dev", "dev.txt", "dev")
+			require.NoError(t, op.FetchOrigin())
+			require.NoError(t, op.Checkout("dev", true))
+
+			require.NoError(t, op.SetUpstream("dev"))
+			assert.Equal(t, r.remote, r.upstream(t, "dev"))
+
+			// remote branch does not exist.
+			require.NoError(t, op.Checkout("feature/a", true))
+			assert.Error(t, op.SetUpstream("feature/a"))
+		},
+	},
+	{
+		name: "DeleteRemoteBranch",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+			require.NoError(t, op.Push("feature/a", true))
+
+			require.NoError(t, op.DeleteRemoteBranch("feature/a"))
+			_, err := r.open(t, r.originDir).Reference(plumbing.NewBranchReferenceName("feature/a"), true)
+			assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+			_, err = r.open(t, r.workDir).Reference(plumbing.NewRemoteReferenceName(r.remote, "feature/a"), true)
+			assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+
+			// not exist.
+			assert.Error(t, op.DeleteRemoteBranch("feature/a"))
+		},
+	},
+	{
+		name: "ListBranches",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			r.publish(t, "dev", "dev.txt", "dev")
+			require.NoError(t, op.FetchOrigin())
+			require.NoError(t, op.Checkout("feature/a", true))
+
+			local, err := op.ListBranches(false)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"master", "feature/a"}, local)
+
+			remote, err := op.ListBranches(true)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"master", "dev"}, remote)
+		},
+	},
+	{
+		name: "Status",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			status, err := op.Status()
+			require.NoError(t, err)
+			assert.True(t, status.Clean())
+			assert.Equal(t, "master", status.Branch)
+
+			r.write(t, "README.md", "local changes")
+			r.write(t, "new.txt", "new")
+			status, err = op.Status()
+			require.NoError(t, err)
+			assert.False(t, status.Clean())
+			assert.Equal(t, []string{"README.md"}, status.Changed)
+			assert.Equal(t, []string{"new.txt"}, status.Untracked)
+			assert.Empty(t, status.Conflicted)
+		},
+	},
+	{
+		name: "Status_in_progress",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			head := r.head(t).Hash.String()
+			require.NoError(t, os.WriteFile(filepath.Join(r.workDir, ".git", "MERGE_HEAD"), []byte(head), 0o644))
+
+			status, err := op.Status()
+			require.NoError(t, err)
+			assert.Equal(t, OperationMerge, status.InProgress)
+			assert.False(t, status.Clean())
+		},
+	},
+	{
+		name: "Status_detached",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			head := r.head(t).Hash.String()
+			require.NoError(t, os.WriteFile(filepath.Join(r.workDir, ".git", "HEAD"), []byte(head+"\n"), 0o644))
+
+			status, err := op.Status()
+			require.NoError(t, err)
+			assert.True(t, status.Detached())
+		},
+	},
+	{
+		name: "Stash_and_StashPop",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			r.write(t, "README.md", "local changes")
+			err := op.Stash("test")
+			if errors.Is(err, ErrNotSupported) {
+				t.Skip(err.Error())
+			}
+			require.NoError(t, err)
+			r.assertClean(t)
+			assert.Equal(t, "init", r.read(t, "README.md"))
+
+			messages, err := op.StashList()
+			require.NoError(t, err)
+			assert.Equal(t, []string{"On master: test"}, messages)
+
+			require.NoError(t, op.Checkout("feature/a", true))
+			require.NoError(t, op.StashPop())
+			assert.Equal(t, "local changes", r.read(t, "README.md"))
+			assert.Error(t, op.StashPop())
+
+			messages, err = op.StashList()
+			require.NoError(t, err)
+			assert.Empty(t, messages)
+		},
+	},
+	{
+		name: "Rebase",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+			master := r.publish(t, "master", "b.txt", "b")
+			require.NoError(t, op.FetchOrigin())
+
+			err := op.Rebase(r.remote + "/master")
+			if errors.Is(err, ErrNotSupported) {
+				t.Skip(err.Error())
+			}
+			require.NoError(t, err)
+			r.assertCurrentBranch(t, op, "feature/a")
+			assert.Equal(t, []plumbing.Hash{master}, r.head(t).ParentHashes)
+			assert.Equal(t, "a", r.read(t, "a.txt"))
+			assert.Equal(t, "b", r.read(t, "b.txt"))
+		},
+	},
+	{
+		name: "Rebase_conflict_and_Continue",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "README.md", "feature a")
+			master := r.publish(t, "master", "README.md", "master")
+			require.NoError(t, op.FetchOrigin())
+
+			err := op.Rebase(r.remote + "/master")
+			if errors.Is(err, ErrNotSupported) {
+				t.Skip(err.Error())
+			}
+			require.Error(t, err)
+			status, err := op.Status()
+			require.NoError(t, err)
+			assert.Equal(t, OperationRebase, status.InProgress)
+			assert.Equal(t, []string{"README.md"}, status.Conflicted)
+
+			// go-git could not resolve conflicts in index.
+			r.write(t, "README.md", "resolved")
+			add := exec.Command("git", "add", "README.md")
+			add.Dir = r.workDir
+			require.NoError(t, add.Run())
+
+			require.NoError(t, op.Continue())
+			r.assertCurrentBranch(t, op, "feature/a")
+			assert.Equal(t, []plumbing.Hash{master}, r.head(t).ParentHashes)
+			assert.Equal(t, "resolved", r.read(t, "README.md"))
+			assert.Error(t, op.Continue())
+		},
+	},
+	{
+		name: "Merge_conflict_and_Abort",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			feature := r.commit(t, r.open(t, r.workDir), "README.md", "feature a")
+			r.publish(t, "master", "README.md", "master")
+			require.NoError(t, op.FetchOrigin())
+
+			require.Error(t, op.Merge(r.remote+"/master", "feature/a"))
+			err := op.Abort()
+			if errors.Is(err, ErrNotSupported) {
+				t.Skip(err.Error())
+			}
+			require.NoError(t, err)
+			r.assertCurrentBranch(t, op, "feature/a")
+			assert.Equal(t, feature, r.head(t).Hash)
+			assert.Equal(t, "feature a", r.read(t, "README.md"))
+			assert.Error(t, op.Abort())
+		},
+	},
+	{
+		name: "MergeTree",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "a.txt", "a")
+			r.publish(t, "master", "b.txt", "b")
+			require.NoError(t, op.FetchOrigin())
+			head := r.head(t).Hash
+
+			conflicts, err := op.MergeTree("feature/a", r.remote+"/master")
+			require.NoError(t, err)
+			assert.Empty(t, conflicts)
+
+			// merged already.
+			conflicts, err = op.MergeTree(r.remote+"/master", r.remote+"/master")
+			require.NoError(t, err)
+			assert.Empty(t, conflicts)
+
+			assert.Equal(t, head, r.head(t).Hash)
+			r.assertCurrentBranch(t, op, "feature/a")
+			r.assertClean(t)
+		},
+	},
+	{
+		name: "MergeTree_conflict",
+		run: func(t *testing.T, op IGitOperator, r *testRepos) {
+			require.NoError(t, op.Checkout("feature/a", true))
+			r.commit(t, r.open(t, r.workDir), "README.md", "feature a")
+			r.publish(t, "master", "README.md", "master")
+			require.NoError(t, op.FetchOrigin())
+			head := r.head(t).Hash
+
+			conflicts, err := op.MergeTree("feature/a", r.remote+"/master")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"README.md"}, conflicts)
+
+			assert.Equal(t, head, r.head(t).Hash)
+			assert.Equal(t, "feature a", r.read(t, "README.md"))
+			r.assertClean(t)
+		},
+	},
+}
+
+func Test_gitOp_conformance(t *testing.T) {
+	// go-git depends on git-upload-pack and git-receive-pack to access local remote too.
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git executable file is not found")
+	}
+	// git merge never opens editor.
+	t.Setenv("GIT_MERGE_AUTOEDIT", "no")
+
+	backends := map[string]func(dir, remote string) IGitOperator{
+		BackendCmd:   NewBasedCmd,
+		BackendGoGit: NewBasedGoGit,
+	}
+
+	for backend, newOp := range backends {
+		backend, newOp := backend, newOp
+		t.Run(backend, func(t *testing.T) {
+			for _, remote := range []string{DefaultRemote, "upstream"} {
+				remote := remote
+				t.Run(remote, func(t *testing.T) {
+					for _, c := range conformanceCases {
+						c := c
+						t.Run(c.name, func(t *testing.T) {
+							r := newTestRepos(t, remote)
+							c.run(t, newOp(r.workDir, remote), r)
+						})
+					}
+				})
+			}
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	assert.IsType(t, operatorBasedCmd{}, New("", ".", ""))
+	assert.IsType(t, operatorBasedCmd{}, New(BackendCmd, ".", ""))
+	assert.IsType(t, operatorBasedGoGit{}, New(BackendGoGit, ".", ""))
+	assert.IsType(t, operatorBasedCmd{}, New("unknown", ".", ""))
+}
+
+// testRepos contains bare origin repository and its clones, work is the clone which
+// operators work on, and other is used to push commits into origin by someone else.
+type testRepos struct {
+	originDir string
+	workDir   string
+	other     *git.Repository
+	// remote is the name of origin in work.
+	remote string
+}
+
+var testSignature = object.Signature{
+	Name:  "gitlab-flow",
+	Email: "gitlab-flow@example.com",
+}
+
+func newTestRepos(t *testing.T, remote string) *testRepos {
+	seedDir, originDir, workDir, otherDir := t.TempDir(), t.TempDir(), t.TempDir(), t.TempDir()
+
+	seed, err := git.PlainInit(seedDir, false)
+	require.NoError(t, err)
+	r := &testRepos{originDir: originDir, workDir: workDir, remote: remote}
+	r.commit(t, seed, "README.md", "init")
+
+	_, err = git.PlainClone(originDir, true, &git.CloneOptions{URL: seedDir})
+	require.NoError(t, err)
+	work, err := git.PlainClone(workDir, false, &git.CloneOptions{URL: originDir, RemoteName: remote})
+	require.NoError(t, err)
+	r.other, err = git.PlainClone(otherDir, false, &git.CloneOptions{URL: originDir})
+	require.NoError(t, err)
+
+	cfg, err := work.Config()
+	require.NoError(t, err)
+	cfg.User.Name = testSignature.Name
+	cfg.User.Email = testSignature.Email
+	require.NoError(t, work.SetConfig(cfg))
+
+	return r
+}
+
+// open opens repository of dir, repositories are opened every time since objects written
+// by operators are not visible to the opened repository.
+func (r *testRepos) open(t *testing.T, dir string) *git.Repository {
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	return repo
+}
+
+// publish commits file into branch of origin by other, the branch is created from master
+// if it does not exist, returns the commit hash.
+func (r *testRepos) publish(t *testing.T, branch, name, content string) plumbing.Hash {
+	wt, err := r.other.Worktree()
+	require.NoError(t, err)
+
+	refName := plumbing.NewBranchReferenceName(branch)
+	_, err = r.other.Reference(refName, true)
+	require.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: refName, Create: err != nil}))
+
+	hash := r.commit(t, r.other, name, content)
+	require.NoError(t, r.other.Push(&git.PushOptions{
+		RefSpecs: []gitconfig.RefSpec{gitconfig.RefSpec(refName + ":" + refName)},
+	}))
+
+	return hash
+}
+
+// originHash returns the hash of branch in origin.
+func (r *testRepos) originHash(t *testing.T, branch string) plumbing.Hash {
+	ref, err := r.open(t, r.originDir).Reference(plumbing.NewBranchReferenceName(branch), true)
+	require.NoError(t, err)
+	return ref.Hash()
+}
+
+// upstream returns the remote which branch of work tracks.
+func (r *testRepos) upstream(t *testing.T, branch string) string {
+	cfg, err := r.open(t, r.workDir).Config()
+	require.NoError(t, err)
+	if b, ok := cfg.Branches[branch]; ok {
+		return b.Remote
+	}
+	return ""
+}
+
+// commit writes file into the working tree of repo and commits it, returns the commit hash.
+func (r *testRepos) commit(t *testing.T, repo *git.Repository, name, content string) plumbing.Hash {
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(wt.Filesystem.Root(), name), []byte(content), 0o644))
+	_, err = wt.Add(name)
+	require.NoError(t, err)
+
+	sig := testSignature
+	sig.When = time.Now()
+	hash, err := wt.Commit("update "+name, &git.CommitOptions{Author: &sig})
+	require.NoError(t, err)
+
+	return hash
+}
+
+func (r *testRepos) write(t *testing.T, name, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(r.workDir, name), []byte(content), 0o644))
+}
+
+func (r *testRepos) read(t *testing.T, name string) string {
+	data, err := os.ReadFile(filepath.Join(r.workDir, name))
+	require.NoError(t, err)
+	return string(data)
+}
+
+func (r *testRepos) head(t *testing.T) *object.Commit {
+	head, err := r.open(t, r.workDir).Head()
+	require.NoError(t, err)
+	commit, err := r.open(t, r.workDir).CommitObject(head.Hash())
+	require.NoError(t, err)
+	return commit
+}
+
+func (r *testRepos) assertCurrentBranch(t *testing.T, op IGitOperator, expected string) {
+	b, err := op.CurrentBranch()
+	require.NoError(t, err)
+	assert.Equal(t, expected, b)
+}
+
+func (r *testRepos) assertClean(t *testing.T) {
+	wt, err := r.open(t, r.workDir).Worktree()
+	require.NoError(t, err)
+	status, err := wt.Status()
+	require.NoError(t, err)
+	assert.True(t, status.IsClean(), status.String())
+}
+
+func Test_parsePorcelainStatus(t *testing.T) {
+	out := "M  staged.go\x00 M unstaged.go\x00R  new.go\x00old.go\x00UU conflict.go\x00" +
+		"AA both-added.go\x00?? untracked.go\x00!! ignored.go\x00"
+
+	changed, conflicted, untracked := parsePorcelainStatus([]byte(out))
+	assert.Equal(t, []string{"staged.go", "unstaged.go", "new.go", "conflict.go", "both-added.go"}, changed)
+	assert.Equal(t, []string{"conflict.go", "both-added.go"}, conflicted)
+	assert.Equal(t, []string{"untracked.go"}, untracked)
+}
@@ -0,0 +1,803 @@
+package gitop
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/pkg/errors"
+	"github.com/yeqown/log"
+)
+
+var _ IGitOperator = operatorBasedGoGit{}
+
+// operatorBasedGoGit implements IGitOperator based on go-git, it does not depend on
+// local `git` executable file, and its output is not affected by git configs or locales.
+//
+// NOTICE: fetching over HTTPS could not use git credential helpers, and Merge only merges
+// changes of files, a file changed in both branches is reported as conflict and nothing would
+// be changed, while `git merge` leaves conflict markers in the working tree.
+type operatorBasedGoGit struct {
+	// dir is the directory of git repository in local filesystem.
+	dir string
+	// remote is the name of remote which branches are pushed to and pulled from.
+	remote string
+}
+
+// NewBasedGoGit generate a git operator based on go-git, empty remote means DefaultRemote.
+func NewBasedGoGit(dir, remote string) IGitOperator {
+	if remote == "" {
+		remote = DefaultRemote
+	}
+
+	return operatorBasedGoGit{
+		dir:    dir,
+		remote: remote,
+	}
+}
+
+// open opens the repository every time, since references could be changed by
+// other process (e.g. user's git command) between two operations.
+func (g operatorBasedGoGit) open() (*git.Repository, *git.Worktree, error) {
+	repo, err := git.PlainOpenWithOptions(g.dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "open repository(%s) failed", g.dir)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "open worktree failed")
+	}
+
+	return repo, wt, nil
+}
+
+// Checkout local branch and control whether create a new branch or not. Same as `git checkout`,
+// if local branch does not exist but remote-tracking branch exists, local branch tracking
+// the remote branch would be created.
+func (g operatorBasedGoGit) Checkout(branchName string, create bool) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of Checkout")
+	}
+
+	repo, wt, err := g.open()
+	if err != nil {
+		return err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return errors.Wrap(err, "get HEAD failed")
+	}
+
+	refName := plumbing.NewBranchReferenceName(branchName)
+	if create {
+		if _, err = repo.Reference(refName, false); err == nil {
+			return errors.Errorf("a branch named '%s' already exists", branchName)
+		}
+		// HEAD is not changed, so the working tree is kept.
+		return errors.Wrapf(
+			wt.Checkout(&git.CheckoutOptions{Branch: refName, Create: true, Keep: true}),
+			"checkout -b %s failed", branchName)
+	}
+
+	ref, err := repo.Reference(refName, true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		if ref, err = g.createTrackingBranch(repo, branchName); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return errors.Wrapf(err, "get branch(%s) failed", branchName)
+	}
+
+	if err = g.ensureNotOverwritten(repo, wt, head.Hash(), ref.Hash()); err != nil {
+		return errors.Wrapf(err, "checkout %s failed", branchName)
+	}
+
+	return errors.Wrapf(wt.Checkout(&git.CheckoutOptions{Branch: refName}), "checkout %s failed", branchName)
+}
+
+// createTrackingBranch creates local branch from remote-tracking branch, and sets upstream
+// of the local branch.
+func (g operatorBasedGoGit) createTrackingBranch(repo *git.Repository, branchName string) (*plumbing.Reference, error) {
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName(g.remote, branchName), true)
+	if err != nil {
+		return nil, errors.Errorf("pathspec '%s' did not match any branch", branchName)
+	}
+
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branchName), remoteRef.Hash())
+	if err = repo.Storer.SetReference(ref); err != nil {
+		return nil, errors.Wrapf(err, "create branch(%s) failed", branchName)
+	}
+	if err = repo.CreateBranch(&gitconfig.Branch{
+		Name:   branchName,
+		Remote: g.remote,
+		Merge:  plumbing.NewBranchReferenceName(branchName),
+	}); err != nil && !errors.Is(err, git.ErrBranchExists) {
+		return nil, errors.Wrapf(err, "set upstream of branch(%s) failed", branchName)
+	}
+
+	log.
+		WithFields(log.Fields{
+			"branch": branchName,
+			"remote": g.remote,
+		}).
+		Debug("branch is set up to track remote branch")
+
+	return ref, nil
+}
+
+// FetchOrigin fetches all remotes, same as `git fetch --all`.
+func (g operatorBasedGoGit) FetchOrigin() error {
+	repo, _, err := g.open()
+	if err != nil {
+		return err
+	}
+
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return errors.Wrap(err, "list remotes failed")
+	}
+
+	for _, remote := range remotes {
+		log.Debugf("fetching %s", remote.Config().Name)
+		err = remote.Fetch(&git.FetchOptions{Progress: os.Stdout})
+		if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+			return errors.Wrapf(err, "fetch %s failed", remote.Config().Name)
+		}
+	}
+
+	return nil
+}
+
+// CurrentBranch returns the short name of current branch, "HEAD" would be returned
+// if HEAD is detached, same as `git rev-parse --abbrev-ref HEAD`.
+func (g operatorBasedGoGit) CurrentBranch() (string, error) {
+	repo, _, err := g.open()
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", errors.Wrapf(err, "get current branch name failed")
+	}
+	if !head.Name().IsBranch() {
+		return plumbing.HEAD.String(), nil
+	}
+
+	return head.Name().Short(), nil
+}
+
+// Merge would merge source into target without fast-forward, same as `git merge --no-ff`.
+// If current branch is not target branch, it checkouts to target automatically.
+//
+// go-git could not merge contents of files, so ErrNotSupported is returned if any file is changed
+// by both source and target, even if they change different lines. Nothing is changed in this case.
+func (g operatorBasedGoGit) Merge(source, target string) error {
+	if source == "" || target == "" {
+		return errors.New("invalid branch parameter of Merge")
+	}
+
+	b, err := g.CurrentBranch()
+	if err != nil {
+		return errors.Wrapf(err, "Merge => g.CurrentBranch() failed")
+	}
+	if strings.Compare(b, target) != 0 {
+		if err = g.Checkout(target, false); err != nil {
+			return errors.Wrap(err, "automatic checkout failed")
+		}
+	}
+
+	output, err := g.merge(source, target)
+	if len(output) != 0 {
+		title := fmt.Sprintf("\nMerge Output (%s => %s):\n", source, target)
+		_, _ = fmt.Fprint(os.Stdout, title+output)
+	}
+
+	return err
+}
+
+func (g operatorBasedGoGit) merge(source, target string) (string, error) {
+	repo, wt, err := g.open()
+	if err != nil {
+		return "", err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", errors.Wrap(err, "get HEAD failed")
+	}
+	sourceHash, err := repo.ResolveRevision(plumbing.Revision(source))
+	if err != nil {
+		return "", errors.Wrapf(err, "%s - not something we can merge", source)
+	}
+
+	targetCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", errors.Wrapf(err, "get commit of %s failed", target)
+	}
+	sourceCommit, err := repo.CommitObject(*sourceHash)
+	if err != nil {
+		return "", errors.Wrapf(err, "get commit of %s failed", source)
+	}
+
+	merged, err := sourceCommit.IsAncestor(targetCommit)
+	if err != nil {
+		return "", errors.Wrap(err, "check ancestor failed")
+	}
+	if merged || sourceCommit.Hash == targetCommit.Hash {
+		return "Already up to date.\n", nil
+	}
+
+	// the merge commit is made from the index, so staged changes would be committed together.
+	if err = ensureIndexClean(wt); err != nil {
+		return "", errors.Wrapf(err, "merge %s into %s failed", source, target)
+	}
+
+	applies, conflicts, err := mergeChanges(sourceCommit, targetCommit)
+	if err != nil {
+		return "", err
+	}
+	if len(conflicts) != 0 {
+		out := ""
+		for _, name := range conflicts {
+			out += "CHANGED BY BOTH: " + name + "\n"
+		}
+		return out, errors.Wrapf(ErrNotSupported, "merge %s into %s: %d file(s) changed by both branches "+
+			"could not be merged by go-git, nothing has been changed, use the cmd backend instead",
+			source, target, len(conflicts))
+	}
+
+	if err = g.ensureNotOverwritten(repo, wt, head.Hash(), *sourceHash); err != nil {
+		return "", errors.Wrapf(err, "merge %s into %s failed", source, target)
+	}
+
+	names := make([]string, 0, len(applies))
+	for name := range applies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := "Merge made by go-git.\n"
+	for _, name := range names {
+		if err = applyEntry(wt, sourceCommit, name, applies[name]); err != nil {
+			return out, errors.Wrapf(err, "apply %s failed", name)
+		}
+		if applies[name] == nil {
+			out += " delete " + name + "\n"
+			continue
+		}
+		out += " " + name + "\n"
+	}
+
+	_, err = wt.Commit(fmt.Sprintf("Merge branch '%s' into %s", source, target), &git.CommitOptions{
+		Parents:           []plumbing.Hash{targetCommit.Hash, sourceCommit.Hash},
+		AllowEmptyCommits: true,
+	})
+	if err != nil {
+		return out, errors.Wrap(err, "commit merge failed")
+	}
+
+	return out, nil
+}
+
+// DeleteLocalBranch delete local branch, the branch must be merged into HEAD unless
+// force is true, same as `git branch -d` and `git branch -D`.
+func (g operatorBasedGoGit) DeleteLocalBranch(branchName string, force bool) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of DeleteLocalBranch")
+	}
+
+	repo, _, err := g.open()
+	if err != nil {
+		return err
+	}
+
+	refName := plumbing.NewBranchReferenceName(branchName)
+	ref, err := repo.Reference(refName, true)
+	if err != nil {
+		return errors.Wrapf(err, "branch '%s' not found", branchName)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return errors.Wrap(err, "get HEAD failed")
+	}
+	if head.Name() == refName {
+		return errors.Errorf("cannot delete branch '%s' checked out", branchName)
+	}
+
+	if !force {
+		branchCommit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return errors.Wrapf(err, "get commit of %s failed", branchName)
+		}
+		headCommit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return errors.Wrap(err, "get commit of HEAD failed")
+		}
+		merged, err := branchCommit.IsAncestor(headCommit)
+		if err != nil {
+			return errors.Wrap(err, "check ancestor failed")
+		}
+		if !merged && branchCommit.Hash != headCommit.Hash {
+			return errors.Errorf("the branch '%s' is not fully merged", branchName)
+		}
+	}
+
+	if err = repo.Storer.RemoveReference(refName); err != nil {
+		return errors.Wrapf(err, "delete branch(%s) failed", branchName)
+	}
+	if err = repo.DeleteBranch(branchName); err != nil && !errors.Is(err, git.ErrBranchNotFound) {
+		return errors.Wrapf(err, "delete config of branch(%s) failed", branchName)
+	}
+
+	return nil
+}
+
+// Push pushes local branch to the remote, same as `git push [-u]`.
+func (g operatorBasedGoGit) Push(branchName string, setUpstream bool) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of Push")
+	}
+
+	repo, _, err := g.open()
+	if err != nil {
+		return err
+	}
+
+	refName := plumbing.NewBranchReferenceName(branchName)
+	err = repo.Push(&git.PushOptions{
+		RemoteName: g.remote,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(refName + ":" + refName)},
+		Progress:   os.Stdout,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return errors.Wrapf(err, "push %s to %s failed", branchName, g.remote)
+	}
+
+	if setUpstream {
+		return g.SetUpstream(branchName)
+	}
+
+	return nil
+}
+
+// Pull fast-forwards current branch to the remote branch, same as `git pull --ff-only`.
+func (g operatorBasedGoGit) Pull(branchName string) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of Pull")
+	}
+
+	_, wt, err := g.open()
+	if err != nil {
+		return err
+	}
+
+	err = wt.Pull(&git.PullOptions{
+		RemoteName:    g.remote,
+		ReferenceName: plumbing.NewBranchReferenceName(branchName),
+		Progress:      os.Stdout,
+	})
+	if errors.Is(err, git.ErrNonFastForwardUpdate) {
+		return errors.Errorf("pull %s from %s failed: not possible to fast-forward", branchName, g.remote)
+	}
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return errors.Wrapf(err, "pull %s from %s failed", branchName, g.remote)
+	}
+
+	return nil
+}
+
+// SetUpstream sets upstream of local branch, same as `git branch --set-upstream-to`.
+func (g operatorBasedGoGit) SetUpstream(branchName string) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of SetUpstream")
+	}
+
+	repo, _, err := g.open()
+	if err != nil {
+		return err
+	}
+
+	refName := plumbing.NewBranchReferenceName(branchName)
+	if _, err = repo.Reference(refName, true); err != nil {
+		return errors.Wrapf(err, "branch '%s' not found", branchName)
+	}
+	if _, err = repo.Reference(plumbing.NewRemoteReferenceName(g.remote, branchName), true); err != nil {
+		return errors.Errorf("the requested upstream branch '%s/%s' does not exist", g.remote, branchName)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return errors.Wrap(err, "get config failed")
+	}
+	b, ok := cfg.Branches[branchName]
+	if !ok {
+		b = &gitconfig.Branch{Name: branchName}
+		cfg.Branches[branchName] = b
+	}
+	b.Remote = g.remote
+	b.Merge = refName
+
+	return errors.Wrapf(repo.SetConfig(cfg), "set upstream of branch(%s) failed", branchName)
+}
+
+// DeleteRemoteBranch deletes the branch of the remote and its remote-tracking branch,
+// same as `git push --delete`.
+func (g operatorBasedGoGit) DeleteRemoteBranch(branchName string) error {
+	if branchName == "" {
+		return errors.New("invalid branch parameter of DeleteRemoteBranch")
+	}
+
+	repo, _, err := g.open()
+	if err != nil {
+		return err
+	}
+
+	err = repo.Push(&git.PushOptions{
+		RemoteName: g.remote,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(":" + plumbing.NewBranchReferenceName(branchName))},
+		Progress:   os.Stdout,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return errors.Errorf("unable to delete '%s': remote ref does not exist", branchName)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "delete branch(%s) of %s failed", branchName, g.remote)
+	}
+
+	err = repo.Storer.RemoveReference(plumbing.NewRemoteReferenceName(g.remote, branchName))
+	return errors.Wrapf(err, "delete remote-tracking branch(%s/%s) failed", g.remote, branchName)
+}
+
+// ListBranches lists local branches, or remote-tracking branches of the remote.
+func (g operatorBasedGoGit) ListBranches(remote bool) ([]string, error) {
+	repo, _, err := g.open()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := "refs/heads/"
+	if remote {
+		prefix = "refs/remotes/" + g.remote + "/"
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, errors.Wrap(err, "list branches failed")
+	}
+
+	branches := make([]string, 0, 8)
+	_ = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().String()
+		if !strings.HasPrefix(name, prefix) || name == prefix+"HEAD" {
+			return nil
+		}
+		branches = append(branches, strings.TrimPrefix(name, prefix))
+		return nil
+	})
+	sort.Strings(branches)
+
+	return branches, nil
+}
+
+// Status returns the status of working tree, operation in progress is detected
+// by files in git directory.
+func (g operatorBasedGoGit) Status() (*Status, error) {
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+
+	repo, wt, err := g.open()
+	if err != nil {
+		return nil, err
+	}
+	st, err := wt.Status()
+	if err != nil {
+		return nil, errors.Wrap(err, "get status failed")
+	}
+
+	status := &Status{Branch: branch}
+	if storage, ok := repo.Storer.(*filesystem.Storage); ok {
+		status.InProgress = inProgressOperation(func(name string) bool {
+			_, err := storage.Filesystem().Stat(name)
+			return err == nil
+		})
+	}
+
+	for name, fs := range st {
+		switch {
+		case fs.Staging == git.Untracked && fs.Worktree == git.Untracked:
+			status.Untracked = append(status.Untracked, name)
+			continue
+		case fs.Staging == git.UpdatedButUnmerged || fs.Worktree == git.UpdatedButUnmerged:
+			status.Conflicted = append(status.Conflicted, name)
+		case fs.Staging == git.Unmodified && fs.Worktree == git.Unmodified:
+			continue
+		}
+		status.Changed = append(status.Changed, name)
+	}
+	sort.Strings(status.Changed)
+	sort.Strings(status.Conflicted)
+	sort.Strings(status.Untracked)
+
+	return status, nil
+}
+
+// Stash is not supported, since go-git has no stash support.
+func (g operatorBasedGoGit) Stash(_ string) error {
+	return errors.Wrap(ErrNotSupported, "stash")
+}
+
+// StashPop is not supported, since go-git has no stash support.
+func (g operatorBasedGoGit) StashPop() error {
+	return errors.Wrap(ErrNotSupported, "stash pop")
+}
+
+// StashList is not supported, since go-git has no stash support.
+func (g operatorBasedGoGit) StashList() ([]string, error) {
+	return nil, errors.Wrap(ErrNotSupported, "stash list")
+}
+
+// Rebase is not supported, since go-git has no rebase support.
+func (g operatorBasedGoGit) Rebase(_ string) error {
+	return errors.Wrap(ErrNotSupported, "rebase")
+}
+
+// Continue is not supported, Merge of go-git never stops with conflicts in progress.
+func (g operatorBasedGoGit) Continue() error {
+	return errors.Wrap(ErrNotSupported, "continue")
+}
+
+// Abort is not supported, Merge of go-git never stops with conflicts in progress.
+func (g operatorBasedGoGit) Abort() error {
+	return errors.Wrap(ErrNotSupported, "abort")
+}
+
+// MergeTree dry runs the merge in the same way as Merge, conflicts are detected by files rather than lines,
+// so files changed by both source and target differently are reported, although `git merge` may merge
+// them cleanly. The result should be treated as advisory.
+func (g operatorBasedGoGit) MergeTree(source, target string) ([]string, error) {
+	if source == "" || target == "" {
+		return nil, errors.New("invalid branch parameter of MergeTree")
+	}
+
+	repo, _, err := g.open()
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]*object.Commit, 0, 2)
+	for _, rev := range []string{source, target} {
+		hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s - not something we can merge", rev)
+		}
+		commit, err := repo.CommitObject(*hash)
+		if err != nil {
+			return nil, errors.Wrapf(err, "get commit of %s failed", rev)
+		}
+		commits = append(commits, commit)
+	}
+
+	merged, err := commits[0].IsAncestor(commits[1])
+	if err != nil {
+		return nil, errors.Wrap(err, "check ancestor failed")
+	}
+	if merged || commits[0].Hash == commits[1].Hash {
+		return nil, nil
+	}
+
+	_, conflicts, err := mergeChanges(commits[0], commits[1])
+	return conflicts, err
+}
+
+// ensureNotOverwritten returns error if any local changes of tracked files would be overwritten
+// while changing the working tree from commit from to commit to.
+func (g operatorBasedGoGit) ensureNotOverwritten(
+	repo *git.Repository, wt *git.Worktree, from, to plumbing.Hash) error {
+	if from == to {
+		return nil
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return errors.Wrap(err, "get status failed")
+	}
+
+	fromCommit, err := repo.CommitObject(from)
+	if err != nil {
+		return errors.Wrapf(err, "get commit(%s) failed", from)
+	}
+	toCommit, err := repo.CommitObject(to)
+	if err != nil {
+		return errors.Wrapf(err, "get commit(%s) failed", to)
+	}
+	changes, err := diffCommits(fromCommit, toCommit)
+	if err != nil {
+		return err
+	}
+
+	dirty := make([]string, 0, 4)
+	for name := range changes {
+		s, ok := status[name]
+		if !ok || s.Staging == git.Untracked && s.Worktree == git.Untracked {
+			continue
+		}
+		if s.Staging != git.Unmodified || s.Worktree != git.Unmodified {
+			dirty = append(dirty, name)
+		}
+	}
+	if len(dirty) != 0 {
+		sort.Strings(dirty)
+		return errors.Errorf("your local changes to the following files would be overwritten: %s",
+			strings.Join(dirty, ", "))
+	}
+
+	return nil
+}
+
+// ensureIndexClean returns error if there is any staged change, like `git merge` does.
+func ensureIndexClean(wt *git.Worktree) error {
+	status, err := wt.Status()
+	if err != nil {
+		return errors.Wrap(err, "get status failed")
+	}
+
+	staged := make([]string, 0, 4)
+	for name, s := range status {
+		if s.Staging != git.Unmodified && s.Staging != git.Untracked {
+			staged = append(staged, name)
+		}
+	}
+	if len(staged) != 0 {
+		sort.Strings(staged)
+		return errors.Errorf("your local changes to the following files would be overwritten: %s",
+			strings.Join(staged, ", "))
+	}
+
+	return nil
+}
+
+// mergeChanges returns changes of source since the merge base which are not in target, and files
+// which are changed differently by both of them.
+func mergeChanges(source, target *object.Commit) (map[string]*object.TreeEntry, []string, error) {
+	bases, err := target.MergeBase(source)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "find merge base failed")
+	}
+	if len(bases) == 0 {
+		return nil, nil, errors.New("refusing to merge unrelated histories")
+	}
+
+	sourceChanges, err := diffCommits(bases[0], source)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetChanges, err := diffCommits(bases[0], target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// only changes of source which are not in target should be applied.
+	applies := make(map[string]*object.TreeEntry, len(sourceChanges))
+	conflicts := make([]string, 0, 4)
+	for name, entry := range sourceChanges {
+		t, ok := targetChanges[name]
+		if !ok {
+			applies[name] = entry
+			continue
+		}
+		if !sameEntry(t, entry) {
+			conflicts = append(conflicts, name)
+		}
+	}
+	sort.Strings(conflicts)
+
+	return applies, conflicts, nil
+}
+
+// diffCommits returns changed files from commit a to commit b, and the entries of files
+// in commit b, nil entry means the file is deleted.
+func diffCommits(a, b *object.Commit) (map[string]*object.TreeEntry, error) {
+	aTree, err := a.Tree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "get tree of commit(%s) failed", a.Hash)
+	}
+	bTree, err := b.Tree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "get tree of commit(%s) failed", b.Hash)
+	}
+
+	changes, err := object.DiffTree(aTree, bTree)
+	if err != nil {
+		return nil, errors.Wrap(err, "diff tree failed")
+	}
+
+	entries := make(map[string]*object.TreeEntry, len(changes))
+	for _, change := range changes {
+		if change.From.Name != "" {
+			entries[change.From.Name] = nil
+		}
+		if change.To.Name != "" {
+			entry := change.To.TreeEntry
+			entries[change.To.Name] = &entry
+		}
+	}
+
+	return entries, nil
+}
+
+func sameEntry(a, b *object.TreeEntry) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Hash == b.Hash && a.Mode == b.Mode
+}
+
+// applyEntry writes file name of commit into working tree and stages it, nil entry
+// means the file should be removed.
+func applyEntry(wt *git.Worktree, commit *object.Commit, name string, entry *object.TreeEntry) error {
+	if entry == nil {
+		_, err := wt.Remove(name)
+		return err
+	}
+
+	file, err := commit.File(name)
+	if err != nil {
+		return err
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	if err = wt.Filesystem.MkdirAll(path.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	perm := os.FileMode(0o644)
+	if entry.Mode == filemode.Executable {
+		perm = 0o755
+	}
+	if entry.Mode == filemode.Symlink {
+		target, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		_ = wt.Filesystem.Remove(name)
+		if err = wt.Filesystem.Symlink(string(target), name); err != nil {
+			return err
+		}
+	} else {
+		f, err := wt.Filesystem.OpenFile(name, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(f, reader); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err = f.Close(); err != nil {
+			return err
+		}
+	}
+
+	_, err = wt.Add(name)
+	return err
+}
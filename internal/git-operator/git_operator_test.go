@@ -14,25 +14,25 @@ var (
 )
 
 func Test_gitOp_FetchOrigin(t *testing.T) {
-	op := NewBasedCmd(repoPath)
+	op := NewBasedCmd(repoPath, "")
 	err := op.FetchOrigin()
 	assert.Nil(t, err)
 }
 
 func Test_gitOp_Checkout(t *testing.T) {
-	op := NewBasedCmd(repoPath)
+	op := NewBasedCmd(repoPath, "")
 	err := op.Checkout("hotfix/hotfix-1", false)
 	assert.Nil(t, err)
 }
 
 func Test_gitOp_Checkout_create(t *testing.T) {
-	op := NewBasedCmd(repoPath)
+	op := NewBasedCmd(repoPath, "")
 	err := op.Checkout("checkout-ddd", true)
 	assert.Nil(t, err)
 }
 
 func Test_gitOp_CurrentBranch(t *testing.T) {
-	op := NewBasedCmd(repoPath)
+	op := NewBasedCmd(repoPath, "")
 
 	b := "hotfix/hotfix-1"
 	cb, err := op.CurrentBranch()
@@ -41,7 +41,7 @@ func Test_gitOp_CurrentBranch(t *testing.T) {
 }
 
 func Test_gitOp_Merge(t *testing.T) {
-	op := NewBasedCmd(repoPath)
+	op := NewBasedCmd(repoPath, "")
 	src, target := "hotfix/hotfix-1", "master"
 	err := op.Merge(src, target)
 	assert.Nil(t, err)
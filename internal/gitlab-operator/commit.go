@@ -0,0 +1,49 @@
+package gitlabop
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	gogitlab "github.com/xanzy/go-gitlab"
+)
+
+// CompareCommitsRequest lists commits those are reachable from To but not from From,
+// e.g. commits of source branch those have not been merged into target branch.
+type CompareCommitsRequest struct {
+	ProjectID int
+	From      string
+	To        string
+}
+
+type CommitShort struct {
+	ID      string
+	ShortID string
+	Title   string
+	Author  string
+	WebURL  string
+}
+
+func (g gitlabOperator) CompareCommits(ctx context.Context, req *CompareCommitsRequest) ([]CommitShort, error) {
+	opt := &gogitlab.CompareOptions{
+		From:     &req.From,
+		To:       &req.To,
+		Straight: gogitlab.Ptr(false),
+	}
+	compare, _, err := g.gitlab.Repositories.Compare(req.ProjectID, opt, gogitlab.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "compare %s...%s failed", req.From, req.To)
+	}
+
+	commits := make([]CommitShort, 0, len(compare.Commits))
+	for _, v := range compare.Commits {
+		commits = append(commits, CommitShort{
+			ID:      v.ID,
+			ShortID: v.ShortID,
+			Title:   v.Title,
+			Author:  v.AuthorName,
+			WebURL:  v.WebURL,
+		})
+	}
+
+	return commits, nil
+}
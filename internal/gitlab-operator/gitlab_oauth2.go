@@ -154,6 +154,18 @@ func (g *gitlabOAuth2Support) Enter(refreshToken string) (err error) {
 	return
 }
 
+// Refresh requests new tokens by refreshToken, and never triggers authorization.
+func (g *gitlabOAuth2Support) Refresh(refreshToken string) (string, string, error) {
+	if refreshToken == "" {
+		return "", "", errors.New("empty refresh token")
+	}
+	if err := g.requestToken(context.TODO(), refreshToken, true); err != nil {
+		return "", "", err
+	}
+
+	return g.oc.AccessToken, g.oc.RefreshToken, nil
+}
+
 func (g *gitlabOAuth2Support) Load() (accessToken, refreshToken string) {
 	// waits for tokenC channel's signal.
 	_, ok := <-g.tokenC
@@ -269,10 +281,13 @@ render:
 }
 
 // serve is serving a backend HTTP server process to
-// receive redirect requests from gitlab.
+// receive redirect requests from gitlab. Handlers are registered into its own mux,
+// since support could be constructed several times in one process, e.g. operating
+// a feature across projects.
 func (g *gitlabOAuth2Support) serve() {
-	http.HandleFunc(callbackURI, g.callbackHandl)
-	err := http.ListenAndServe(g.oc.ServeAddr, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackURI, g.callbackHandl)
+	err := http.ListenAndServe(g.oc.ServeAddr, mux)
 	if err != nil {
 		log.Errorf("gitlabOAuth2Support serve quit: %v", err)
 	}
@@ -1,6 +1,9 @@
 package gitlabop
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // IGitlabOperator contains all operations those manage repository,
 // milestones, branch, issue and merge requests.
@@ -8,6 +11,8 @@ type IGitlabOperator interface {
 	// CreateBranch create a branch on remote gitlab repository, but this would check remote
 	// resource if create failed.
 	CreateBranch(ctx context.Context, req *CreateBranchRequest) (*CreateBranchResult, error)
+	// DeleteBranch delete a branch on remote gitlab repository.
+	DeleteBranch(ctx context.Context, req *DeleteBranchRequest) error
 
 	// CreateMilestone create a milestone on remote gitlab repository, but this would check remote
 	// resource if create failed.
@@ -16,17 +21,54 @@ type IGitlabOperator interface {
 	GetMilestoneMergeRequests(
 		ctx context.Context, req *GetMilestoneMergeRequestsRequest) (*GetMilestoneMergeRequestsResult, error)
 	GetMilestoneIssues(ctx context.Context, req *GetMilestoneIssuesRequest) (*GetMilestoneIssuesResult, error)
+	// CloseMilestone close a milestone on remote gitlab repository.
+	CloseMilestone(ctx context.Context, req *CloseMilestoneRequest) error
 
 	// CreateIssue create an issue on remote repository, but this would check remote
 	// resource if create failed.
 	CreateIssue(ctx context.Context, req *CreateIssueRequest) (*CreateIssueResult, error)
+	// CloseIssue close an issue on remote repository.
+	CloseIssue(ctx context.Context, req *CloseIssueRequest) error
 	// CreateMergeRequest create an merge request on remote repository, but this would check remote
 	// resource if create failed.
 	CreateMergeRequest(ctx context.Context, req *CreateMergeRequest) (*CreateMergeResult, error)
-	MergeMergeRequest(ctx context.Context, req *MergeMergeRequest) error
+	MergeMergeRequest(ctx context.Context, req *MergeMergeRequest) (*MergeMergeRequestResult, error)
+	// GetMergeRequest get a merge request from remote repository, it's useful to
+	// check the latest state of the merge request.
+	GetMergeRequest(ctx context.Context, req *GetMergeRequestRequest) (*GetMergeRequestResult, error)
+	// UpdateMergeRequest update title, description or draft state of a merge request.
+	UpdateMergeRequest(ctx context.Context, req *UpdateMergeRequestRequest) (*UpdateMergeRequestResult, error)
 
-	ListMilestones(ctx context.Context, req *ListMilestoneRequest) (*ListMilestoneResult, error)
-	ListProjects(ctx context.Context, req *ListProjectRequest) (*ListProjectResult, error)
+	// CreateTag create a tag on remote repository which points to req.Ref.
+	CreateTag(ctx context.Context, req *CreateTagRequest) (*CreateTagResult, error)
+
+	// CreateRelease create a gitlab release of the tag on remote repository.
+	CreateRelease(ctx context.Context, req *CreateReleaseRequest) (*CreateReleaseResult, error)
+
+	// ListMilestones iterates all milestones matched req of the project, the pages would be requested
+	// on demand.
+	ListMilestones(ctx context.Context, req *ListMilestoneRequest) Iterator[MilestoneShort]
+	// ListProjects iterates all projects matched req, the pages would be requested on demand.
+	ListProjects(ctx context.Context, req *ListProjectRequest) Iterator[ProjectShort]
+	// GetProject get a project from remote repository, includes the group it belongs to.
+	GetProject(ctx context.Context, req *GetProjectRequest) (*ProjectShort, error)
+	// ListTags iterates all tags of the project, the pages would be requested on demand.
+	ListTags(ctx context.Context, req *ListTagRequest) Iterator[TagShort]
+	// ListMergeRequests iterates all merge requests matched req of the project, the pages
+	// would be requested on demand.
+	ListMergeRequests(ctx context.Context, req *ListMergeRequestRequest) Iterator[MergeRequestShort]
+
+	// GetLatestPipeline get the latest pipeline of a branch or a merge request, ErrPipelineNotFound
+	// would be returned if there is no pipeline.
+	GetLatestPipeline(ctx context.Context, req *GetLatestPipelineRequest) (*PipelineShort, error)
+
+	// GetUser get a user by username, or the current user if username is empty. It's used to
+	// resolve usernames into IDs of assignees and reviewers.
+	GetUser(ctx context.Context, req *GetUserRequest) (*UserShort, error)
+
+	// CompareCommits lists commits between two refs, it's used to generate commit list
+	// in description of merge request.
+	CompareCommits(ctx context.Context, req *CompareCommitsRequest) ([]CommitShort, error)
 }
 
 // CreateBranchRequest
@@ -43,11 +85,46 @@ type CreateBranchResult struct {
 	WebURL string
 }
 
+// DeleteBranchRequest
+type DeleteBranchRequest struct {
+	BranchName string
+	ProjectID  int
+}
+
+// CreateTagRequest
+type CreateTagRequest struct {
+	TagName   string
+	Ref       string // branch name or commit SHA
+	Message   string // annotated tag would be created if message is not empty
+	ProjectID int
+}
+
+type CreateTagResult struct {
+	Name      string
+	CommitSHA string
+}
+
+// CreateReleaseRequest
+type CreateReleaseRequest struct {
+	TagName     string
+	Name        string
+	Description string   // release notes in markdown
+	Milestones  []string // titles of milestones associated with the release
+	ProjectID   int
+}
+
+type CreateReleaseResult struct {
+	TagName string
+	Name    string
+}
+
 // CreateMilestoneRequest
 type CreateMilestoneRequest struct {
 	Title     string
 	Desc      string
 	ProjectID int
+	// GroupID creates a group milestone if it's not zero, and ProjectID is ignored.
+	GroupID int
 }
 
 type CreateMilestoneResult struct {
@@ -59,6 +136,8 @@ type CreateMilestoneResult struct {
 type GetMilestoneRequest struct {
 	MilestoneID int
 	ProjectID   int
+	// GroupID means the milestone is a group milestone if it's not zero.
+	GroupID int
 }
 
 type GetMilestoneResult struct {
@@ -66,12 +145,25 @@ type GetMilestoneResult struct {
 	Title       string
 	Description string
 	WebURL      string
+	// GroupID is not zero if the milestone is a group milestone.
+	GroupID int
+}
+
+// CloseMilestoneRequest
+type CloseMilestoneRequest struct {
+	MilestoneID int
+	ProjectID   int
+	// GroupID means the milestone is a group milestone if it's not zero.
+	GroupID int
 }
 
 // GetMilestoneMergeRequestsRequest
 type GetMilestoneMergeRequestsRequest struct {
 	MilestoneID int
 	ProjectID   int
+	// GroupID means the milestone is a group milestone if it's not zero, then merge requests
+	// of all projects in the group would be returned.
+	GroupID int
 }
 
 type GetMilestoneMergeRequestsResult struct {
@@ -81,17 +173,49 @@ type GetMilestoneMergeRequestsResult struct {
 type MergeRequestShort struct {
 	ID           int
 	IID          int
+	ProjectID    int
 	Title        string
 	Description  string
 	WebURL       string
 	SourceBranch string
 	TargetBranch string
+
+	State          MergeRequestState
+	CreatedAt      *time.Time
+	MergedAt       *time.Time
+	Author         string // username of the author
+	PipelineStatus string // status of the head pipeline, empty if there is no pipeline.
+	Draft          bool
+
+	// HasConflicts and DetailedMergeStatus tell whether the merge request could be merged or not.
+	// gitlab checks the mergeability asynchronously, so they are not reliable while MergeStatusChecking
+	// returns true.
+	HasConflicts        bool
+	DetailedMergeStatus string
+
+	// MergeCommitSHA is the commit which the merge request has been merged as, it's squash commit
+	// if squash without merge commit, or the head commit of the merge request if fast-forward merge.
+	// It's empty if the merge request has not been merged.
+	MergeCommitSHA string
+}
+
+// MergeStatusChecking returns true if gitlab is still checking the mergeability of the merge request.
+func (mr MergeRequestShort) MergeStatusChecking() bool {
+	switch mr.DetailedMergeStatus {
+	case "unchecked", "checking", "preparing", "cannot_be_merged_recheck":
+		return true
+	}
+
+	return false
 }
 
 // GetMilestoneIssuesRequest
 type GetMilestoneIssuesRequest struct {
 	MilestoneID int
 	ProjectID   int
+	// GroupID means the milestone is a group milestone if it's not zero, then issues
+	// of all projects in the group would be returned.
+	GroupID int
 }
 
 type GetMilestoneIssuesResult struct {
@@ -106,6 +230,7 @@ type IssueShort struct {
 	WebURL      string
 	ProjectID   int
 	MilestoneID int
+	Labels      []string
 }
 
 // CreateIssueRequest
@@ -113,6 +238,8 @@ type CreateIssueRequest struct {
 	Title, Desc, RelatedBranch string
 	MilestoneID                int
 	ProjectID                  int
+	AssigneeIDs                []int
+	Labels                     []string
 }
 
 type CreateIssueResult struct {
@@ -121,30 +248,107 @@ type CreateIssueResult struct {
 	WebURL string
 }
 
+// CloseIssueRequest
+type CloseIssueRequest struct {
+	IssueIID  int
+	ProjectID int
+}
+
 // CreateMergeRequest
 type CreateMergeRequest struct {
 	Title, Desc, SrcBranch, TargetBranch string
 	MilestoneID, IssueIID                int
 	ProjectID                            int
 	AutoMerge                            bool
+	// Draft opens the merge request as draft, which could not be merged until it's marked as ready.
+	Draft                    bool
+	AssigneeIDs, ReviewerIDs []int
+	Labels                   []string
 }
 
 type CreateMergeResult struct {
 	ID     int
 	IID    int
 	WebURL string
+	State  MergeRequestState
+	Author string
+	Draft  bool
+}
+
+// UpdateMergeRequestRequest updates fields those are not empty (or nil).
+type UpdateMergeRequestRequest struct {
+	MergeRequestIID int
+	ProjectID       int
+
+	Title, Desc string
+	// Draft marks the merge request as draft if it's true, or ready if it's false.
+	Draft *bool
+}
+
+type UpdateMergeRequestResult struct {
+	Title  string
+	WebURL string
+	Draft  bool
 }
 
 type MergeMergeRequest struct {
 	MergeRequestID int
 	ProjectID      int
+
+	// MergeWhenPipelineSucceeds schedules the merge request to be merged after its pipeline succeeded,
+	// it would be merged immediately if there is no pipeline.
+	MergeWhenPipelineSucceeds bool
+	Squash                    bool
+	ShouldRemoveSourceBranch  bool
+}
+
+type MergeMergeRequestResult struct {
+	State MergeRequestState
+	// MergeWhenPipelineSucceeds is true if the merge request has been scheduled rather than merged.
+	MergeWhenPipelineSucceeds bool
+	MergedAt                  *time.Time
 }
 
+// GetMergeRequestRequest
+type GetMergeRequestRequest struct {
+	MergeRequestIID int
+	ProjectID       int
+}
+
+// MergeRequestState represents the state of merge request on gitlab.
+type MergeRequestState string
+
+const (
+	MergeRequestStateOpened MergeRequestState = "opened"
+	MergeRequestStateClosed MergeRequestState = "closed"
+	MergeRequestStateLocked MergeRequestState = "locked"
+	MergeRequestStateMerged MergeRequestState = "merged"
+)
+
+type GetMergeRequestResult struct {
+	MergeRequestShort
+}
+
+// Milestone states could be used to filter milestones.
+const (
+	MilestoneStateActive = "active"
+	MilestoneStateClosed = "closed"
+	// MilestoneStateAll means milestones would not be filtered by state.
+	MilestoneStateAll = "all"
+)
+
 // ListMilestoneRequest
 type ListMilestoneRequest struct {
-	Page      int
+	// PerPage is the page size, default is 100.
 	PerPage   int
 	ProjectID int
+	// Search filters milestones whose title or description contains Search.
+	Search string
+	// State filters milestones by state, default is MilestoneStateActive,
+	// MilestoneStateAll means all milestones.
+	State string
+	// IncludeParentMilestones means milestones of ancestor groups would be listed too.
+	IncludeParentMilestones bool
 }
 
 type MilestoneShort struct {
@@ -153,26 +357,56 @@ type MilestoneShort struct {
 	Name        string
 	WebURL      string
 	Description string
-}
-
-type ListMilestoneResult struct {
-	Data []MilestoneShort
+	// GroupID is not zero if the milestone is a group milestone.
+	GroupID int
 }
 
 type ListProjectRequest struct {
-	Page        int
+	// PerPage is the page size, default is 100.
 	PerPage     int
 	ProjectName string
+	// SearchNamespaces means ProjectName would match the namespace too,
+	// so that the full path (group/project) could be used.
+	SearchNamespaces bool
 }
 
 type ProjectShort struct {
-	ID     int
-	Name   string
-	WebURL string
+	ID                int
+	Name              string
+	PathWithNamespace string
+	WebURL            string
+	// GroupID is the ID of group which the project belongs to, it's zero if the
+	// project belongs to a user namespace.
+	GroupID int
+}
+
+// GetProjectRequest
+type GetProjectRequest struct {
+	ProjectID int
+}
+
+type ListTagRequest struct {
+	// PerPage is the page size, default is 100.
+	PerPage   int
+	ProjectID int
+	// Search filters tags whose name contains Search, ^ and $ could be used to
+	// match the beginning and the end of name.
+	Search string
+}
+
+type ListMergeRequestRequest struct {
+	// PerPage is the page size, default is 100.
+	PerPage   int
+	ProjectID int
+	// State filters merge requests by state, empty means all merge requests.
+	State MergeRequestState
+	// TargetBranch filters merge requests by target branch, empty means all branches.
+	TargetBranch string
 }
 
-type ListProjectResult struct {
-	Data []ProjectShort
+type TagShort struct {
+	Name      string
+	CommitSHA string
 }
 
 type IGitlabOauth2Support interface {
@@ -182,4 +416,8 @@ type IGitlabOauth2Support interface {
 
 	// Load only uses this after any signal from Enter channel. Blocked method.
 	Load() (accessToken, refreshToken string)
+
+	// Refresh requests new tokens by refreshToken synchronously, unlike Enter, it never falls back
+	// to authorization by browser, error is returned if refreshToken has expired.
+	Refresh(refreshToken string) (accessToken, newRefreshToken string, err error)
 }
@@ -101,6 +101,18 @@ func (g *gitlabOperatorTestSuite) Test_CreateMergeRequest() {
 	g.T().Logf("result=%+v", result)
 }
 
+func (g *gitlabOperatorTestSuite) Test_GetMergeRequest() {
+	ctx := context.Background()
+	req := GetMergeRequestRequest{
+		MergeRequestIID: 1,
+		ProjectID:       g.projectID,
+	}
+	result, err := g.op.GetMergeRequest(ctx, &req)
+	g.Nil(err)
+	g.NotNil(result)
+	g.T().Logf("result=%+v", result)
+}
+
 func Test_gitlabOperator(t *testing.T) {
 	suite.Run(t, new(gitlabOperatorTestSuite))
 }
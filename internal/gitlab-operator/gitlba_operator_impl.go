@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/samber/lo"
 	gogitlab "github.com/xanzy/go-gitlab"
 	"github.com/yeqown/log"
 )
@@ -14,6 +16,10 @@ import (
 // gitlabOperator implement IGitlabOperator to operate remote gitlab repository.
 type gitlabOperator struct {
 	gitlab *gogitlab.Client
+
+	// groupWebURLs caches web URL of groups, since group milestones need it to generate
+	// their web URL. groupID(int) => webURL(string)
+	groupWebURLs *sync.Map
 }
 
 type Config struct {
@@ -47,7 +53,8 @@ func NewGitlabOperator(accessToken, apiURL string) IGitlabOperator {
 	}
 
 	return &gitlabOperator{
-		gitlab: gitlab,
+		gitlab:       gitlab,
+		groupWebURLs: new(sync.Map),
 	}
 }
 
@@ -74,8 +81,64 @@ func (g gitlabOperator) CreateBranch(ctx context.Context, req *CreateBranchReque
 	}, nil
 }
 
-func (g gitlabOperator) CreateMilestone(ctx context.Context, req *CreateMilestoneRequest) (*CreateMilestoneResult, error) {
+func (g gitlabOperator) DeleteBranch(ctx context.Context, req *DeleteBranchRequest) error {
 	_ = ctx
+	_, err := g.gitlab.Branches.DeleteBranch(req.ProjectID, req.BranchName)
+	if err != nil {
+		return errors.Wrap(err, "delete branch failed")
+	}
+
+	return nil
+}
+
+func (g gitlabOperator) CreateTag(ctx context.Context, req *CreateTagRequest) (*CreateTagResult, error) {
+	opt := &gogitlab.CreateTagOptions{
+		TagName: &req.TagName,
+		Ref:     &req.Ref,
+	}
+	if req.Message != "" {
+		opt.Message = &req.Message
+	}
+
+	tag, _, err := g.gitlab.Tags.CreateTag(req.ProjectID, opt, gogitlab.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "create tag failed")
+	}
+
+	result := &CreateTagResult{Name: tag.Name}
+	if tag.Commit != nil {
+		result.CommitSHA = tag.Commit.ID
+	}
+
+	return result, nil
+}
+
+func (g gitlabOperator) CreateRelease(ctx context.Context, req *CreateReleaseRequest) (*CreateReleaseResult, error) {
+	opt := &gogitlab.CreateReleaseOptions{
+		Name:        &req.Name,
+		TagName:     &req.TagName,
+		Description: &req.Description,
+	}
+	if len(req.Milestones) != 0 {
+		opt.Milestones = &req.Milestones
+	}
+
+	release, _, err := g.gitlab.Releases.CreateRelease(req.ProjectID, opt, gogitlab.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "create release failed")
+	}
+
+	return &CreateReleaseResult{
+		TagName: release.TagName,
+		Name:    release.Name,
+	}, nil
+}
+
+func (g gitlabOperator) CreateMilestone(ctx context.Context, req *CreateMilestoneRequest) (*CreateMilestoneResult, error) {
+	if req.GroupID != 0 {
+		return g.createGroupMilestone(ctx, req)
+	}
+
 	opt := &gogitlab.CreateMilestoneOptions{
 		Title:       &req.Title,
 		Description: &req.Desc,
@@ -85,28 +148,31 @@ func (g gitlabOperator) CreateMilestone(ctx context.Context, req *CreateMileston
 	milestone, _, err := g.gitlab.Milestones.CreateMilestone(req.ProjectID, opt)
 	if err != nil || milestone == nil {
 		// if create failed then query from remote, if got then return
-		opt := gogitlab.ListMilestonesOptions{
-			ListOptions: gogitlab.ListOptions{
-				Page:    1,
-				PerPage: 20,
+		it := newPageIterator(ctx, 0,
+			func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.Milestone, *gogitlab.Response, error) {
+				opt := gogitlab.ListMilestonesOptions{
+					ListOptions: lo,
+					Title:       &req.Title,
+					Search:      &req.Title,
+				}
+				return g.gitlab.Milestones.ListMilestones(req.ProjectID, &opt, options...)
 			},
-			Title:  &req.Title,
-			Search: &req.Title,
-		}
-		milestones, _, err2 := g.gitlab.Milestones.ListMilestones(req.ProjectID, &opt)
-		if err2 != nil {
-			return nil, fmt.Errorf("create milestone failed: %v, query failed: %v", err, err2)
-		}
+			func(v *gogitlab.Milestone) *gogitlab.Milestone { return v },
+		)
 
 		matched := false
-		for idx, v := range milestones {
+		for !matched && it.Next() {
+			v := it.Value()
 			if strings.Compare(req.Title, v.Title) == 0 &&
 				strings.Compare(req.Desc, v.Description) == 0 {
 				//	matched
-				milestone = milestones[idx]
+				milestone = v
 				matched = true
 			}
 		}
+		if err2 := it.Err(); err2 != nil {
+			return nil, fmt.Errorf("create milestone failed: %v, query failed: %v", err, err2)
+		}
 
 		if !matched || milestone == nil {
 			return nil, fmt.Errorf("[matched: %v] create milestone failed: %v", matched, err)
@@ -120,7 +186,10 @@ func (g gitlabOperator) CreateMilestone(ctx context.Context, req *CreateMileston
 }
 
 func (g gitlabOperator) GetMilestone(ctx context.Context, req *GetMilestoneRequest) (*GetMilestoneResult, error) {
-	_ = ctx
+	if req.GroupID != 0 {
+		return g.getGroupMilestone(ctx, req)
+	}
+
 	milestone, _, err := g.gitlab.Milestones.GetMilestone(req.ProjectID, req.MilestoneID)
 	if err != nil {
 		return nil, errors.Wrap(err, "get milestone failed")
@@ -136,56 +205,87 @@ func (g gitlabOperator) GetMilestone(ctx context.Context, req *GetMilestoneReque
 
 func (g gitlabOperator) GetMilestoneMergeRequests(
 	ctx context.Context, req *GetMilestoneMergeRequestsRequest) (*GetMilestoneMergeRequestsResult, error) {
-	_ = ctx
-	opt := gogitlab.GetMilestoneMergeRequestsOptions{}
-	mrs, _, err := g.gitlab.Milestones.GetMilestoneMergeRequests(req.ProjectID, req.MilestoneID, &opt)
+	it := newPageIterator(ctx, 0,
+		func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.MergeRequest, *gogitlab.Response, error) {
+			if req.GroupID != 0 {
+				opt := gogitlab.GetGroupMilestoneMergeRequestsOptions(lo)
+				return g.gitlab.GroupMilestones.GetGroupMilestoneMergeRequests(req.GroupID, req.MilestoneID, &opt, options...)
+			}
+			opt := gogitlab.GetMilestoneMergeRequestsOptions(lo)
+			return g.gitlab.Milestones.GetMilestoneMergeRequests(req.ProjectID, req.MilestoneID, &opt, options...)
+		},
+		toMergeRequestShort,
+	)
+
+	mrs, err := Collect(it)
 	if err != nil {
 		return nil, errors.Wrap(err, "get milestone merge requests failed")
 	}
 
-	result := new(GetMilestoneMergeRequestsResult)
-	result.Data = make([]MergeRequestShort, 0, len(mrs))
-
-	for _, v := range mrs {
-		result.Data = append(result.Data, MergeRequestShort{
-			ID:           v.ID,
-			IID:          v.IID,
-			Title:        v.Title,
-			Description:  v.Description,
-			WebURL:       v.WebURL,
-			SourceBranch: v.SourceBranch,
-			TargetBranch: v.TargetBranch,
-		})
-	}
-
-	return result, nil
+	return &GetMilestoneMergeRequestsResult{Data: mrs}, nil
 }
 
 func (g gitlabOperator) GetMilestoneIssues(
 	ctx context.Context, req *GetMilestoneIssuesRequest) (*GetMilestoneIssuesResult, error) {
-	_ = ctx
-	opt := gogitlab.GetMilestoneIssuesOptions{}
-	issues, _, err := g.gitlab.Milestones.GetMilestoneIssues(req.ProjectID, req.MilestoneID, &opt)
+	it := newPageIterator(ctx, 0,
+		func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.Issue, *gogitlab.Response, error) {
+			if req.GroupID != 0 {
+				opt := gogitlab.GetGroupMilestoneIssuesOptions(lo)
+				return g.gitlab.GroupMilestones.GetGroupMilestoneIssues(req.GroupID, req.MilestoneID, &opt, options...)
+			}
+			opt := gogitlab.GetMilestoneIssuesOptions(lo)
+			return g.gitlab.Milestones.GetMilestoneIssues(req.ProjectID, req.MilestoneID, &opt, options...)
+		},
+		toIssueShort,
+	)
+
+	issues, err := Collect(it)
 	if err != nil {
 		return nil, errors.Wrap(err, "get milestone issues failed")
 	}
 
-	result := new(GetMilestoneIssuesResult)
-	result.Data = make([]IssueShort, 0, len(issues))
+	return &GetMilestoneIssuesResult{Data: issues}, nil
+}
 
-	for _, v := range issues {
-		result.Data = append(result.Data, IssueShort{
-			ID:          v.ID,
-			IID:         v.IID,
-			Title:       v.Title,
-			Description: v.Description,
-			WebURL:      v.WebURL,
-			ProjectID:   v.ProjectID,
-			MilestoneID: v.Milestone.ID,
-		})
+// toIssueShort convert gitlab issue into IssueShort.
+func toIssueShort(v *gogitlab.Issue) IssueShort {
+	short := IssueShort{
+		ID:          v.ID,
+		IID:         v.IID,
+		Title:       v.Title,
+		Description: v.Description,
+		WebURL:      v.WebURL,
+		ProjectID:   v.ProjectID,
+		Labels:      v.Labels,
+	}
+	if v.Milestone != nil {
+		short.MilestoneID = v.Milestone.ID
 	}
 
-	return result, nil
+	return short
+}
+
+func (g gitlabOperator) CloseMilestone(ctx context.Context, req *CloseMilestoneRequest) error {
+	_ = ctx
+	closeEvent := "close"
+	if req.GroupID != 0 {
+		_, _, err := g.gitlab.GroupMilestones.UpdateGroupMilestone(req.GroupID, req.MilestoneID,
+			&gogitlab.UpdateGroupMilestoneOptions{StateEvent: &closeEvent}, gogitlab.WithContext(ctx))
+		if err != nil {
+			return errors.Wrap(err, "close group milestone failed")
+		}
+		return nil
+	}
+
+	opt := &gogitlab.UpdateMilestoneOptions{
+		StateEvent: &closeEvent,
+	}
+	_, _, err := g.gitlab.Milestones.UpdateMilestone(req.ProjectID, req.MilestoneID, opt)
+	if err != nil {
+		return errors.Wrap(err, "close milestone failed")
+	}
+
+	return nil
 }
 
 func (g gitlabOperator) CreateIssue(ctx context.Context, req *CreateIssueRequest) (*CreateIssueResult, error) {
@@ -197,30 +297,39 @@ func (g gitlabOperator) CreateIssue(ctx context.Context, req *CreateIssueRequest
 		MilestoneID: &req.MilestoneID,
 		CreatedAt:   &now,
 	}
+	if len(req.AssigneeIDs) != 0 {
+		opt3.AssigneeIDs = &req.AssigneeIDs
+	}
+	if len(req.Labels) != 0 {
+		opt3.Labels = gogitlab.Ptr(gogitlab.LabelOptions(req.Labels))
+	}
 	issue, _, err := g.gitlab.Issues.CreateIssue(req.ProjectID, opt3)
 	if err != nil || issue == nil {
 		// if create failed then query from remote, if got then return
-		opt := gogitlab.ListProjectIssuesOptions{
-			ListOptions: gogitlab.ListOptions{
-				Page:    1,
-				PerPage: 20,
+		it := newPageIterator(ctx, 0,
+			func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.Issue, *gogitlab.Response, error) {
+				opt := gogitlab.ListProjectIssuesOptions{
+					ListOptions: lo,
+					Search:      &req.Title,
+				}
+				return g.gitlab.Issues.ListProjectIssues(req.ProjectID, &opt, options...)
 			},
-			Search: &req.Title,
-		}
-		issues, _, err2 := g.gitlab.Issues.ListProjectIssues(req.ProjectID, &opt)
-		if err2 != nil {
-			return nil, fmt.Errorf("create issue failed: %v, query failed: %v", err, err2)
-		}
+			func(v *gogitlab.Issue) *gogitlab.Issue { return v },
+		)
 
 		matched := false
-		for idx, v := range issues {
+		for !matched && it.Next() {
+			v := it.Value()
 			if strings.Compare(req.Title, v.Title) == 0 &&
 				strings.Compare(req.Desc, v.Description) == 0 {
 				//	matched
-				issue = issues[idx]
+				issue = v
 				matched = true
 			}
 		}
+		if err2 := it.Err(); err2 != nil {
+			return nil, fmt.Errorf("create issue failed: %v, query failed: %v", err, err2)
+		}
 
 		if !matched || issue == nil {
 			return nil, fmt.Errorf("[matched: %v] create issue failed: %v", matched, err)
@@ -235,6 +344,20 @@ func (g gitlabOperator) CreateIssue(ctx context.Context, req *CreateIssueRequest
 	}, nil
 }
 
+func (g gitlabOperator) CloseIssue(ctx context.Context, req *CloseIssueRequest) error {
+	_ = ctx
+	closeEvent := "close"
+	opt := &gogitlab.UpdateIssueOptions{
+		StateEvent: &closeEvent,
+	}
+	_, _, err := g.gitlab.Issues.UpdateIssue(req.ProjectID, req.IssueIID, opt)
+	if err != nil {
+		return errors.Wrap(err, "close issue failed")
+	}
+
+	return nil
+}
+
 func (g gitlabOperator) CreateMergeRequest(ctx context.Context, req *CreateMergeRequest) (*CreateMergeResult, error) {
 	_ = ctx
 	approvals := 1
@@ -242,129 +365,327 @@ func (g gitlabOperator) CreateMergeRequest(ctx context.Context, req *CreateMerge
 		approvals = 0
 	}
 
+	title := draftTitle(req.Title, req.Draft)
 	opt5 := &gogitlab.CreateMergeRequestOptions{
-		Title:        &req.Title,
+		Title:        &title,
 		Description:  &req.Desc,
 		MilestoneID:  &req.MilestoneID,
 		SourceBranch: &req.SrcBranch,
 		TargetBranch: &req.TargetBranch,
-		// AssigneeID:         nil,
-		// AssigneeIDs:        nil,
 		// TargetProjectID:    nil,
 		// RemoveSourceBranch: true,
 		ApprovalsBeforeMerge: &approvals,
 	}
+	if len(req.AssigneeIDs) != 0 {
+		opt5.AssigneeIDs = &req.AssigneeIDs
+	}
+	if len(req.ReviewerIDs) != 0 {
+		opt5.ReviewerIDs = &req.ReviewerIDs
+	}
+	if len(req.Labels) != 0 {
+		opt5.Labels = gogitlab.Ptr(gogitlab.LabelOptions(req.Labels))
+	}
 	mr, _, err := g.gitlab.MergeRequests.CreateMergeRequest(req.ProjectID, opt5)
 	if err != nil || mr == nil {
 		// if create failed then query from remote, if got then return
-		opt := gogitlab.ListProjectMergeRequestsOptions{
-			ListOptions: gogitlab.ListOptions{
-				Page:    1,
-				PerPage: 20,
+		it := newPageIterator(ctx, 0,
+			func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.MergeRequest, *gogitlab.Response, error) {
+				opt := gogitlab.ListProjectMergeRequestsOptions{
+					ListOptions:  lo,
+					Search:       &req.Title,
+					TargetBranch: &req.TargetBranch,
+					SourceBranch: &req.SrcBranch,
+					// IIDs:         []int{req.IssueIID},
+				}
+				return g.gitlab.MergeRequests.ListProjectMergeRequests(req.ProjectID, &opt, options...)
 			},
-			Search:       &req.Title,
-			TargetBranch: &req.TargetBranch,
-			SourceBranch: &req.SrcBranch,
-			// IIDs:         []int{req.IssueIID},
-		}
-		mergeRequests, _, err2 := g.gitlab.MergeRequests.ListProjectMergeRequests(req.ProjectID, &opt)
-		if err2 != nil {
-			return nil, fmt.Errorf("create merge request failed: %v, query failed: %v", err, err2)
-		}
+			func(v *gogitlab.MergeRequest) *gogitlab.MergeRequest { return v },
+		)
 
 		matched := false
-		for idx, v := range mergeRequests {
-			if strings.Compare(req.Title, v.Title) == 0 &&
+		for !matched && it.Next() {
+			v := it.Value()
+			if strings.Compare(title, v.Title) == 0 &&
 				strings.Compare(req.Desc, v.Description) == 0 {
 				//	matched
-				mr = mergeRequests[idx]
+				mr = v
 				matched = true
 			}
 		}
+		if err2 := it.Err(); err2 != nil {
+			return nil, fmt.Errorf("create merge request failed: %v, query failed: %v", err, err2)
+		}
 
 		if !matched || mr == nil {
 			return nil, fmt.Errorf("[matched: %v] create merge request failed: %v", matched, err)
 		}
 	}
 
-	return &CreateMergeResult{
+	result := &CreateMergeResult{
 		ID:     mr.ID,
 		IID:    mr.IID,
 		WebURL: mr.WebURL,
-	}, nil
+		State:  MergeRequestState(mr.State),
+		Draft:  mr.Draft || mr.WorkInProgress,
+	}
+	if mr.Author != nil {
+		result.Author = mr.Author.Username
+	}
+
+	return result, nil
 }
 
-func (g gitlabOperator) MergeMergeRequest(ctx context.Context, req *MergeMergeRequest) error {
+func (g gitlabOperator) MergeMergeRequest(ctx context.Context, req *MergeMergeRequest) (*MergeMergeRequestResult, error) {
 	opt := &gogitlab.AcceptMergeRequestOptions{
 		// MergeCommitMessage:        nil,
 		// SquashCommitMessage:       nil,
-		// Squash:                    nil,
-		// ShouldRemoveSourceBranch:  nil,
-		// MergeWhenPipelineSucceeds: nil,
 		// SHA:                       nil,
+		Squash:                    gogitlab.Ptr(req.Squash),
+		ShouldRemoveSourceBranch:  gogitlab.Ptr(req.ShouldRemoveSourceBranch),
+		MergeWhenPipelineSucceeds: gogitlab.Ptr(req.MergeWhenPipelineSucceeds),
 	}
 
-	_, _, err := g.gitlab.MergeRequests.AcceptMergeRequest(req.ProjectID, req.MergeRequestID, opt)
+	mr, _, err := g.gitlab.MergeRequests.AcceptMergeRequest(
+		req.ProjectID, req.MergeRequestID, opt, gogitlab.WithContext(ctx))
 	if err != nil {
-		return errors.Wrap(err, "merge merge request failed")
+		return nil, errors.Wrap(err, "merge merge request failed")
 	}
 
-	return nil
+	return &MergeMergeRequestResult{
+		State:                     MergeRequestState(mr.State),
+		MergeWhenPipelineSucceeds: mr.MergeWhenPipelineSucceeds,
+		MergedAt:                  mr.MergedAt,
+	}, nil
 }
 
-func (g gitlabOperator) ListMilestones(ctx context.Context, req *ListMilestoneRequest) (*ListMilestoneResult, error) {
+func (g gitlabOperator) GetMergeRequest(ctx context.Context, req *GetMergeRequestRequest) (*GetMergeRequestResult, error) {
 	_ = ctx
-	var active = "active"
-	ms, _, err := g.gitlab.Milestones.ListMilestones(req.ProjectID, &gogitlab.ListMilestonesOptions{
-		ListOptions: gogitlab.ListOptions{
-			Page:    req.Page,
-			PerPage: req.PerPage,
-		},
-		// Search: nil,
-		// IIDs:   nil,
-		// Title:  nil,
-		State: &active,
-	})
+	mr, _, err := g.gitlab.MergeRequests.GetMergeRequest(req.ProjectID, req.MergeRequestIID, nil)
 	if err != nil {
-		return nil, errors.Wrap(err, "query milestones failed")
+		return nil, errors.Wrap(err, "get merge request failed")
 	}
 
-	result := new(ListMilestoneResult)
-	result.Data = make([]MilestoneShort, 0, len(ms))
-	for _, v := range ms {
-		result.Data = append(result.Data, MilestoneShort{
-			ID:   v.ID,
-			IID:  v.IID,
-			Name: v.Title,
-		})
+	return &GetMergeRequestResult{
+		MergeRequestShort: toMergeRequestShort(mr),
+	}, nil
+}
+
+func (g gitlabOperator) UpdateMergeRequest(
+	ctx context.Context, req *UpdateMergeRequestRequest) (*UpdateMergeRequestResult, error) {
+	opt := &gogitlab.UpdateMergeRequestOptions{}
+	if req.Title != "" {
+		opt.Title = &req.Title
+	}
+	if req.Desc != "" {
+		opt.Description = &req.Desc
+	}
+	if req.Draft != nil {
+		// draft state is represented by the title prefix, so current title is needed
+		// if the title is not updated.
+		title := req.Title
+		if title == "" {
+			mr, _, err := g.gitlab.MergeRequests.GetMergeRequest(
+				req.ProjectID, req.MergeRequestIID, nil, gogitlab.WithContext(ctx))
+			if err != nil {
+				return nil, errors.Wrap(err, "get merge request failed")
+			}
+			title = mr.Title
+		}
+		title = draftTitle(title, *req.Draft)
+		opt.Title = &title
 	}
 
-	return result, nil
+	mr, _, err := g.gitlab.MergeRequests.UpdateMergeRequest(
+		req.ProjectID, req.MergeRequestIID, opt, gogitlab.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "update merge request failed")
+	}
+
+	return &UpdateMergeRequestResult{
+		Title:  mr.Title,
+		WebURL: mr.WebURL,
+		Draft:  mr.Draft || mr.WorkInProgress,
+	}, nil
 }
 
-func (g gitlabOperator) ListProjects(ctx context.Context, req *ListProjectRequest) (*ListProjectResult, error) {
-	_ = ctx
-	opt := &gogitlab.ListProjectsOptions{
-		ListOptions: gogitlab.ListOptions{
-			Page:    req.Page,
-			PerPage: req.PerPage,
-		},
-		Search: &req.ProjectName,
+// _draftTitlePrefixes are title prefixes those mark merge request as draft in gitlab,
+// the first one is used to mark draft.
+var _draftTitlePrefixes = []string{"Draft: ", "Draft:", "[Draft]", "(Draft)", "WIP: ", "WIP:", "[WIP]"}
+
+// draftTitle adds the draft prefix into title if draft is true, otherwise removes draft prefixes.
+func draftTitle(title string, draft bool) string {
+	title = strings.TrimSpace(title)
+	for trimmed := true; trimmed; {
+		trimmed = false
+		for _, prefix := range _draftTitlePrefixes {
+			if len(title) >= len(prefix) && strings.EqualFold(title[:len(prefix)], prefix) {
+				title = strings.TrimSpace(title[len(prefix):])
+				trimmed = true
+			}
+		}
+	}
+
+	if draft {
+		return _draftTitlePrefixes[0] + title
+	}
+
+	return title
+}
+
+// toMergeRequestShort convert gitlab merge request into MergeRequestShort.
+func toMergeRequestShort(mr *gogitlab.MergeRequest) MergeRequestShort {
+	short := MergeRequestShort{
+		ID:           mr.ID,
+		IID:          mr.IID,
+		ProjectID:    mr.ProjectID,
+		Title:        mr.Title,
+		Description:  mr.Description,
+		WebURL:       mr.WebURL,
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		State:        MergeRequestState(mr.State),
+		CreatedAt:    mr.CreatedAt,
+		MergedAt:     mr.MergedAt,
+		Draft:        mr.Draft || mr.WorkInProgress,
+
+		HasConflicts:        mr.HasConflicts,
+		DetailedMergeStatus: mr.DetailedMergeStatus,
+	}
+	// DetailedMergeStatus is introduced in gitlab 15.6, use the deprecated MergeStatus instead
+	// if the server is older.
+	if short.DetailedMergeStatus == "" {
+		short.DetailedMergeStatus = mr.MergeStatus
 	}
-	projects, _, err := g.gitlab.Projects.ListProjects(opt)
+
+	if mr.State == string(MergeRequestStateMerged) {
+		short.MergeCommitSHA = lo.CoalesceOrEmpty(mr.MergeCommitSHA, mr.SquashCommitSHA, mr.SHA)
+	}
+
+	if mr.Author != nil {
+		short.Author = mr.Author.Username
+	}
+	// list APIs do not return pipeline information, only single merge request API does.
+	if mr.HeadPipeline != nil {
+		short.PipelineStatus = mr.HeadPipeline.Status
+	} else if mr.Pipeline != nil {
+		short.PipelineStatus = mr.Pipeline.Status
+	}
+
+	return short
+}
+
+func (g gitlabOperator) ListMilestones(ctx context.Context, req *ListMilestoneRequest) Iterator[MilestoneShort] {
+	state := req.State
+	if state == "" {
+		state = MilestoneStateActive
+	}
+
+	return newPageIterator(ctx, req.PerPage,
+		func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.Milestone, *gogitlab.Response, error) {
+			opt := &gogitlab.ListMilestonesOptions{
+				ListOptions: lo,
+			}
+			if state != MilestoneStateAll {
+				opt.State = &state
+			}
+			if req.IncludeParentMilestones {
+				opt.IncludeParentMilestones = &req.IncludeParentMilestones
+			}
+			if req.Search != "" {
+				opt.Search = &req.Search
+			}
+			return g.gitlab.Milestones.ListMilestones(req.ProjectID, opt, options...)
+		},
+		func(v *gogitlab.Milestone) MilestoneShort {
+			return MilestoneShort{
+				ID:          v.ID,
+				IID:         v.IID,
+				Name:        v.Title,
+				WebURL:      v.WebURL,
+				Description: v.Description,
+				GroupID:     v.GroupID,
+			}
+		},
+	)
+}
+
+func (g gitlabOperator) ListProjects(ctx context.Context, req *ListProjectRequest) Iterator[ProjectShort] {
+	return newPageIterator(ctx, req.PerPage,
+		func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.Project, *gogitlab.Response, error) {
+			opt := &gogitlab.ListProjectsOptions{
+				ListOptions: lo,
+				Search:      &req.ProjectName,
+			}
+			if req.SearchNamespaces {
+				opt.SearchNamespaces = &req.SearchNamespaces
+			}
+			return g.gitlab.Projects.ListProjects(opt, options...)
+		},
+		toProjectShort,
+	)
+}
+
+func (g gitlabOperator) GetProject(ctx context.Context, req *GetProjectRequest) (*ProjectShort, error) {
+	project, _, err := g.gitlab.Projects.GetProject(req.ProjectID, nil, gogitlab.WithContext(ctx))
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrap(err, "get project failed")
 	}
 
-	result := new(ListProjectResult)
-	result.Data = make([]ProjectShort, 0, len(projects))
-	for _, v := range projects {
-		result.Data = append(result.Data, ProjectShort{
-			ID:     v.ID,
-			Name:   v.Name,
-			WebURL: v.WebURL,
-		})
+	short := toProjectShort(project)
+	return &short, nil
+}
+
+// toProjectShort convert gitlab project into ProjectShort.
+func toProjectShort(v *gogitlab.Project) ProjectShort {
+	short := ProjectShort{
+		ID:                v.ID,
+		Name:              v.Name,
+		PathWithNamespace: v.PathWithNamespace,
+		WebURL:            v.WebURL,
+	}
+	if v.Namespace != nil && v.Namespace.Kind == "group" {
+		short.GroupID = v.Namespace.ID
 	}
 
-	return result, nil
+	return short
+}
+
+func (g gitlabOperator) ListTags(ctx context.Context, req *ListTagRequest) Iterator[TagShort] {
+	return newPageIterator(ctx, req.PerPage,
+		func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.Tag, *gogitlab.Response, error) {
+			opt := &gogitlab.ListTagsOptions{
+				ListOptions: lo,
+			}
+			if req.Search != "" {
+				opt.Search = &req.Search
+			}
+			return g.gitlab.Tags.ListTags(req.ProjectID, opt, options...)
+		},
+		func(v *gogitlab.Tag) TagShort {
+			tag := TagShort{Name: v.Name}
+			if v.Commit != nil {
+				tag.CommitSHA = v.Commit.ID
+			}
+			return tag
+		},
+	)
+}
+
+func (g gitlabOperator) ListMergeRequests(
+	ctx context.Context, req *ListMergeRequestRequest) Iterator[MergeRequestShort] {
+	return newPageIterator(ctx, req.PerPage,
+		func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.MergeRequest, *gogitlab.Response, error) {
+			opt := &gogitlab.ListProjectMergeRequestsOptions{
+				ListOptions: lo,
+			}
+			if req.State != "" {
+				opt.State = gogitlab.Ptr(string(req.State))
+			}
+			if req.TargetBranch != "" {
+				opt.TargetBranch = &req.TargetBranch
+			}
+			return g.gitlab.MergeRequests.ListProjectMergeRequests(req.ProjectID, opt, options...)
+		},
+		toMergeRequestShort,
+	)
 }
@@ -0,0 +1,27 @@
+package gitlabop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_draftTitle(t *testing.T) {
+	cases := []struct {
+		title string
+		draft bool
+		want  string
+	}{
+		{title: "Merge issue/login-1 into feature/login", draft: true, want: "Draft: Merge issue/login-1 into feature/login"},
+		{title: "Draft: Merge a into b", draft: true, want: "Draft: Merge a into b"},
+		{title: "Draft: Merge a into b", draft: false, want: "Merge a into b"},
+		{title: "WIP: Merge a into b", draft: false, want: "Merge a into b"},
+		{title: "[Draft] WIP: Merge a into b", draft: false, want: "Merge a into b"},
+		{title: "draft:Merge a into b", draft: false, want: "Merge a into b"},
+		{title: "Drafting plan", draft: false, want: "Drafting plan"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, draftTitle(c.title, c.draft), c.title)
+	}
+}
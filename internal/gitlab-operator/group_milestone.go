@@ -0,0 +1,97 @@
+package gitlabop
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	gogitlab "github.com/xanzy/go-gitlab"
+)
+
+// createGroupMilestone creates a milestone of group, the existing one with the same title and
+// description would be returned if creating failed, just like the project milestone.
+func (g gitlabOperator) createGroupMilestone(
+	ctx context.Context, req *CreateMilestoneRequest) (*CreateMilestoneResult, error) {
+	opt := &gogitlab.CreateGroupMilestoneOptions{
+		Title:       &req.Title,
+		Description: &req.Desc,
+	}
+	milestone, _, err := g.gitlab.GroupMilestones.CreateGroupMilestone(req.GroupID, opt, gogitlab.WithContext(ctx))
+	if err != nil || milestone == nil {
+		it := newPageIterator(ctx, 0,
+			func(lo gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]*gogitlab.GroupMilestone, *gogitlab.Response, error) {
+				opt := gogitlab.ListGroupMilestonesOptions{
+					ListOptions: lo,
+					Title:       &req.Title,
+				}
+				return g.gitlab.GroupMilestones.ListGroupMilestones(req.GroupID, &opt, options...)
+			},
+			func(v *gogitlab.GroupMilestone) *gogitlab.GroupMilestone { return v },
+		)
+
+		matched := false
+		for !matched && it.Next() {
+			v := it.Value()
+			if strings.Compare(req.Title, v.Title) == 0 &&
+				strings.Compare(req.Desc, v.Description) == 0 {
+				milestone = v
+				matched = true
+			}
+		}
+		if err2 := it.Err(); err2 != nil {
+			return nil, fmt.Errorf("create group milestone failed: %v, query failed: %v", err, err2)
+		}
+
+		if !matched || milestone == nil {
+			return nil, fmt.Errorf("[matched: %v] create group milestone failed: %v", matched, err)
+		}
+	}
+
+	return &CreateMilestoneResult{
+		ID:     milestone.ID,
+		WebURL: g.groupMilestoneWebURL(ctx, milestone),
+	}, nil
+}
+
+func (g gitlabOperator) getGroupMilestone(ctx context.Context, req *GetMilestoneRequest) (*GetMilestoneResult, error) {
+	milestone, _, err := g.gitlab.GroupMilestones.GetGroupMilestone(req.GroupID, req.MilestoneID, gogitlab.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "get group milestone failed")
+	}
+
+	return &GetMilestoneResult{
+		ID:          milestone.ID,
+		Title:       milestone.Title,
+		Description: milestone.Description,
+		WebURL:      g.groupMilestoneWebURL(ctx, milestone),
+		GroupID:     milestone.GroupID,
+	}, nil
+}
+
+// groupMilestoneWebURL generates web URL of the group milestone, since gitlab API does not
+// return it. Empty string would be returned if the group could not be found.
+func (g gitlabOperator) groupMilestoneWebURL(ctx context.Context, milestone *gogitlab.GroupMilestone) string {
+	groupWebURL, ok := g.groupWebURL(ctx, milestone.GroupID)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/-/milestones/%d", groupWebURL, milestone.IID)
+}
+
+// groupWebURL returns web URL of the group, the group is queried only once.
+func (g gitlabOperator) groupWebURL(ctx context.Context, groupID int) (string, bool) {
+	if v, ok := g.groupWebURLs.Load(groupID); ok {
+		return v.(string), true
+	}
+
+	opt := &gogitlab.GetGroupOptions{WithProjects: gogitlab.Ptr(false)}
+	group, _, err := g.gitlab.Groups.GetGroup(groupID, opt, gogitlab.WithContext(ctx))
+	if err != nil {
+		return "", false
+	}
+	g.groupWebURLs.Store(groupID, group.WebURL)
+
+	return group.WebURL, true
+}
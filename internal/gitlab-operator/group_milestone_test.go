@@ -0,0 +1,85 @@
+package gitlabop
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gogitlab "github.com/xanzy/go-gitlab"
+)
+
+// newTestGitlabOperator creates a gitlabOperator which requests the given handler.
+func newTestGitlabOperator(t *testing.T, handler http.Handler) *gitlabOperator {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client, err := gogitlab.NewOAuthClient("token", gogitlab.WithBaseURL(srv.URL+"/api/v4"))
+	require.NoError(t, err)
+
+	return &gitlabOperator{gitlab: client, groupWebURLs: new(sync.Map)}
+}
+
+func Test_gitlabOperator_GetMilestone_group(t *testing.T) {
+	groupRequested := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/groups/7/milestones/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		_, _ = fmt.Sscanf(r.URL.Path, "/api/v4/groups/7/milestones/%d", &id)
+		_, _ = fmt.Fprintf(w, `{"id":%d,"iid":%d,"group_id":7,"title":"v1.0.0","description":"desc"}`, id, id-100)
+	})
+	mux.HandleFunc("/api/v4/groups/7", func(w http.ResponseWriter, r *http.Request) {
+		groupRequested++
+		_, _ = fmt.Fprint(w, `{"id":7,"web_url":"https://gitlab.example.com/groups/team"}`)
+	})
+	g := newTestGitlabOperator(t, mux)
+
+	for _, id := range []int{101, 102} {
+		result, err := g.GetMilestone(context.Background(), &GetMilestoneRequest{
+			ProjectID:   1,
+			MilestoneID: id,
+			GroupID:     7,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, id, result.ID)
+		assert.Equal(t, 7, result.GroupID)
+		assert.Equal(t, "v1.0.0", result.Title)
+		assert.Equal(t, fmt.Sprintf("https://gitlab.example.com/groups/team/-/milestones/%d", id-100), result.WebURL)
+	}
+	assert.Equal(t, 1, groupRequested, "group should be requested only once")
+}
+
+func Test_gitlabOperator_GetMilestoneIssues_group(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/groups/7/milestones/101/issues", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id":1,"iid":1,"project_id":2,"title":"a"},{"id":2,"iid":1,"project_id":3,"title":"b"}]`)
+	})
+	mux.HandleFunc("/api/v4/groups/7/milestones/101/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id":3,"iid":5,"project_id":3,"source_branch":"issue/a-1","target_branch":"feature/a"}]`)
+	})
+	g := newTestGitlabOperator(t, mux)
+
+	issues, err := g.GetMilestoneIssues(context.Background(), &GetMilestoneIssuesRequest{
+		ProjectID:   2,
+		MilestoneID: 101,
+		GroupID:     7,
+	})
+	require.NoError(t, err)
+	require.Len(t, issues.Data, 2)
+	assert.Equal(t, 2, issues.Data[0].ProjectID)
+	assert.Equal(t, 3, issues.Data[1].ProjectID)
+
+	mrs, err := g.GetMilestoneMergeRequests(context.Background(), &GetMilestoneMergeRequestsRequest{
+		ProjectID:   2,
+		MilestoneID: 101,
+		GroupID:     7,
+	})
+	require.NoError(t, err)
+	require.Len(t, mrs.Data, 1)
+	assert.Equal(t, 3, mrs.Data[0].ProjectID)
+	assert.Equal(t, 5, mrs.Data[0].IID)
+}
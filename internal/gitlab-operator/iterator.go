@@ -0,0 +1,161 @@
+package gitlabop
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	gogitlab "github.com/xanzy/go-gitlab"
+)
+
+// _defaultPerPage is the default page size of gitlab list APIs, 100 is the max value gitlab allows.
+const _defaultPerPage = 100
+
+// Iterator walks through all items of a gitlab list API page by page. The next page is
+// only requested while the current page has been drained, so that caller could stop early.
+//
+//	it := operator.ListMilestones(ctx, req)
+//	for it.Next() {
+//		milestone := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type Iterator[T any] interface {
+	// Next prepares the next item, it returns false if there is no more item or any error occurred.
+	Next() bool
+	// Value returns the current item, it should be called after Next returns true.
+	Value() T
+	// Err returns the error occurred while iterating.
+	Err() error
+}
+
+// Collect drains the iterator and returns all items.
+func Collect[T any](it Iterator[T]) ([]T, error) {
+	out := make([]T, 0, 16)
+	for it.Next() {
+		out = append(out, it.Value())
+	}
+
+	return out, it.Err()
+}
+
+// ErrTooManyItems means the iterator has more items than the limit of CollectN.
+var ErrTooManyItems = errors.New("too many items")
+
+// CollectN drains at most n items of the iterator. If there are more than n items, the first n items
+// are returned with ErrTooManyItems, so that caller could ask for a more specific query rather than
+// requesting all pages.
+func CollectN[T any](it Iterator[T], n int) ([]T, error) {
+	out := make([]T, 0, 16)
+	for it.Next() {
+		if len(out) == n {
+			return out, errors.Wrapf(ErrTooManyItems, "more than %d", n)
+		}
+		out = append(out, it.Value())
+	}
+
+	return out, it.Err()
+}
+
+// pageFetcher requests one page from gitlab. opt is used by offset-based pagination,
+// and options carries the keyset-based pagination parameters if the previous response has next link.
+type pageFetcher[R any] func(opt gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]R, *gogitlab.Response, error)
+
+// pageIterator implements Iterator, it follows `X-Next-Page` header for offset-based pagination,
+// and `Link: <...>; rel="next"` header for keyset-based pagination.
+type pageIterator[R, T any] struct {
+	ctx     context.Context
+	fetch   pageFetcher[R]
+	convert func(R) T
+	perPage int
+
+	items   []R
+	idx     int
+	current T
+
+	nextPage int
+	nextLink string
+	done     bool
+	err      error
+}
+
+// newPageIterator creates an Iterator starts from the first page. perPage would be _defaultPerPage
+// if it is not positive.
+func newPageIterator[R, T any](
+	ctx context.Context, perPage int, fetch pageFetcher[R], convert func(R) T) Iterator[T] {
+	if perPage <= 0 {
+		perPage = _defaultPerPage
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return &pageIterator[R, T]{
+		ctx:      ctx,
+		fetch:    fetch,
+		convert:  convert,
+		perPage:  perPage,
+		nextPage: 1,
+	}
+}
+
+func (it *pageIterator[R, T]) Next() bool {
+	for it.idx >= len(it.items) {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		it.fetchNextPage()
+	}
+
+	it.current = it.convert(it.items[it.idx])
+	it.idx++
+	return true
+}
+
+func (it *pageIterator[R, T]) Value() T {
+	return it.current
+}
+
+func (it *pageIterator[R, T]) Err() error {
+	return it.err
+}
+
+func (it *pageIterator[R, T]) fetchNextPage() {
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return
+	}
+
+	opt := gogitlab.ListOptions{PerPage: it.perPage}
+	options := []gogitlab.RequestOptionFunc{gogitlab.WithContext(it.ctx)}
+	if it.nextLink != "" {
+		options = append(options, gogitlab.WithKeysetPaginationParameters(it.nextLink))
+	} else {
+		opt.Page = it.nextPage
+	}
+
+	items, resp, err := it.fetch(opt, options...)
+	if err != nil {
+		if it.nextLink != "" {
+			it.err = errors.Wrapf(err, "fetch page(%s) failed", it.nextLink)
+		} else {
+			it.err = errors.Wrapf(err, "fetch page(%d) failed", it.nextPage)
+		}
+		return
+	}
+
+	it.items = items
+	it.idx = 0
+
+	switch {
+	case len(items) == 0 || resp == nil:
+		it.done = true
+	case resp.NextLink != "":
+		it.nextLink = resp.NextLink
+	case resp.NextPage > 0:
+		it.nextPage = resp.NextPage
+	default:
+		it.done = true
+	}
+}
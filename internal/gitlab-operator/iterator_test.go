@@ -0,0 +1,110 @@
+package gitlabop
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	gogitlab "github.com/xanzy/go-gitlab"
+)
+
+func Test_pageIterator_offset(t *testing.T) {
+	pages := [][]int{{1, 2}, {3, 4}, {5}}
+	requested := make([]int, 0, 3)
+
+	fetch := func(opt gogitlab.ListOptions, _ ...gogitlab.RequestOptionFunc) ([]int, *gogitlab.Response, error) {
+		requested = append(requested, opt.Page)
+		resp := &gogitlab.Response{}
+		if opt.Page < len(pages) {
+			resp.NextPage = opt.Page + 1
+		}
+		return pages[opt.Page-1], resp, nil
+	}
+
+	it := newPageIterator(context.Background(), 2, fetch, func(v int) int { return v * 10 })
+	got, err := Collect(it)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{10, 20, 30, 40, 50}, got)
+	assert.Equal(t, []int{1, 2, 3}, requested)
+}
+
+func Test_pageIterator_keyset(t *testing.T) {
+	calls := 0
+	fetch := func(opt gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]string, *gogitlab.Response, error) {
+		calls++
+		if calls == 1 {
+			assert.Equal(t, 1, opt.Page)
+			return []string{"a"}, &gogitlab.Response{NextLink: "https://gitlab.example.com/api/v4/projects?id_after=1"}, nil
+		}
+
+		// keyset pagination parameters are passed by options, so page should not be set.
+		assert.Equal(t, 0, opt.Page)
+		assert.Len(t, options, 2)
+		return []string{"b"}, &gogitlab.Response{}, nil
+	}
+
+	got, err := Collect(newPageIterator(context.Background(), 0, fetch, func(v string) string { return v }))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, got)
+	assert.Equal(t, 2, calls)
+}
+
+func Test_pageIterator_stopEarly(t *testing.T) {
+	calls := 0
+	fetch := func(opt gogitlab.ListOptions, _ ...gogitlab.RequestOptionFunc) ([]int, *gogitlab.Response, error) {
+		calls++
+		return []int{opt.Page}, &gogitlab.Response{NextPage: opt.Page + 1}, nil
+	}
+
+	it := newPageIterator(context.Background(), 1, fetch, func(v int) int { return v })
+	for it.Next() {
+		if it.Value() == 2 {
+			break
+		}
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 2, calls)
+}
+
+func Test_pageIterator_error(t *testing.T) {
+	fetch := func(opt gogitlab.ListOptions, _ ...gogitlab.RequestOptionFunc) ([]int, *gogitlab.Response, error) {
+		if opt.Page == 2 {
+			return nil, nil, errors.New("boom")
+		}
+		return []int{1}, &gogitlab.Response{NextPage: 2}, nil
+	}
+
+	got, err := Collect(newPageIterator(context.Background(), 1, fetch, func(v int) int { return v }))
+	assert.EqualError(t, err, "fetch page(2) failed: boom")
+	assert.Equal(t, []int{1}, got)
+
+	nextLink := "https://gitlab.example.com/api/v4/projects?id_after=1"
+	fetch = func(_ gogitlab.ListOptions, options ...gogitlab.RequestOptionFunc) ([]int, *gogitlab.Response, error) {
+		if len(options) == 2 {
+			return nil, nil, errors.New("boom")
+		}
+		return []int{1}, &gogitlab.Response{NextLink: nextLink}, nil
+	}
+
+	_, err = Collect(newPageIterator(context.Background(), 1, fetch, func(v int) int { return v }))
+	assert.EqualError(t, err, "fetch page("+nextLink+") failed: boom")
+}
+
+func Test_CollectN(t *testing.T) {
+	calls := 0
+	fetch := func(opt gogitlab.ListOptions, _ ...gogitlab.RequestOptionFunc) ([]int, *gogitlab.Response, error) {
+		calls++
+		return []int{opt.Page*2 - 1, opt.Page * 2}, &gogitlab.Response{NextPage: opt.Page + 1}, nil
+	}
+
+	got, err := CollectN(newPageIterator(context.Background(), 2, fetch, func(v int) int { return v }), 3)
+	assert.True(t, errors.Is(err, ErrTooManyItems))
+	assert.Equal(t, []int{1, 2, 3}, got)
+	assert.Equal(t, 2, calls)
+
+	got, err = CollectN(newPageIterator(context.Background(), 2, fetch, func(v int) int { return v }), 4)
+	assert.True(t, errors.Is(err, ErrTooManyItems))
+	assert.Equal(t, []int{1, 2, 3, 4}, got)
+	assert.Equal(t, 5, calls)
+}
@@ -0,0 +1,109 @@
+package gitlabop
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	gogitlab "github.com/xanzy/go-gitlab"
+)
+
+// ErrPipelineNotFound means there is no pipeline of the branch or merge request.
+var ErrPipelineNotFound = errors.New("pipeline not found")
+
+// Pipeline statuses those are same as gitlab.
+const (
+	PipelineStatusCreated  = "created"
+	PipelineStatusPending  = "pending"
+	PipelineStatusRunning  = "running"
+	PipelineStatusSuccess  = "success"
+	PipelineStatusFailed   = "failed"
+	PipelineStatusCanceled = "canceled"
+	PipelineStatusSkipped  = "skipped"
+	PipelineStatusManual   = "manual"
+)
+
+// GetLatestPipelineRequest gets the latest pipeline of merge request if MergeRequestIID is not
+// zero, otherwise the latest pipeline of Ref.
+type GetLatestPipelineRequest struct {
+	ProjectID       int
+	Ref             string
+	MergeRequestIID int
+}
+
+type PipelineShort struct {
+	ID        int
+	ProjectID int
+	Status    string
+	Source    string
+	Ref       string
+	SHA       string
+	WebURL    string
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+// Finished returns true if the pipeline would not run anymore without manual actions.
+func (p PipelineShort) Finished() bool {
+	switch p.Status {
+	case PipelineStatusSuccess, PipelineStatusFailed, PipelineStatusCanceled,
+		PipelineStatusSkipped, PipelineStatusManual:
+		return true
+	}
+
+	return false
+}
+
+// Failed returns true if the pipeline has failed or been canceled.
+func (p PipelineShort) Failed() bool {
+	return p.Status == PipelineStatusFailed || p.Status == PipelineStatusCanceled
+}
+
+func (g gitlabOperator) GetLatestPipeline(ctx context.Context, req *GetLatestPipelineRequest) (*PipelineShort, error) {
+	if req.MergeRequestIID != 0 {
+		// pipelines of merge request are ordered by ID descending, so the first one is the latest.
+		pipelines, _, err := g.gitlab.MergeRequests.ListMergeRequestPipelines(
+			req.ProjectID, req.MergeRequestIID, gogitlab.WithContext(ctx))
+		if err != nil {
+			return nil, errors.Wrap(err, "list merge request pipelines failed")
+		}
+		if len(pipelines) == 0 {
+			return nil, ErrPipelineNotFound
+		}
+
+		v := pipelines[0]
+		return &PipelineShort{
+			ID:        v.ID,
+			ProjectID: v.ProjectID,
+			Status:    v.Status,
+			Source:    v.Source,
+			Ref:       v.Ref,
+			SHA:       v.SHA,
+			WebURL:    v.WebURL,
+			CreatedAt: v.CreatedAt,
+			UpdatedAt: v.UpdatedAt,
+		}, nil
+	}
+
+	opt := &gogitlab.GetLatestPipelineOptions{Ref: &req.Ref}
+	v, resp, err := g.gitlab.Pipelines.GetLatestPipeline(req.ProjectID, opt, gogitlab.WithContext(ctx))
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, ErrPipelineNotFound
+		}
+		return nil, errors.Wrap(err, "get latest pipeline failed")
+	}
+
+	return &PipelineShort{
+		ID:        v.ID,
+		ProjectID: v.ProjectID,
+		Status:    v.Status,
+		Source:    v.Source,
+		Ref:       v.Ref,
+		SHA:       v.SHA,
+		WebURL:    v.WebURL,
+		CreatedAt: v.CreatedAt,
+		UpdatedAt: v.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,30 @@
+package gitlabop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PipelineShort_status(t *testing.T) {
+	cases := []struct {
+		status   string
+		finished bool
+		failed   bool
+	}{
+		{status: PipelineStatusCreated},
+		{status: PipelineStatusPending},
+		{status: PipelineStatusRunning},
+		{status: PipelineStatusSuccess, finished: true},
+		{status: PipelineStatusFailed, finished: true, failed: true},
+		{status: PipelineStatusCanceled, finished: true, failed: true},
+		{status: PipelineStatusSkipped, finished: true},
+		{status: PipelineStatusManual, finished: true},
+	}
+
+	for _, c := range cases {
+		p := PipelineShort{Status: c.status}
+		assert.Equal(t, c.finished, p.Finished(), c.status)
+		assert.Equal(t, c.failed, p.Failed(), c.status)
+	}
+}
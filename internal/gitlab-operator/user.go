@@ -0,0 +1,45 @@
+package gitlabop
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	gogitlab "github.com/xanzy/go-gitlab"
+)
+
+// ErrUserNotFound means there is no user of the username.
+var ErrUserNotFound = errors.New("user not found")
+
+// GetUserRequest gets the user of Username, the current user would be returned
+// if Username is empty.
+type GetUserRequest struct {
+	Username string
+}
+
+type UserShort struct {
+	ID       int
+	Username string
+	Name     string
+}
+
+func (g gitlabOperator) GetUser(ctx context.Context, req *GetUserRequest) (*UserShort, error) {
+	if req.Username == "" {
+		user, _, err := g.gitlab.Users.CurrentUser(gogitlab.WithContext(ctx))
+		if err != nil {
+			return nil, errors.Wrap(err, "get current user failed")
+		}
+
+		return &UserShort{ID: user.ID, Username: user.Username, Name: user.Name}, nil
+	}
+
+	opt := &gogitlab.ListUsersOptions{Username: &req.Username}
+	users, _, err := g.gitlab.Users.ListUsers(opt, gogitlab.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "get user(%s) failed", req.Username)
+	}
+	if len(users) == 0 {
+		return nil, errors.Wrapf(ErrUserNotFound, "username=%s", req.Username)
+	}
+
+	return &UserShort{ID: users[0].ID, Username: users[0].Username, Name: users[0].Name}, nil
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yeqown/log"
+
+	gitlabop "github.com/yeqown/gitlab-flow/internal/gitlab-operator"
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+// flowStepOf tells which flow step creates the issue or merge request, srcBranch is the related
+// branch of issue, and targetBranch is empty for issue.
+func flowStepOf(srcBranch, targetBranch string) types.FlowStep {
+	switch {
+	case strings.HasPrefix(srcBranch, types.HotfixBranchPrefix):
+		return types.FlowStepHotfix
+	case targetBranch == "" || strings.HasPrefix(srcBranch, types.IssueBranchPrefix):
+		return types.FlowStepIssue
+	case targetBranch == types.DevBranch.String():
+		return types.FlowStepDebug
+	case targetBranch == types.TestBranch.String():
+		return types.FlowStepTest
+	case targetBranch == types.MasterBranch.String():
+		return types.FlowStepRelease
+	}
+
+	return ""
+}
+
+// participants are resolved Participants, usernames are kept to be saved in local.
+type participants struct {
+	assigneeIDs, reviewerIDs []int
+	assignees, reviewers     []string
+	labels                   []string
+}
+
+// resolveParticipants resolves usernames of configured participants of step into user IDs,
+// types.MeUsername is resolved into the current user. Users could not be resolved are skipped
+// with warnings, since the issue or merge request should be created anyway.
+func (f flowImpl) resolveParticipants(ctx context.Context, step types.FlowStep) *participants {
+	p := f.ctx.Config().Participants.Of(step)
+	if p == nil {
+		return &participants{}
+	}
+
+	users := make(map[string]*gitlabop.UserShort, len(p.Assignees)+len(p.Reviewers))
+	resolve := func(usernames []string) (ids []int, resolved []string) {
+		for _, username := range usernames {
+			username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+			if username == "" {
+				continue
+			}
+
+			user, ok := users[username]
+			if !ok {
+				req := &gitlabop.GetUserRequest{Username: username}
+				if "@"+username == types.MeUsername {
+					req.Username = ""
+				}
+				var err error
+				if user, err = f.gitlabOperator.GetUser(ctx, req); err != nil {
+					log.
+						WithFields(log.Fields{"username": username, "step": step}).
+						Warnf("could not resolve user, skipped: %v", err)
+				}
+				users[username] = user
+			}
+			if user == nil {
+				continue
+			}
+
+			ids = append(ids, user.ID)
+			resolved = append(resolved, user.Username)
+		}
+
+		return ids, resolved
+	}
+
+	resolved := &participants{labels: p.Labels}
+	resolved.assigneeIDs, resolved.assignees = resolve(p.Assignees)
+	resolved.reviewerIDs, resolved.reviewers = resolve(p.Reviewers)
+
+	return resolved
+}
@@ -0,0 +1,249 @@
+// Package render renders view models into table, json, yaml or markdown, so that
+// the output of commands could be read by human or consumed by scripts.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the output format of view models.
+type Format string
+
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatYAML     Format = "yaml"
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats contains all supported formats.
+var Formats = []Format{FormatTable, FormatJSON, FormatYAML, FormatMarkdown}
+
+// ParseFormat parses s into Format, empty string means FormatTable.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return FormatTable, nil
+	}
+
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+
+	return "", errors.Errorf("unknown output format: %s, must be one of (table, json, yaml, markdown)", s)
+}
+
+// Field is a key-value pair displayed before the table of section.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Section is a part of view model in table or markdown format, it contains fields and a table,
+// both of them are optional.
+type Section struct {
+	Title  string
+	Fields []Field
+	Header []string
+	Rows   [][]string
+
+	// RowColors returns colors of cells in the row, ColumnColors, MergeCells and RowLine are
+	// styles of table. They are only used in table format.
+	RowColors    func(row []string) []tablewriter.Colors
+	ColumnColors []tablewriter.Colors
+	MergeCells   bool
+	RowLine      bool
+}
+
+// Tabular is implemented by view models which could be rendered in table or markdown format.
+type Tabular interface {
+	Sections() []*Section
+}
+
+// Markdowner is implemented by view models which are rendered as markdown by themselves, such as
+// changelog. They are rendered by Markdown in both table and markdown format.
+type Markdowner interface {
+	Markdown() ([]byte, error)
+}
+
+// Renderer renders a view model into bytes.
+type Renderer interface {
+	Render(v interface{}) ([]byte, error)
+}
+
+// NewRenderer creates a Renderer of format f, table renderer would be returned
+// if f is unknown.
+func NewRenderer(f Format) Renderer {
+	switch f {
+	case FormatJSON:
+		return jsonRenderer{}
+	case FormatYAML:
+		return yamlRenderer{}
+	case FormatMarkdown:
+		return markdownRenderer{}
+	default:
+		return tableRenderer{}
+	}
+}
+
+// Render renders v in format f.
+func Render(f Format, v interface{}) ([]byte, error) {
+	return NewRenderer(f).Render(v)
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "json.Marshal")
+	}
+
+	return append(data, '\n'), nil
+}
+
+// yamlRenderer renders v into json first, so that view models only need json tags,
+// and the order of fields are kept.
+type yamlRenderer struct{}
+
+func (yamlRenderer) Render(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrap(err, "json.Marshal")
+	}
+
+	node := new(yaml.Node)
+	if err = yaml.Unmarshal(data, node); err != nil {
+		return nil, errors.Wrap(err, "yaml.Unmarshal")
+	}
+	resetYAMLStyle(node)
+
+	buf := bytes.NewBuffer(nil)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(node); err != nil {
+		return nil, errors.Wrap(err, "yaml.Encode")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// resetYAMLStyle resets flow and quoted style from json, strings would still be
+// quoted if they could be resolved into other types.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetYAMLStyle(n)
+	}
+}
+
+type tableRenderer struct{}
+
+func (tableRenderer) Render(v interface{}) ([]byte, error) {
+	switch vv := v.(type) {
+	case Markdowner:
+		return vv.Markdown()
+	case Tabular:
+		buf := bytes.NewBuffer(nil)
+		for _, s := range vv.Sections() {
+			renderTableSection(buf, s)
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, errors.Errorf("%T could not be rendered in %s format", v, FormatTable)
+}
+
+func renderTableSection(buf *bytes.Buffer, s *Section) {
+	if len(s.Fields) != 0 {
+		buf.WriteString("\n")
+		for _, f := range s.Fields {
+			_, _ = fmt.Fprintf(buf, "%s\t:\t\t%s\n", f.Name, f.Value)
+		}
+	}
+
+	if len(s.Header) == 0 {
+		return
+	}
+
+	if s.Title != "" {
+		buf.WriteString(s.Title + ":\n")
+	}
+	w := tablewriter.NewWriter(buf)
+	w.SetHeader(s.Header)
+	w.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	w.SetAlignment(tablewriter.ALIGN_LEFT)
+	w.SetAutoMergeCells(s.MergeCells)
+	w.SetRowLine(s.RowLine)
+	if len(s.ColumnColors) != 0 {
+		w.SetColumnColor(s.ColumnColors...)
+	}
+	for _, row := range s.Rows {
+		if s.RowColors != nil {
+			if colors := s.RowColors(row); colors != nil {
+				w.Rich(row, colors)
+				continue
+			}
+		}
+		w.Append(row)
+	}
+	w.Render()
+}
+
+type markdownRenderer struct{}
+
+func (markdownRenderer) Render(v interface{}) ([]byte, error) {
+	switch vv := v.(type) {
+	case Markdowner:
+		return vv.Markdown()
+	case Tabular:
+		buf := bytes.NewBuffer(nil)
+		for _, s := range vv.Sections() {
+			renderMarkdownSection(buf, s)
+		}
+		return buf.Bytes(), nil
+	}
+
+	return nil, errors.Errorf("%T could not be rendered in %s format", v, FormatMarkdown)
+}
+
+var _markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+
+func renderMarkdownSection(buf *bytes.Buffer, s *Section) {
+	if s.Title != "" {
+		buf.WriteString("## " + s.Title + "\n\n")
+	}
+
+	if len(s.Fields) != 0 {
+		for _, f := range s.Fields {
+			_, _ = fmt.Fprintf(buf, "- **%s**: %s\n", strings.TrimSpace(f.Name), _markdownEscaper.Replace(f.Value))
+		}
+		buf.WriteString("\n")
+	}
+
+	if len(s.Header) == 0 {
+		return
+	}
+
+	writeRow := func(cells []string) {
+		escaped := make([]string, len(cells))
+		for idx, c := range cells {
+			escaped[idx] = _markdownEscaper.Replace(c)
+		}
+		buf.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+	}
+	writeRow(s.Header)
+	buf.WriteString(strings.Repeat("| --- ", len(s.Header)) + "|\n")
+	for _, row := range s.Rows {
+		writeRow(row)
+	}
+	buf.WriteString("\n")
+}
@@ -0,0 +1,58 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testView struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Tags    []string `json:"tags"`
+}
+
+func (v testView) Sections() []*Section {
+	return []*Section{
+		{Fields: []Field{{Name: "Name", Value: v.Name}}},
+		{Title: "Tags", Header: []string{"Tag"}, Rows: [][]string{{"a|b"}}},
+	}
+}
+
+func Test_ParseFormat(t *testing.T) {
+	f, err := ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatTable, f)
+
+	f, err = ParseFormat("JSON")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSON, f)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func Test_Render(t *testing.T) {
+	v := testView{Name: "flow", Version: "1.0", Tags: []string{"true"}}
+
+	out, err := Render(FormatJSON, v)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"name\": \"flow\",\n  \"version\": \"1.0\",\n  \"tags\": [\n    \"true\"\n  ]\n}\n", string(out))
+
+	// strings which look like other types are still quoted.
+	out, err = Render(FormatYAML, v)
+	assert.NoError(t, err)
+	assert.Equal(t, "name: flow\nversion: \"1.0\"\ntags:\n  - \"true\"\n", string(out))
+
+	out, err = Render(FormatMarkdown, v)
+	assert.NoError(t, err)
+	assert.Equal(t, "- **Name**: flow\n\n## Tags\n\n| Tag |\n| --- |\n| a\\|b |\n\n", string(out))
+
+	out, err = Render(FormatTable, v)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "Name\t:\t\tflow\n")
+	assert.Contains(t, string(out), "Tags:\n")
+
+	_, err = Render(FormatTable, struct{}{})
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -40,6 +41,17 @@ type IFlowRepository interface {
 	QueryMergeRequest(filter *MergeRequestDO) (*MergeRequestDO, error)
 	QueryMergeRequests(filter *MergeRequestDO) ([]*MergeRequestDO, error)
 	CloseMergeRequest(projectId int, milestoneId int, mergeRequestIID int) error
+	// UpdateMergeRequestState update the state, merged time, author, pipeline status and merge commit of
+	// the merge request which is located by m.ProjectID and m.MergeRequestIID.
+	UpdateMergeRequestState(m *MergeRequestDO) error
+
+	SaveRelease(m *ReleaseDO, txs ...*gorm2.DB) error
+	QueryRelease(filter *ReleaseDO) (*ReleaseDO, error)
+	QueryReleases(filter *ReleaseDO) ([]*ReleaseDO, error)
+	// CloseRelease marks the release closed and records the tag which is created on master.
+	CloseRelease(projectId int, releaseBranch, tagName string) error
+	SaveReleaseMilestone(m *ReleaseMilestoneDO, txs ...*gorm2.DB) error
+	QueryReleaseMilestones(filter *ReleaseMilestoneDO) ([]*ReleaseMilestoneDO, error)
 }
 
 type removeProjectRepository interface {
@@ -75,6 +87,16 @@ type MilestoneDO struct {
 	Desc        string     `gorm:"column:desc"`
 	WebURL      string     `gorm:"column:web_url"`
 	ClosedAt    *time.Time `gorm:"column:closed_at"`
+	// Version is the semantic version of milestone, it's only recorded in semver mode.
+	Version string `gorm:"column:version"`
+	// GroupID is the group which the milestone belongs to, zero means the milestone
+	// is a project milestone.
+	GroupID int `gorm:"column:group_id"`
+}
+
+// IsGroupMilestone returns true if the milestone is a group milestone.
+func (m *MilestoneDO) IsGroupMilestone() bool {
+	return m.GroupID != 0
 }
 
 func (m *MilestoneDO) TableName() string {
@@ -107,12 +129,35 @@ type IssueDO struct {
 	RelatedBranch string     `gorm:"column:related_branch"`
 	WebURL        string     `gorm:"column:web_url"`
 	ClosedAt      *time.Time `gorm:"column:closed_at"`
+	// Labels of issue on gitlab which are joined by comma.
+	Labels string `gorm:"column:labels"`
+	// Assignees are usernames of assignees which are joined by comma, they're
+	// chosen while the issue is created by gitlab-flow.
+	Assignees string `gorm:"column:assignees"`
 }
 
 func (m *IssueDO) TableName() string {
 	return "project_issue"
 }
 
+// LabelList split Labels into slice.
+func (m *IssueDO) LabelList() []string {
+	return splitComma(m.Labels)
+}
+
+// AssigneeList split Assignees into slice.
+func (m *IssueDO) AssigneeList() []string {
+	return splitComma(m.Assignees)
+}
+
+func splitComma(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(s, ",")
+}
+
 // MergeRequestDO data model
 type MergeRequestDO struct {
 	gorm2.Model
@@ -126,12 +171,84 @@ type MergeRequestDO struct {
 	TargetBranch    string     `gorm:"column:target_branch"`
 	WebURL          string     `gorm:"column:web_url"`
 	ClosedAt        *time.Time `gorm:"column:closed_at"`
+
+	// State is the state of merge request on gitlab, one of opened, closed, locked, merged.
+	// Empty means the state is unknown, since the record was created before state tracking.
+	State          string     `gorm:"column:state"`
+	MergedAt       *time.Time `gorm:"column:merged_at"`
+	Author         string     `gorm:"column:author"`
+	PipelineStatus string     `gorm:"column:pipeline_status"`
+	MergeCommitSHA string     `gorm:"column:merge_commit_sha"`
+	// AutoMergeError is the reason why merge request could not be merged automatically
+	// while it's created with --auto-merge, empty means no error.
+	AutoMergeError string `gorm:"column:auto_merge_error"`
+	// Draft is true if the merge request is draft, it could not be merged until marked as ready.
+	Draft bool `gorm:"column:draft"`
+	// Assignees and Reviewers are usernames which are joined by comma, they're chosen
+	// while the merge request is created by gitlab-flow.
+	Assignees string `gorm:"column:assignees"`
+	Reviewers string `gorm:"column:reviewers"`
+}
+
+// MergeRequest states those are same as gitlab.
+const (
+	MergeRequestStateOpened = "opened"
+	MergeRequestStateClosed = "closed"
+	MergeRequestStateLocked = "locked"
+	MergeRequestStateMerged = "merged"
+)
+
+// AssigneeList split Assignees into slice.
+func (m *MergeRequestDO) AssigneeList() []string {
+	return splitComma(m.Assignees)
+}
+
+// ReviewerList split Reviewers into slice.
+func (m *MergeRequestDO) ReviewerList() []string {
+	return splitComma(m.Reviewers)
+}
+
+// IsMerged returns true if the merge request has been merged.
+func (m *MergeRequestDO) IsMerged() bool {
+	return m.State == MergeRequestStateMerged
 }
 
 func (m *MergeRequestDO) TableName() string {
 	return "project_merge_request"
 }
 
+// ReleaseDO data model, release is a branch which bundles several features
+// before they go to master.
+type ReleaseDO struct {
+	gorm2.Model
+
+	ProjectID  int        `gorm:"column:project_id"`
+	Version    string     `gorm:"column:version"`
+	Desc       string     `gorm:"column:desc"`
+	BranchName string     `gorm:"column:branch_name"`
+	BaseBranch string     `gorm:"column:base_branch"`
+	TagName    string     `gorm:"column:tag_name"`
+	ClosedAt   *time.Time `gorm:"column:closed_at"`
+}
+
+func (m *ReleaseDO) TableName() string {
+	return "project_release"
+}
+
+// ReleaseMilestoneDO data model, it records which feature milestones are attached to the release.
+type ReleaseMilestoneDO struct {
+	gorm2.Model
+
+	ProjectID     int    `gorm:"column:project_id"`
+	ReleaseBranch string `gorm:"column:release_branch"`
+	MilestoneID   int    `gorm:"column:milestone_id"`
+	FeatureBranch string `gorm:"column:feature_branch"`
+}
+
+func (m *ReleaseMilestoneDO) TableName() string {
+	return "project_release_milestone"
+}
+
 type QueryProjectsFilter struct {
 	ProjectName string
 	WorkDir     string
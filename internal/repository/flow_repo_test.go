@@ -124,6 +124,56 @@ func (s *flowRepoTestSuite) Test_CreateBranch_withoutTx() {
 	s.Nil(err)
 }
 
+func (s *flowRepoTestSuite) Test_UpdateMergeRequestState() {
+	s.NotNil(s.repo)
+
+	mr := &repository.MergeRequestDO{
+		ProjectID:       112312,
+		MilestoneID:     212312,
+		MergeRequestIID: 12,
+		SourceBranch:    "feature/state",
+		TargetBranch:    "master",
+		State:           repository.MergeRequestStateOpened,
+	}
+	err := s.repo.SaveMergeRequest(mr)
+	s.Nil(err)
+
+	mr.State = repository.MergeRequestStateMerged
+	err = s.repo.UpdateMergeRequestState(mr)
+	s.Nil(err)
+
+	got, err := s.repo.QueryMergeRequest(&repository.MergeRequestDO{
+		ProjectID:       mr.ProjectID,
+		MergeRequestIID: mr.MergeRequestIID,
+	})
+	s.Nil(err)
+	s.True(got.IsMerged())
+}
+
+func (s *flowRepoTestSuite) Test_CloseRelease() {
+	s.NotNil(s.repo)
+
+	release := &repository.ReleaseDO{
+		ProjectID:  112312,
+		Version:    "1.2",
+		BranchName: "release/1.2",
+		BaseBranch: "test",
+	}
+	err := s.repo.SaveRelease(release)
+	s.Nil(err)
+
+	err = s.repo.CloseRelease(release.ProjectID, release.BranchName, "v1.2.0")
+	s.Nil(err)
+
+	got, err := s.repo.QueryRelease(&repository.ReleaseDO{
+		ProjectID:  release.ProjectID,
+		BranchName: release.BranchName,
+	})
+	s.Nil(err)
+	s.NotNil(got.ClosedAt)
+	s.Equal("v1.2.0", got.TagName)
+}
+
 func Test_flowRepo(t *testing.T) {
 	suite.Run(t, new(flowRepoTestSuite))
 }
@@ -80,6 +80,8 @@ func ConnectDB(path string, debug bool) func() *gorm2.DB {
 			&repository.BranchDO{},
 			&repository.IssueDO{},
 			&repository.MergeRequestDO{},
+			&repository.ReleaseDO{},
+			&repository.ReleaseMilestoneDO{},
 		); err != nil {
 			log.Warnf("auto migrate database failed: %v", err)
 		}
@@ -278,16 +280,26 @@ func (repo *sqliteFlowRepositoryImpl) SaveIssue(m *repository.IssueDO, txs ...*g
 }
 
 func (repo *sqliteFlowRepositoryImpl) BatchCreateIssue(records []*repository.IssueDO, txs ...*gorm2.DB) error {
-	// uniq records with local database.
+	// uniq records with local database. labels are excluded from the filter,
+	// since they may be changed on gitlab.
 	uniq := make([]*repository.IssueDO, 0, len(records))
 	for idx, v := range records {
 		count := int64(0)
-		err := repo.db.Model(v).Where(v).Count(&count).Error
+		filter := &repository.IssueDO{
+			ProjectID:     v.ProjectID,
+			MilestoneID:   v.MilestoneID,
+			IssueIID:      v.IssueIID,
+			RelatedBranch: v.RelatedBranch,
+		}
+		err := repo.db.Model(filter).Where(filter).Count(&count).Error
 		if err != nil {
 			return err
 		}
 
 		if count > 0 {
+			if err = repo.updateIssueLabels(repo.txIn(txs...), v); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -302,6 +314,20 @@ func (repo *sqliteFlowRepositoryImpl) BatchCreateIssue(records []*repository.Iss
 	return repo.batchCreate(uniq, len(uniq), txs...)
 }
 
+func (repo *sqliteFlowRepositoryImpl) updateIssueLabels(tx *gorm2.DB, m *repository.IssueDO) error {
+	if tx == nil {
+		tx = repo.db
+	}
+
+	if err := tx.Model(&repository.IssueDO{}).
+		Where("project_id = ? AND milestone_id = ? AND issue_iid = ?", m.ProjectID, m.MilestoneID, m.IssueIID).
+		Update("labels", m.Labels).Error; err != nil {
+		return errors.Wrap(err, "could not update issue labels")
+	}
+
+	return nil
+}
+
 func (repo *sqliteFlowRepositoryImpl) QueryIssue(filter *repository.IssueDO) (*repository.IssueDO, error) {
 	out := new(repository.IssueDO)
 	err := repo.db.
@@ -335,16 +361,22 @@ func (repo *sqliteFlowRepositoryImpl) SaveMergeRequest(m *repository.MergeReques
 }
 
 func (repo *sqliteFlowRepositoryImpl) BatchCreateMergeRequest(records []*repository.MergeRequestDO, txs ...*gorm2.DB) error {
-	// uniq records with local database.
+	// uniq records with local database. merge request is identified by project and IID,
+	// since the state of merge request may be changed.
 	uniq := make([]*repository.MergeRequestDO, 0, len(records))
 	for idx, v := range records {
 		count := int64(0)
-		err := repo.db.Model(v).Where(v).Count(&count).Error
+		filter := &repository.MergeRequestDO{ProjectID: v.ProjectID, MergeRequestIID: v.MergeRequestIID}
+		err := repo.db.Model(filter).Where(filter).Count(&count).Error
 		if err != nil {
 			return err
 		}
 
 		if count > 0 {
+			// update the state of existing merge request.
+			if err = repo.updateMergeRequestState(repo.txIn(txs...), v); err != nil {
+				return err
+			}
 			continue
 		}
 
@@ -390,6 +422,34 @@ func (repo *sqliteFlowRepositoryImpl) QueryMergeRequests(
 	return out, nil
 }
 
+func (repo *sqliteFlowRepositoryImpl) UpdateMergeRequestState(m *repository.MergeRequestDO) error {
+	return repo.updateMergeRequestState(nil, m)
+}
+
+func (repo *sqliteFlowRepositoryImpl) updateMergeRequestState(tx *gorm2.DB, m *repository.MergeRequestDO) error {
+	if m == nil || m.ProjectID <= 0 || m.MergeRequestIID <= 0 || m.State == "" {
+		return nil
+	}
+	if tx == nil {
+		tx = repo.db
+	}
+
+	if err := tx.Model(&repository.MergeRequestDO{}).
+		Where("project_id = ? AND merge_request_iid = ?", m.ProjectID, m.MergeRequestIID).
+		Updates(map[string]interface{}{
+			"state":            m.State,
+			"merged_at":        m.MergedAt,
+			"author":           m.Author,
+			"pipeline_status":  m.PipelineStatus,
+			"merge_commit_sha": m.MergeCommitSHA,
+			"draft":            m.Draft,
+		}).Error; err != nil {
+		return errors.Wrap(err, "could not update merge request state")
+	}
+
+	return nil
+}
+
 // insertRecordWithCheck would insert data and checking data is exists or not.
 // If data has been exists, function would return directly, otherwise function would
 // insert into database. Externally, it would retry when insert got err `database is locked`.
@@ -522,6 +582,15 @@ func (repo *sqliteFlowRepositoryImpl) RemoveProjectAndRelatedData(projectId int)
 	if err = tx.Unscoped().Delete(&repository.MergeRequestDO{}, delCondition5).Error; err != nil {
 		return err
 	}
+	// remove releases
+	delCondition6 := &repository.ReleaseDO{ProjectID: projectId}
+	if err = tx.Unscoped().Delete(&repository.ReleaseDO{}, delCondition6).Error; err != nil {
+		return err
+	}
+	delCondition7 := &repository.ReleaseMilestoneDO{ProjectID: projectId}
+	if err = tx.Unscoped().Delete(&repository.ReleaseMilestoneDO{}, delCondition7).Error; err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -577,3 +646,74 @@ func (repo *sqliteFlowRepositoryImpl) CloseMergeRequest(projectId int, milestone
 
 	return nil
 }
+
+func (repo *sqliteFlowRepositoryImpl) SaveRelease(m *repository.ReleaseDO, txs ...*gorm2.DB) error {
+	return repo.insertRecordWithCheck(repo.txIn(txs...), m)
+}
+
+func (repo *sqliteFlowRepositoryImpl) QueryRelease(filter *repository.ReleaseDO) (*repository.ReleaseDO, error) {
+	out := new(repository.ReleaseDO)
+	err := repo.db.
+		Model(filter).
+		Order("created_at DESC").
+		Where(filter).
+		First(out).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (repo *sqliteFlowRepositoryImpl) QueryReleases(filter *repository.ReleaseDO) ([]*repository.ReleaseDO, error) {
+	out := make([]*repository.ReleaseDO, 0, 10)
+	err := repo.db.
+		Model(filter).
+		Order("created_at DESC").
+		Where(filter).
+		Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (repo *sqliteFlowRepositoryImpl) CloseRelease(projectId int, releaseBranch, tagName string) error {
+	if projectId <= 0 || releaseBranch == "" {
+		return nil
+	}
+
+	now := time.Now()
+
+	if err := repo.db.Model(&repository.ReleaseDO{}).
+		Where("project_id = ? AND branch_name = ?", projectId, releaseBranch).
+		Updates(map[string]interface{}{
+			"closed_at": now,
+			"tag_name":  tagName,
+		}).Error; err != nil {
+		return errors.Wrap(err, "could not close release")
+	}
+
+	return nil
+}
+
+func (repo *sqliteFlowRepositoryImpl) SaveReleaseMilestone(m *repository.ReleaseMilestoneDO, txs ...*gorm2.DB) error {
+	return repo.insertRecordWithCheck(repo.txIn(txs...), m)
+}
+
+func (repo *sqliteFlowRepositoryImpl) QueryReleaseMilestones(
+	filter *repository.ReleaseMilestoneDO) ([]*repository.ReleaseMilestoneDO, error) {
+
+	out := make([]*repository.ReleaseMilestoneDO, 0, 10)
+	err := repo.db.
+		Model(filter).
+		Order("created_at ASC").
+		Where(filter).
+		Find(&out).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
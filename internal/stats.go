@@ -0,0 +1,131 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	gitlabop "github.com/yeqown/gitlab-flow/internal/gitlab-operator"
+	"github.com/yeqown/gitlab-flow/internal/repository"
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+// fillLocalProjectStats computes statistics from local records of the project:
+// * open features (milestones which have not been closed) and issues of them.
+// * hotfixes those were created in the last stats.Days days.
+// * average time from feature open (milestone created) to its first merge into master.
+func fillLocalProjectStats(
+	stats *ProjectStatsView,
+	now time.Time,
+	milestones []*repository.MilestoneDO,
+	issues []*repository.IssueDO,
+	mrs []*repository.MergeRequestDO,
+) {
+	issuesOfMilestone := make(map[int][]*repository.IssueDO, len(milestones))
+	since := now.AddDate(0, 0, -stats.Days)
+	for _, issue := range issues {
+		if strings.HasPrefix(issue.RelatedBranch, types.HotfixBranchPrefix) {
+			if !issue.CreatedAt.Before(since) {
+				stats.HotfixCount++
+			}
+			continue
+		}
+		if issue.MilestoneID != 0 {
+			issuesOfMilestone[issue.MilestoneID] = append(issuesOfMilestone[issue.MilestoneID], issue)
+		}
+	}
+
+	// the first merged time into master of each milestone.
+	mergedAt := make(map[int]time.Time, len(milestones))
+	for _, mr := range mrs {
+		if mr.MilestoneID == 0 || mr.MergedAt == nil || mr.TargetBranch != types.MasterBranch.String() {
+			continue
+		}
+		if t, ok := mergedAt[mr.MilestoneID]; !ok || mr.MergedAt.Before(t) {
+			mergedAt[mr.MilestoneID] = *mr.MergedAt
+		}
+	}
+
+	var leadTime time.Duration
+	for _, m := range milestones {
+		if t, ok := mergedAt[m.MilestoneID]; ok && t.After(m.CreatedAt) {
+			leadTime += t.Sub(m.CreatedAt)
+			stats.MergedFeatures++
+		}
+
+		if m.ClosedAt != nil {
+			continue
+		}
+
+		stats.OpenFeatures++
+		ms := &MilestoneIssueStatsView{MilestoneID: m.MilestoneID, Title: m.Title}
+		for _, issue := range issuesOfMilestone[m.MilestoneID] {
+			ms.Issues++
+			if issue.ClosedAt != nil {
+				ms.ClosedIssues++
+			}
+		}
+		stats.MilestoneIssues = append(stats.MilestoneIssues, ms)
+	}
+
+	if stats.MergedFeatures != 0 {
+		stats.AvgFeatureLeadTimeHours = toHours(leadTime / time.Duration(stats.MergedFeatures))
+	}
+}
+
+// fillRemoteProjectStats computes statistics from gitlab, openMRs are all opened merge requests
+// of the project, they are counted by target branch and the oldest one is picked out.
+func fillRemoteProjectStats(
+	stats *ProjectStatsView,
+	now time.Time,
+	openMilestones int,
+	openMRs []gitlabop.MergeRequestShort,
+) {
+	openMergeRequests := len(openMRs)
+	stats.OpenMilestones = &openMilestones
+	stats.OpenMergeRequests = &openMergeRequests
+
+	counts := make(map[string]int, 4)
+	var oldest *gitlabop.MergeRequestShort
+	for idx, mr := range openMRs {
+		counts[mr.TargetBranch]++
+		if mr.CreatedAt == nil {
+			continue
+		}
+		if oldest == nil || mr.CreatedAt.Before(*oldest.CreatedAt) {
+			oldest = &openMRs[idx]
+		}
+	}
+
+	stats.OpenMergeRequestsByTarget = make([]*TargetBranchStatsView, 0, len(counts))
+	for branch, cnt := range counts {
+		stats.OpenMergeRequestsByTarget = append(stats.OpenMergeRequestsByTarget,
+			&TargetBranchStatsView{TargetBranch: branch, Count: cnt})
+	}
+	sort.Slice(stats.OpenMergeRequestsByTarget, func(i, j int) bool {
+		a, b := stats.OpenMergeRequestsByTarget[i], stats.OpenMergeRequestsByTarget[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.TargetBranch < b.TargetBranch
+	})
+
+	if oldest != nil {
+		stats.OldestOpenMergeRequest = &OpenMergeRequestAgeView{
+			MergeRequestView: &MergeRequestView{
+				IID:          oldest.IID,
+				SourceBranch: oldest.SourceBranch,
+				TargetBranch: oldest.TargetBranch,
+				WebURL:       oldest.WebURL,
+				State:        string(oldest.State),
+			},
+			CreatedAt: *oldest.CreatedAt,
+			AgeHours:  toHours(now.Sub(*oldest.CreatedAt)),
+		}
+	}
+}
+
+// toHours converts d into hours which keeps one decimal place.
+func toHours(d time.Duration) float64 {
+	return float64(d.Round(6*time.Minute)) / float64(time.Hour)
+}
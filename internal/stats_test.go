@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	gorm2 "gorm.io/gorm"
+
+	gitlabop "github.com/yeqown/gitlab-flow/internal/gitlab-operator"
+	"github.com/yeqown/gitlab-flow/internal/repository"
+)
+
+func Test_fillLocalProjectStats(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	at := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	milestones := []*repository.MilestoneDO{
+		{Model: gorm2.Model{CreatedAt: at(10)}, MilestoneID: 1, Title: "login"},
+		{Model: gorm2.Model{CreatedAt: at(20)}, MilestoneID: 2, Title: "profile", ClosedAt: ptr(at(5))},
+		{Model: gorm2.Model{CreatedAt: at(3)}, MilestoneID: 3, Title: "search"},
+	}
+	issues := []*repository.IssueDO{
+		{MilestoneID: 1, RelatedBranch: "feature/login"},
+		{MilestoneID: 1, RelatedBranch: "feature/login", ClosedAt: ptr(at(1))},
+		{MilestoneID: 2, RelatedBranch: "feature/profile"},
+		{Model: gorm2.Model{CreatedAt: at(2)}, RelatedBranch: "hotfix/crash"},
+		{Model: gorm2.Model{CreatedAt: at(40)}, RelatedBranch: "hotfix/typo"},
+	}
+	mrs := []*repository.MergeRequestDO{
+		{MilestoneID: 1, TargetBranch: "master", MergedAt: ptr(at(8))},
+		{MilestoneID: 1, TargetBranch: "master", MergedAt: ptr(at(9))},
+		{MilestoneID: 2, TargetBranch: "master", MergedAt: ptr(at(6))},
+		{MilestoneID: 3, TargetBranch: "test", MergedAt: ptr(at(1))},
+	}
+
+	stats := &ProjectStatsView{Days: 30}
+	fillLocalProjectStats(stats, now, milestones, issues, mrs)
+	assert.Equal(t, 2, stats.OpenFeatures)
+	assert.Equal(t, 1, stats.HotfixCount)
+	assert.Equal(t, []*MilestoneIssueStatsView{
+		{MilestoneID: 1, Title: "login", Issues: 2, ClosedIssues: 1},
+		{MilestoneID: 3, Title: "search"},
+	}, stats.MilestoneIssues)
+	// (1 day + 14 days) / 2
+	assert.Equal(t, 2, stats.MergedFeatures)
+	assert.Equal(t, 180.0, stats.AvgFeatureLeadTimeHours)
+}
+
+func Test_fillRemoteProjectStats(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		t := now.Add(-time.Duration(hours) * time.Hour)
+		return &t
+	}
+
+	openMRs := []gitlabop.MergeRequestShort{
+		{IID: 1, TargetBranch: "test", CreatedAt: at(5)},
+		{IID: 2, TargetBranch: "master", CreatedAt: at(50)},
+		{IID: 3, TargetBranch: "test", CreatedAt: at(1)},
+		{IID: 4, TargetBranch: "dev"},
+	}
+
+	stats := &ProjectStatsView{}
+	fillRemoteProjectStats(stats, now, 3, openMRs)
+	assert.Equal(t, 3, *stats.OpenMilestones)
+	assert.Equal(t, 4, *stats.OpenMergeRequests)
+	assert.Equal(t, []*TargetBranchStatsView{
+		{TargetBranch: "test", Count: 2},
+		{TargetBranch: "dev", Count: 1},
+		{TargetBranch: "master", Count: 1},
+	}, stats.OpenMergeRequestsByTarget)
+	if assert.NotNil(t, stats.OldestOpenMergeRequest) {
+		assert.Equal(t, 2, stats.OldestOpenMergeRequest.IID)
+		assert.Equal(t, 50.0, stats.OldestOpenMergeRequest.AgeHours)
+	}
+	assert.Equal(t, "2d 2.0h", formatHours(stats.OldestOpenMergeRequest.AgeHours))
+	assert.Equal(t, "3", formatCount(stats.OpenMilestones))
+
+	// remote statistics are left empty if gitlab could not be reached.
+	assert.Equal(t, "-", formatCount((&ProjectStatsView{}).OpenMilestones))
+}
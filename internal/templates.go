@@ -0,0 +1,221 @@
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/yeqown/log"
+
+	gitlabop "github.com/yeqown/gitlab-flow/internal/gitlab-operator"
+	"github.com/yeqown/gitlab-flow/internal/repository"
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+const (
+	_issueTemplateDir        = ".gitlab/issue_templates"
+	_mergeRequestTemplateDir = ".gitlab/merge_request_templates"
+	// _defaultTemplateName is the template which gitlab chooses by default.
+	_defaultTemplateName = "Default"
+)
+
+// TemplateData is the variables of issue and merge request templates. Title and Desc are
+// generated by gitlab-flow, so that templates could extend them rather than rewriting.
+type TemplateData struct {
+	Step  types.FlowStep
+	Title string
+	Desc  string
+
+	// Feature is the feature name (milestone title), empty for hotfix.
+	Feature       string
+	FeatureBranch string
+	MilestoneURL  string
+
+	IssueIID   int
+	IssueTitle string
+	IssueURL   string
+
+	// SourceBranch, TargetBranch and Commits are empty for issue, Commits are
+	// commits of SourceBranch those have not been merged into TargetBranch.
+	SourceBranch string
+	TargetBranch string
+	Commits      []TemplateCommit
+}
+
+type TemplateCommit struct {
+	ShortID string
+	Title   string
+	Author  string
+	WebURL  string
+}
+
+// renderTemplate renders text as text/template with data.
+func renderTemplate(name, text string, data *TemplateData) (string, error) {
+	tpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", errors.Wrapf(err, "parse template(%s) failed", name)
+	}
+
+	sb := strings.Builder{}
+	if err = tpl.Execute(&sb, data); err != nil {
+		return "", errors.Wrapf(err, "execute template(%s) failed", name)
+	}
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// loadRepoTemplate loads the template of step from dir of the repository, templates are
+// {step}.md or Default.md. Empty string would be returned if there is no template.
+func loadRepoTemplate(repoDir, dir string, step types.FlowStep) string {
+	for _, name := range []string{string(step), _defaultTemplateName, strings.ToLower(_defaultTemplateName)} {
+		if name == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(repoDir, dir, name+".md"))
+		if err == nil {
+			return string(data)
+		}
+		if !os.IsNotExist(err) {
+			log.Warnf("could not read template %s: %v", filepath.Join(dir, name+".md"), err)
+		}
+	}
+
+	return ""
+}
+
+// applyTemplates renders title and description of issue or merge request if there is any template,
+// titleTpl and descTpl come from project configuration and descTpl falls back to repoDir.
+// The generated title and description would be kept if template is empty or could not be rendered.
+//
+// Templates of repository are shared with gitlab web pages which know nothing about gitlab-flow, so the
+// generated description (e.g. "Closes #1" which links merge request to issue) is appended to the rendered
+// one if the repository template does not use .Desc.
+func applyTemplates(
+	titleTpl, descTpl, repoDir, dir string, data *TemplateData, fillCommits func()) (title, desc string) {
+	title, desc = data.Title, data.Desc
+	appendDesc := false
+	if descTpl == "" {
+		descTpl = loadRepoTemplate(repoDir, dir, data.Step)
+		appendDesc = data.Desc != "" && !strings.Contains(descTpl, ".Desc")
+	}
+	if titleTpl == "" && descTpl == "" {
+		return title, desc
+	}
+
+	if fillCommits != nil && strings.Contains(titleTpl+descTpl, ".Commits") {
+		fillCommits()
+	}
+
+	if titleTpl != "" {
+		if v, err := renderTemplate("title", titleTpl, data); err != nil {
+			log.Warnf("could not render title template, use default title: %v", err)
+		} else if v != "" {
+			title = v
+		}
+	}
+	if descTpl != "" {
+		if v, err := renderTemplate("desc", descTpl, data); err != nil {
+			log.Warnf("could not render description template, use default description: %v", err)
+		} else if appendDesc {
+			desc = v + "\n\n" + data.Desc
+		} else {
+			desc = v
+		}
+	}
+
+	return title, desc
+}
+
+// newTemplateData collects variables of templates from local records, branch is the related
+// branch of issue or the source branch of merge request.
+func (f flowImpl) newTemplateData(
+	title, desc string, milestoneID, issueIID int, branch, targetBranch string) *TemplateData {
+	data := &TemplateData{
+		Step:         flowStepOf(branch, targetBranch),
+		Title:        title,
+		Desc:         desc,
+		IssueIID:     issueIID,
+		SourceBranch: branch,
+		TargetBranch: targetBranch,
+	}
+	if targetBranch == "" {
+		data.SourceBranch = ""
+	}
+
+	switch {
+	case strings.HasPrefix(branch, types.FeatureBranchPrefix):
+		data.FeatureBranch = branch
+	case strings.HasPrefix(targetBranch, types.FeatureBranchPrefix):
+		data.FeatureBranch = targetBranch
+	}
+
+	if milestoneID != 0 {
+		milestone, err := f.repo.QueryMilestone(&repository.MilestoneDO{
+			ProjectID:   f.ctx.Project().ID,
+			MilestoneID: milestoneID,
+		})
+		if err == nil {
+			data.Feature = milestone.Title
+			data.MilestoneURL = milestone.WebURL
+		}
+	}
+	if issueIID != 0 {
+		issue, err := f.repo.QueryIssue(&repository.IssueDO{
+			ProjectID: f.ctx.Project().ID,
+			IssueIID:  issueIID,
+		})
+		if err == nil {
+			data.IssueTitle = issue.Title
+			data.IssueURL = issue.WebURL
+		}
+	}
+
+	return data
+}
+
+// issueTemplates renders title and description of issue.
+func (f flowImpl) issueTemplates(data *TemplateData) (title, desc string) {
+	setting := f.ctx.Config().Templates
+	if setting == nil {
+		setting = &types.TemplateSetting{}
+	}
+
+	return applyTemplates(setting.IssueTitle, setting.IssueDesc, f.ctx.CWD(), _issueTemplateDir, data, nil)
+}
+
+// mergeRequestTemplates renders title and description of merge request, commits are
+// listed from gitlab only if templates use them.
+func (f flowImpl) mergeRequestTemplates(ctx context.Context, data *TemplateData) (title, desc string) {
+	setting := f.ctx.Config().Templates
+	if setting == nil {
+		setting = &types.TemplateSetting{}
+	}
+
+	fillCommits := func() {
+		commits, err := f.gitlabOperator.CompareCommits(ctx, &gitlabop.CompareCommitsRequest{
+			ProjectID: f.ctx.Project().ID,
+			From:      data.TargetBranch,
+			To:        data.SourceBranch,
+		})
+		if err != nil {
+			log.Warnf("could not list commits of merge request: %v", err)
+			return
+		}
+
+		for _, c := range commits {
+			data.Commits = append(data.Commits, TemplateCommit{
+				ShortID: c.ShortID,
+				Title:   c.Title,
+				Author:  c.Author,
+				WebURL:  c.WebURL,
+			})
+		}
+	}
+
+	return applyTemplates(setting.MergeRequestTitle, setting.MergeRequestDesc,
+		f.ctx.CWD(), _mergeRequestTemplateDir, data, fillCommits)
+}
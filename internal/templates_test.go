@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+func Test_applyTemplates(t *testing.T) {
+	repoDir := t.TempDir()
+	dir := filepath.Join(repoDir, _mergeRequestTemplateDir)
+	assert.NoError(t, os.MkdirAll(dir, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Default.md"), []byte("default of {{ .Feature }}"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "release.md"),
+		[]byte("{{ .Desc }}\n{{ range .Commits }}- {{ .ShortID }} {{ .Title }}\n{{ end }}- [ ] reviewed"), 0o644))
+
+	newData := func(step types.FlowStep) *TemplateData {
+		return &TemplateData{Step: step, Title: "Merge feature/login into master", Desc: "login", Feature: "login"}
+	}
+	fillCommits := func(data *TemplateData) func() {
+		return func() { data.Commits = []TemplateCommit{{ShortID: "abc123", Title: "add login"}} }
+	}
+
+	data := newData(types.FlowStepRelease)
+	title, desc := applyTemplates("", "", repoDir, _mergeRequestTemplateDir, data, fillCommits(data))
+	assert.Equal(t, "Merge feature/login into master", title)
+	assert.Equal(t, "login\n- abc123 add login\n- [ ] reviewed", desc)
+
+	data = newData(types.FlowStepTest)
+	title, desc = applyTemplates("[{{ .Step }}] {{ .Feature }}", "", repoDir, _mergeRequestTemplateDir, data, nil)
+	assert.Equal(t, "[test] login", title)
+	// repository template without .Desc keeps the generated description.
+	assert.Equal(t, "default of login\n\nlogin", desc)
+
+	// configured template is used as it is.
+	data = newData(types.FlowStepTest)
+	_, desc = applyTemplates("", "default of {{ .Feature }}", repoDir, _mergeRequestTemplateDir, data, nil)
+	assert.Equal(t, "default of login", desc)
+
+	// invalid template falls back to the generated one.
+	data = newData(types.FlowStepDebug)
+	title, desc = applyTemplates("{{ .Unknown }}", "{{", t.TempDir(), _mergeRequestTemplateDir, data, nil)
+	assert.Equal(t, "Merge feature/login into master", title)
+	assert.Equal(t, "login", desc)
+}
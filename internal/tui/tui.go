@@ -0,0 +1,530 @@
+// Package tui is an interactive terminal dashboard, it browses projects, features, issues and
+// merge requests from local data with keyboard, and triggers flow actions on the selected row.
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pkg/errors"
+	"github.com/rivo/tview"
+	"github.com/yeqown/log"
+
+	"github.com/yeqown/gitlab-flow/internal"
+	gitop "github.com/yeqown/gitlab-flow/internal/git-operator"
+	"github.com/yeqown/gitlab-flow/internal/types"
+	"github.com/yeqown/gitlab-flow/pkg"
+)
+
+// Workbench is everything the dashboard needs for one project, data are read from Dash, and
+// actions are done by Flow and Git.
+type Workbench struct {
+	Dash internal.IDash
+	// Flow is called on demand, since constructing flow refreshes access token which
+	// is not necessary to browse local data.
+	Flow func() (internal.IFlow, error)
+	Git  gitop.IGitOperator
+}
+
+// Opener opens the workbench of project which is selected in the dashboard.
+type Opener func(project *internal.ProjectItemView) (*Workbench, error)
+
+// Run runs the dashboard of the current project until user quits. Logs would break the screen,
+// so they are only printed in logLevel while actions are running with the terminal restored.
+func Run(current *Workbench, open Opener, logLevel log.Level) error {
+	d := newDashboard(current, open)
+	d.logLevel = logLevel
+	if err := d.reload(); err != nil {
+		return err
+	}
+
+	log.SetLogLevel(log.LevelFatal)
+	defer log.SetLogLevel(logLevel)
+
+	if err := d.app.Run(); err != nil {
+		return errors.Wrap(err, "run dashboard")
+	}
+
+	return nil
+}
+
+const (
+	_helpText = "[yellow]enter[-] open  [yellow]esc[-] back  [yellow]tab[-] next  [yellow]o[-] browser  " +
+		"[yellow]c[-] checkout  [yellow]d/t/m[-] feature debug/test/release  [yellow]r[-] refresh  " +
+		"[yellow]s[-] sync milestone  [yellow]q[-] quit"
+
+	_confirmPage = "confirm"
+	_mainPage    = "main"
+)
+
+// featureAction is an action of IFlow which operates the feature, key is the shortcut of action.
+type featureAction struct {
+	key  rune
+	name string
+	fn   func(flow internal.IFlow, opc *types.OpFeatureContext) error
+}
+
+var _featureActions = []featureAction{
+	{key: 'd', name: "debug", fn: internal.IFlow.FeatureDebugging},
+	{key: 't', name: "test", fn: internal.IFlow.FeatureTest},
+	{key: 'm', name: "release", fn: internal.IFlow.FeatureRelease},
+}
+
+type dashboard struct {
+	app   *tview.Application
+	pages *tview.Pages
+	open  Opener
+	wb    *Workbench
+
+	logLevel log.Level
+
+	currentProjectID int
+	projects         []*internal.ProjectItemView
+	features         []*internal.FeatureItemView
+	rows             []*detailRow
+
+	projectList *tview.List
+	featureList *tview.List
+	detailTable *tview.Table
+	status      *tview.TextView
+}
+
+func newDashboard(current *Workbench, open Opener) *dashboard {
+	d := &dashboard{
+		app:  tview.NewApplication(),
+		open: open,
+		wb:   current,
+
+		projectList: tview.NewList().ShowSecondaryText(false),
+		featureList: tview.NewList(),
+		detailTable: tview.NewTable().SetSelectable(true, false).SetFixed(1, 0),
+		status:      tview.NewTextView().SetDynamicColors(true),
+	}
+
+	d.projectList.SetBorder(true).SetTitle(" Projects ")
+	d.projectList.SetSelectedFunc(func(idx int, _, _ string, _ rune) { d.switchProject(idx) })
+
+	d.featureList.SetBorder(true).SetTitle(" Features ")
+	d.featureList.SetChangedFunc(func(idx int, _, _ string, _ rune) { d.loadDetail(idx) })
+	d.featureList.SetSelectedFunc(func(int, string, string, rune) { d.app.SetFocus(d.detailTable) })
+	d.featureList.SetDoneFunc(func() { d.app.SetFocus(d.projectList) })
+
+	d.detailTable.SetBorder(true).SetTitle(" Issues & Merge Requests ")
+	d.detailTable.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			d.app.SetFocus(d.featureList)
+		}
+	})
+
+	help := tview.NewTextView().SetDynamicColors(true).SetText(_helpText)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(d.projectList, 0, 1, false).
+			AddItem(d.featureList, 0, 2, true).
+			AddItem(d.detailTable, 0, 4, false), 0, 1, true).
+		AddItem(d.status, 1, 0, false).
+		AddItem(help, 1, 0, false)
+
+	d.pages = tview.NewPages().AddPage(_mainPage, layout, true, true)
+	d.app.SetRoot(d.pages, true).SetFocus(d.featureList)
+	d.app.SetInputCapture(d.handleKey)
+
+	return d
+}
+
+// handleKey handles global shortcuts, keys are passed to the focused widget if they are not handled.
+func (d *dashboard) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if d.pages.HasPage(_confirmPage) {
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyTab:
+		d.focusNext()
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+
+	switch r := event.Rune(); r {
+	case 'q':
+		d.app.Stop()
+	case 'r':
+		d.report(d.reload(), "refreshed from local data")
+	case 's':
+		d.syncMilestone()
+	case 'o':
+		d.openBrowser()
+	case 'c':
+		d.checkout()
+	default:
+		for _, action := range _featureActions {
+			if action.key == r {
+				d.runFeatureAction(action)
+				return nil
+			}
+		}
+		return event
+	}
+
+	return nil
+}
+
+func (d *dashboard) focusNext() {
+	switch d.app.GetFocus() {
+	case d.projectList:
+		d.app.SetFocus(d.featureList)
+	case d.featureList:
+		d.app.SetFocus(d.detailTable)
+	default:
+		d.app.SetFocus(d.projectList)
+	}
+}
+
+// reload reloads projects, features and detail of the selected feature from local data,
+// selections are kept if possible.
+func (d *dashboard) reload() error {
+	projects, err := d.wb.Dash.ProjectList()
+	if err != nil {
+		return err
+	}
+	features, err := d.wb.Dash.FeatureList()
+	if err != nil {
+		return err
+	}
+
+	d.currentProjectID = features.Project.ID
+	d.projects = projects.Projects
+	d.projectList.Clear()
+	for idx, p := range d.projects {
+		text := tview.Escape(p.Name)
+		d.projectList.AddItem(text, "", 0, nil)
+		if p.ID == d.currentProjectID {
+			d.projectList.SetItemText(idx, "[green]"+text+"[-]", "")
+			d.projectList.SetCurrentItem(idx)
+		}
+	}
+
+	selected := d.featureList.GetCurrentItem()
+	d.features = features.Features
+	d.featureList.Clear()
+	for _, f := range d.features {
+		main, secondary := tview.Escape(f.Milestone.Title), tview.Escape(textOrDash(f.FeatureBranch))
+		if f.Closed {
+			main = "[gray]" + main + " (closed)[-]"
+		}
+		d.featureList.AddItem(main, secondary, 0, nil)
+	}
+	if selected < len(d.features) {
+		d.featureList.SetCurrentItem(selected)
+	}
+	d.loadDetail(d.featureList.GetCurrentItem())
+
+	return nil
+}
+
+var _detailTblHeader = []string{"Type", "IID", "Title", "Branch", "Target", "State"}
+
+// loadDetail loads issues and merge requests of the feature at idx into detail table.
+func (d *dashboard) loadDetail(idx int) {
+	d.rows = nil
+	d.detailTable.Clear()
+	for col, h := range _detailTblHeader {
+		d.detailTable.SetCell(0, col,
+			tview.NewTableCell(h).SetTextColor(tcell.ColorYellow).SetSelectable(false))
+	}
+
+	feature := d.selectedFeature(idx)
+	if feature == nil {
+		return
+	}
+	if feature.FeatureBranch == "" {
+		d.setStatus("[yellow]feature branch of milestone(%s) is unknown, sync the milestone first",
+			tview.Escape(feature.Milestone.Title))
+		return
+	}
+
+	detail, err := d.wb.Dash.FeatureDetail(feature.FeatureBranch)
+	if err != nil {
+		d.report(err, "")
+		return
+	}
+
+	d.rows = detailRows(detail)
+	for idx, row := range d.rows {
+		for col, text := range row.cells() {
+			cell := tview.NewTableCell(tview.Escape(text))
+			if row.Target == types.MasterBranch.String() {
+				cell.SetTextColor(tcell.ColorRed)
+			}
+			d.detailTable.SetCell(idx+1, col, cell)
+		}
+	}
+	d.detailTable.Select(1, 0)
+	d.detailTable.ScrollToBeginning()
+}
+
+func (d *dashboard) selectedFeature(idx int) *internal.FeatureItemView {
+	if idx < 0 || idx >= len(d.features) {
+		return nil
+	}
+
+	return d.features[idx]
+}
+
+func (d *dashboard) selectedRow() *detailRow {
+	row, _ := d.detailTable.GetSelection()
+	if row < 1 || row > len(d.rows) {
+		return nil
+	}
+
+	return d.rows[row-1]
+}
+
+// switchProject opens the project at idx, and reloads the dashboard with its workbench.
+func (d *dashboard) switchProject(idx int) {
+	if idx < 0 || idx >= len(d.projects) {
+		return
+	}
+
+	project := d.projects[idx]
+	if project.ID != d.currentProjectID {
+		if project.LocalDir == "" {
+			d.report(errors.Errorf("local directory of project(%s) is unknown", project.Name), "")
+			return
+		}
+
+		wb, err := d.open(project)
+		if err != nil {
+			d.report(err, "")
+			return
+		}
+		d.wb = wb
+		d.featureList.SetCurrentItem(0)
+	}
+
+	d.report(d.reload(), "project "+project.Name+" opened")
+	d.app.SetFocus(d.featureList)
+}
+
+// openBrowser opens web URL of the focused item.
+func (d *dashboard) openBrowser() {
+	var url string
+	switch d.app.GetFocus() {
+	case d.projectList:
+		if idx := d.projectList.GetCurrentItem(); idx < len(d.projects) {
+			url = d.projects[idx].WebURL
+		}
+	case d.featureList:
+		if f := d.selectedFeature(d.featureList.GetCurrentItem()); f != nil {
+			url = f.Milestone.WebURL
+		}
+	case d.detailTable:
+		if row := d.selectedRow(); row != nil {
+			url = row.WebURL
+		}
+	}
+
+	if url == "" {
+		d.report(errors.New("no web URL of the selected item"), "")
+		return
+	}
+
+	d.report(pkg.OpenBrowser(url), "opened "+url)
+}
+
+// checkout checks out the feature branch or the branch of selected issue or merge request.
+func (d *dashboard) checkout() {
+	var branch string
+	switch d.app.GetFocus() {
+	case d.featureList:
+		if f := d.selectedFeature(d.featureList.GetCurrentItem()); f != nil {
+			branch = f.FeatureBranch
+		}
+	case d.detailTable:
+		if row := d.selectedRow(); row != nil {
+			branch = row.Branch
+		}
+	}
+
+	if branch == "" {
+		d.report(errors.New("no branch of the selected item"), "")
+		return
+	}
+
+	_ = d.runSuspended("checkout "+branch, func() error {
+		return d.wb.Git.Checkout(branch, false)
+	})
+}
+
+// runFeatureAction runs action with the selected feature after confirmation.
+func (d *dashboard) runFeatureAction(action featureAction) {
+	feature := d.selectedFeature(d.featureList.GetCurrentItem())
+	if feature == nil || feature.FeatureBranch == "" {
+		d.report(errors.New("no feature branch is selected"), "")
+		return
+	}
+
+	title := fmt.Sprintf("feature %s (%s)", action.name, feature.FeatureBranch)
+	d.confirm(title+"?", func() {
+		err := d.runSuspended(title, func() error {
+			flow, err := d.wb.Flow()
+			if err != nil {
+				return err
+			}
+			return action.fn(flow, &types.OpFeatureContext{FeatureBranchName: feature.FeatureBranch})
+		})
+		if err == nil {
+			d.report(d.reload(), title+" done")
+		}
+	})
+}
+
+// syncMilestone synchronizes the selected milestone from gitlab, then reloads local data.
+func (d *dashboard) syncMilestone() {
+	feature := d.selectedFeature(d.featureList.GetCurrentItem())
+	if feature == nil {
+		d.report(errors.New("no milestone is selected"), "")
+		return
+	}
+
+	title := "sync milestone " + feature.Milestone.Title
+	err := d.runSuspended(title, func() error {
+		flow, err := d.wb.Flow()
+		if err != nil {
+			return err
+		}
+		return flow.SyncMilestone(feature.Milestone.ID, feature.GroupID != 0, false)
+	})
+	if err == nil {
+		d.report(d.reload(), title+" done")
+	}
+}
+
+// confirm shows a modal with msg, fn is called if user confirms.
+func (d *dashboard) confirm(msg string, fn func()) {
+	focused := d.app.GetFocus()
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"Yes", "No"}).
+		SetDoneFunc(func(_ int, label string) {
+			d.pages.RemovePage(_confirmPage)
+			d.app.SetFocus(focused)
+			if label == "Yes" {
+				fn()
+			}
+		})
+
+	d.pages.AddPage(_confirmPage, modal, false, true)
+}
+
+// runSuspended runs fn with the terminal restored, so that outputs and prompts of fn are visible.
+// It waits for enter before going back to the dashboard, and the result is shown in status bar.
+func (d *dashboard) runSuspended(title string, fn func() error) error {
+	var err error
+	d.app.Suspend(func() {
+		fmt.Printf("\n>>> %s\n\n", title)
+		log.SetLogLevel(d.logLevel)
+		err = recoverCall(fn)
+		log.SetLogLevel(log.LevelFatal)
+		if err != nil {
+			fmt.Printf("\n❌ %v\n", err)
+		} else {
+			fmt.Printf("\n✅ done\n")
+		}
+		fmt.Print("press enter to go back to dashboard...")
+		_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+	})
+
+	d.report(err, title+" done")
+	return err
+}
+
+// recoverCall calls fn and converts panic into error, flow panics if access token
+// could not be renewed, it should not crash the dashboard.
+func recoverCall(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%v", r)
+		}
+	}()
+
+	return fn()
+}
+
+// report shows err in status bar, or msg if err is nil.
+func (d *dashboard) report(err error, msg string) {
+	if err != nil {
+		log.Debugf("dashboard: %v", err)
+		d.setStatus("[red]%s", tview.Escape(err.Error()))
+		return
+	}
+
+	d.setStatus("[green]%s", tview.Escape(msg))
+}
+
+func (d *dashboard) setStatus(format string, args ...interface{}) {
+	d.status.SetText(fmt.Sprintf(format, args...))
+}
+
+// detailRow is an issue or a merge request of feature in detail table, Branch is the issue branch
+// or the source branch of merge request, which could be checked out.
+type detailRow struct {
+	Kind   string
+	IID    int
+	Title  string
+	Branch string
+	Target string
+	State  string
+	WebURL string
+}
+
+func (r *detailRow) cells() []string {
+	return []string{r.Kind, strconv.Itoa(r.IID), r.Title, textOrDash(r.Branch), textOrDash(r.Target), textOrDash(r.State)}
+}
+
+// detailRows converts feature detail into rows, issues are listed first, then merge requests.
+func detailRows(v *internal.FeatureDetailView) []*detailRow {
+	issueTitles := make(map[int]string, len(v.Issues))
+	rows := make([]*detailRow, 0, len(v.Issues)+len(v.MergeRequests))
+	for _, issue := range v.Issues {
+		issueTitles[issue.IID] = issue.Title
+		rows = append(rows, &detailRow{
+			Kind:   "issue",
+			IID:    issue.IID,
+			Title:  issue.Title,
+			Branch: issue.Branch,
+			WebURL: issue.WebURL,
+		})
+	}
+
+	for _, mr := range v.MergeRequests {
+		title := issueTitles[mr.IssueIID]
+		if title == "" {
+			title = mr.SourceBranch + " => " + mr.TargetBranch
+		}
+		rows = append(rows, &detailRow{
+			Kind:   "mr",
+			IID:    mr.IID,
+			Title:  title,
+			Branch: mr.SourceBranch,
+			Target: mr.TargetBranch,
+			State:  mr.State,
+			WebURL: mr.WebURL,
+		})
+	}
+
+	return rows
+}
+
+func textOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
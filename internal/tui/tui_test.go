@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yeqown/gitlab-flow/internal"
+)
+
+func Test_detailRows(t *testing.T) {
+	v := &internal.FeatureDetailView{
+		Issues: []*internal.IssueView{
+			{IID: 1, Title: "login page", Branch: "issue/1-login-page", WebURL: "https://a.com/issues/1"},
+		},
+		MergeRequests: []*internal.MergeRequestView{
+			{IID: 3, SourceBranch: "issue/1-login-page", TargetBranch: "feature/login", State: "merged", IssueIID: 1},
+			{IID: 4, SourceBranch: "feature/login", TargetBranch: "master"},
+		},
+	}
+
+	rows := detailRows(v)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, []string{"issue", "1", "login page", "issue/1-login-page", "-", "-"}, rows[0].cells())
+	assert.Equal(t, []string{"mr", "3", "login page", "issue/1-login-page", "feature/login", "merged"}, rows[1].cells())
+	assert.Equal(t, []string{"mr", "4", "feature/login => master", "feature/login", "master", "-"}, rows[2].cells())
+}
@@ -50,6 +50,124 @@ type BranchSetting struct {
 	HotfixBranchPrefix          string `toml:"hotfix_branch_prefix"`
 	ConflictResolveBranchPrefix string `toml:"conflict_resolve_branch_prefix"`
 	IssueBranchPrefix           string `toml:"issue_branch_prefix"`
+
+	// ReleaseBranchPrefix and ReleaseBase are optional, since they are only used by
+	// release flow. ReleaseBase is the branch which release branch checkout from.
+	ReleaseBranchPrefix string    `toml:"release_branch_prefix,omitempty"`
+	ReleaseBase         BranchTyp `toml:"release_base,omitempty"`
+}
+
+// MergeSetting controls how merge request is merged while --auto-merge is set, merge request
+// is always merged after its pipeline succeeded.
+type MergeSetting struct {
+	// Squash squashes commits of merge request into one commit while merging.
+	Squash bool `toml:"squash"`
+	// RemoveSourceBranch removes source branch after merged, it only works for issue branches
+	// and branches merged into master, since feature branch is still used by other merge requests.
+	RemoveSourceBranch bool `toml:"remove_source_branch"`
+}
+
+// FlowStep is the step of flow which creates issues or merge requests.
+type FlowStep string
+
+const (
+	// FlowStepIssue creates issues of feature and merge requests from issue branch into feature branch.
+	FlowStepIssue FlowStep = "issue"
+	// FlowStepDebug creates merge requests into DevBranch.
+	FlowStepDebug FlowStep = "debug"
+	// FlowStepTest creates merge requests into TestBranch.
+	FlowStepTest FlowStep = "test"
+	// FlowStepRelease creates merge requests into MasterBranch.
+	FlowStepRelease FlowStep = "release"
+	// FlowStepHotfix creates issues and merge requests of hotfix branch.
+	FlowStepHotfix FlowStep = "hotfix"
+)
+
+// MeUsername stands for the current user of gitlab in assignees and reviewers.
+const MeUsername = "@me"
+
+// Participants are default assignees, reviewers and labels of created issues and merge requests,
+// assignees and reviewers are gitlab usernames or MeUsername. Reviewers are ignored by issues.
+type Participants struct {
+	Assignees []string `toml:"assignees,omitempty"`
+	Reviewers []string `toml:"reviewers,omitempty"`
+	Labels    []string `toml:"labels,omitempty"`
+}
+
+// Override returns a copy of p whose fields are replaced by non-empty fields of o.
+func (p *Participants) Override(o *Participants) *Participants {
+	copied := Participants{}
+	if p != nil {
+		copied = *p
+	}
+	if o == nil {
+		return &copied
+	}
+
+	if len(o.Assignees) != 0 {
+		copied.Assignees = o.Assignees
+	}
+	if len(o.Reviewers) != 0 {
+		copied.Reviewers = o.Reviewers
+	}
+	if len(o.Labels) != 0 {
+		copied.Labels = o.Labels
+	}
+
+	return &copied
+}
+
+// ParticipantSetting contains Participants of each flow step.
+type ParticipantSetting struct {
+	Issue   *Participants `toml:"issue,omitempty"`
+	Debug   *Participants `toml:"debug,omitempty"`
+	Test    *Participants `toml:"test,omitempty"`
+	Release *Participants `toml:"release,omitempty"`
+	Hotfix  *Participants `toml:"hotfix,omitempty"`
+}
+
+// Of returns Participants of step, nil means there is no default participants.
+func (s *ParticipantSetting) Of(step FlowStep) *Participants {
+	if s == nil {
+		return nil
+	}
+
+	switch step {
+	case FlowStepIssue:
+		return s.Issue
+	case FlowStepDebug:
+		return s.Debug
+	case FlowStepTest:
+		return s.Test
+	case FlowStepRelease:
+		return s.Release
+	case FlowStepHotfix:
+		return s.Hotfix
+	}
+
+	return nil
+}
+
+// Override returns a copy of s whose participants of all steps are overridden by o,
+// it's used to apply participants specified by command line flags.
+func (s *ParticipantSetting) Override(o *Participants) *ParticipantSetting {
+	return &ParticipantSetting{
+		Issue:   s.Of(FlowStepIssue).Override(o),
+		Debug:   s.Of(FlowStepDebug).Override(o),
+		Test:    s.Of(FlowStepTest).Override(o),
+		Release: s.Of(FlowStepRelease).Override(o),
+		Hotfix:  s.Of(FlowStepHotfix).Override(o),
+	}
+}
+
+// TemplateSetting contains text/template of titles and descriptions of created issues and
+// merge requests, empty template means using .gitlab/issue_templates or .gitlab/merge_request_templates
+// of the repository, or the default one of gitlab-flow.
+type TemplateSetting struct {
+	IssueTitle        string `toml:"issue_title,omitempty"`
+	IssueDesc         string `toml:"issue_desc,omitempty"`
+	MergeRequestTitle string `toml:"merge_request_title,omitempty"`
+	MergeRequestDesc  string `toml:"merge_request_desc,omitempty"`
 }
 
 var (
@@ -65,6 +183,10 @@ const (
 	ConfigType_Project ConfigType = "project"
 )
 
+// EnvPrefix is the prefix of environment variables which are read by gitlab-flow,
+// e.g. GITLAB_FLOW_ACCESS_TOKEN.
+const EnvPrefix = "GITLAB_FLOW"
+
 type ConfigHolder interface {
 	Type() ConfigType
 
@@ -81,6 +203,25 @@ type Config struct {
 	GitlabHost   string         `toml:"gitlab_host"`
 	DebugMode    bool           `toml:"debug"`
 	OpenBrowser  bool           `toml:"open_browser"`
+	// Semver enables semantic version mode, milestone titles would be validated as
+	// semantic version, and the next version could be suggested from remote tags and milestones.
+	Semver bool `toml:"semver"`
+	// Merge is optional, default is merging without squash and keeping source branch.
+	Merge *MergeSetting `toml:"merge,omitempty"`
+	// GitBackend is the backend of local git operations, "cmd" (default) executes `git`
+	// executable file, and "go-git" uses go-git instead.
+	GitBackend string `toml:"git_backend,omitempty"`
+	// GitRemote is the name of remote which branches are pushed to and pulled from,
+	// default is "origin".
+	GitRemote string `toml:"git_remote,omitempty"`
+	// AutoStash stashes local changes before flow steps which switch branches or merge,
+	// and restores them after that, otherwise these steps are refused.
+	AutoStash bool `toml:"auto_stash,omitempty"`
+	// Participants could only be configured in project configuration, since people and
+	// labels are different among projects.
+	Participants *ParticipantSetting `toml:"-"`
+	// Templates could only be configured in project configuration too.
+	Templates *TemplateSetting `toml:"-"`
 }
 
 func (c *Config) Type() ConfigType {
@@ -131,6 +272,15 @@ type ProjectConfig struct {
 	Branch      *BranchSetting `toml:"branch,omitempty"`
 	DebugMode   *bool          `toml:"debug,omitempty"`
 	OpenBrowser *bool          `toml:"open_browser,omitempty"`
+	Semver      *bool          `toml:"semver,omitempty"`
+	Merge       *MergeSetting  `toml:"merge,omitempty"`
+	GitBackend  string         `toml:"git_backend,omitempty"`
+	GitRemote   string         `toml:"git_remote,omitempty"`
+	AutoStash   *bool          `toml:"auto_stash,omitempty"`
+	// Participants are default assignees, reviewers and labels of each flow step.
+	Participants *ParticipantSetting `toml:"participants,omitempty"`
+	// Templates are templates of titles and descriptions of issues and merge requests.
+	Templates *TemplateSetting `toml:"templates,omitempty"`
 }
 
 func (c *ProjectConfig) Type() ConfigType {
@@ -0,0 +1,19 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParticipantSetting_Override(t *testing.T) {
+	setting := &ParticipantSetting{
+		Issue: &Participants{Assignees: []string{MeUsername}, Labels: []string{"feature"}},
+	}
+	overridden := setting.Override(&Participants{Assignees: []string{"alice"}})
+	assert.Equal(t, &Participants{Assignees: []string{"alice"}, Labels: []string{"feature"}},
+		overridden.Of(FlowStepIssue))
+	assert.Equal(t, &Participants{Assignees: []string{"alice"}}, overridden.Of(FlowStepTest))
+	assert.Equal(t, []string{MeUsername}, setting.Issue.Assignees)
+	assert.Nil(t, (*ParticipantSetting)(nil).Of(FlowStepIssue))
+}
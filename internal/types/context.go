@@ -30,19 +30,23 @@ type FlowContext struct {
 	// debug indicates whether gitlab-flow prints more detail logs.
 	debug       bool
 	openBrowser bool
+	// nonInteractive indicates gitlab-flow should never prompt, prompts are answered by
+	// flags or environment variables, or fail.
+	nonInteractive bool
 }
 
 // NewContext be generated with non-project information.
 // Do not use Project directly!!!
-func NewContext(cwd, projectName string, c *Config, forceRemote bool) *FlowContext {
+func NewContext(cwd, projectName string, c *Config, forceRemote, nonInteractive bool) *FlowContext {
 	ctx := &FlowContext{
-		mergedConfig: c,
-		cwd:          cwd, // set later by applyProjectName
-		project:      nil, // set later by InjectProject
-		projectName:  "",  // set later by applyProjectName
-		forceRemote:  forceRemote,
-		debug:        c.DebugMode,
-		openBrowser:  c.OpenBrowser,
+		mergedConfig:   c,
+		cwd:            cwd, // set later by applyProjectName
+		project:        nil, // set later by InjectProject
+		projectName:    "",  // set later by applyProjectName
+		forceRemote:    forceRemote,
+		debug:          c.DebugMode,
+		openBrowser:    c.OpenBrowser,
+		nonInteractive: nonInteractive,
 	}
 
 	ctx.applyProjectName(projectName)
@@ -69,6 +73,16 @@ func (c *FlowContext) InjectProject(p *ProjectBasics) {
 	c.project = p
 }
 
+// WithProject returns a copy of context which operates project p located in localDir.
+func (c *FlowContext) WithProject(p *ProjectBasics, localDir string) *FlowContext {
+	copied := *c
+	copied.project = p
+	copied.projectName = p.Name
+	copied.cwd = localDir
+
+	return &copied
+}
+
 func (c *FlowContext) Project() *ProjectBasics {
 	if c == nil {
 		return &ProjectBasics{}
@@ -121,6 +135,25 @@ func (c *FlowContext) ShouldOpenBrowser() bool {
 	return c.openBrowser
 }
 
+// DisableOpenBrowser disables opening web browser automatically, it's useful while data are
+// served to web browsers rather than printed.
+func (c *FlowContext) DisableOpenBrowser() {
+	if c == nil {
+		return
+	}
+
+	c.openBrowser = false
+}
+
+// NonInteractive returns true if gitlab-flow should never prompt.
+func (c *FlowContext) NonInteractive() bool {
+	if c == nil {
+		return false
+	}
+
+	return c.nonInteractive
+}
+
 func (c *FlowContext) APIEndpoint() string {
 	if c == nil || c.mergedConfig == nil {
 		return ""
@@ -155,10 +188,66 @@ type OpFeatureContext struct {
 
 	// ParseIssueCompatible if this is true, means parse issueName to feature in compatible way.
 	ParseIssueCompatible bool
+
+	// ReleaseTag if this is not empty, means a tag and gitlab release would be created after
+	// the merge request into master has been merged.
+	ReleaseTag string
+
+	// Bump if this is not empty, means the next semantic version would be suggested as the
+	// milestone title, it's one of (major, minor, patch).
+	Bump string
+
+	// GroupMilestone if this is true, means the milestone would be created in the group
+	// which the project belongs to, so that it could be shared by projects of the group.
+	GroupMilestone bool
+
+	// IgnorePipeline if this is true, means feature release only warns rather than blocks
+	// when the latest pipeline of feature branch has failed.
+	IgnorePipeline bool
+
+	// Draft if this is true, means a draft merge request from issue branch into feature branch
+	// would be opened while the issue is opened, and it would be marked as ready while closing the issue.
+	Draft bool
+
+	// Rebase if this is true, means the branch is updated from its base by rebasing rather than merging.
+	Rebase bool
+
+	// ResolveConflict if this is true, means conflicts predicted before opening the merge request
+	// would be resolved in a conflict-resolve branch without asking, even in non-interactive mode.
+	ResolveConflict bool
 }
 
+// UpdateAction is the action of updating branch from its base.
+type UpdateAction string
+
+const (
+	// UpdateActionStart fetches and then rebases or merges the base into the branch.
+	UpdateActionStart UpdateAction = "start"
+	// UpdateActionContinue continues the update which is stopped by conflicts.
+	UpdateActionContinue UpdateAction = "continue"
+	// UpdateActionAbort aborts the update which is stopped by conflicts.
+	UpdateActionAbort UpdateAction = "abort"
+)
+
 type OpHotfixContext struct {
 	// ForceCreateMergeRequest if this is true, means merge request would be create no matter whether
 	// merge request has been created or merged.
 	ForceCreateMergeRequest bool
+	// Backport if this is true, means merge requests from hotfix branch into DevBranch and TestBranch
+	// would be opened too, so that the fix would not be lost in the next release.
+	Backport bool
+	// ReleaseTag if this is not empty, means a tag and gitlab release would be created after
+	// the merge request into master has been merged.
+	ReleaseTag string
+}
+
+// OpReleaseContext contains all parameters of releases' operations in common.
+type OpReleaseContext struct {
+	// ForceCreateMergeRequest if this is true, means merge request would be create no matter whether
+	// merge request has been created or merged.
+	ForceCreateMergeRequest bool
+	// ReleaseBranchName specify which release branch to use, current branch would be used if it's empty.
+	ReleaseBranchName string
+	// AutoMergeRequest if this is true, means merge request would be merged automatically.
+	AutoMergeRequest bool
 }
@@ -12,10 +12,14 @@ var (
 	MasterBranch BranchTyp = "master"
 	DevBranch    BranchTyp = "develop"
 	TestBranch   BranchTyp = "test"
+
+	// ReleaseBaseBranch is the branch which release branch checkout from, it is
+	// TestBranch as default.
+	ReleaseBaseBranch = TestBranch
 )
 
 // SetBranchSetting reset builtin branch enums manually.
-func SetBranchSetting(master, dev, test BranchTyp) {
+func SetBranchSetting(master, dev, test, releaseBase BranchTyp) {
 	if master != "" {
 		MasterBranch = master
 	}
@@ -25,6 +29,11 @@ func SetBranchSetting(master, dev, test BranchTyp) {
 	if test != "" {
 		TestBranch = test
 	}
+
+	ReleaseBaseBranch = TestBranch
+	if releaseBase != "" {
+		ReleaseBaseBranch = releaseBase
+	}
 }
 
 var (
@@ -32,9 +41,10 @@ var (
 	HotfixBranchPrefix          = "hotfix/"
 	ConflictResolveBranchPrefix = "conflict-resolve/"
 	IssueBranchPrefix           = "issue/"
+	ReleaseBranchPrefix         = "release/"
 )
 
-func SetBranchPrefix(feature, hotfix, conflictResolve, issue string) {
+func SetBranchPrefix(feature, hotfix, conflictResolve, issue, release string) {
 	if feature != "" {
 		FeatureBranchPrefix = feature
 	}
@@ -47,4 +57,7 @@ func SetBranchPrefix(feature, hotfix, conflictResolve, issue string) {
 	if issue != "" {
 		IssueBranchPrefix = issue
 	}
+	if release != "" {
+		ReleaseBranchPrefix = release
+	}
 }
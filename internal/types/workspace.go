@@ -0,0 +1,30 @@
+package types
+
+// Workspace lists projects which run the same feature together, the milestone and
+// feature branch would be created in every project with the same name.
+type Workspace struct {
+	Projects []*WorkspaceProject `toml:"projects"`
+}
+
+// WorkspaceProject is a project in workspace. Path is the local directory of project's
+// repository, relative path is based on the current working directory. Name is the
+// project name on gitlab, it could be empty if it's same as the directory name.
+type WorkspaceProject struct {
+	Name string `toml:"name"`
+	Path string `toml:"path"`
+}
+
+// Lookup returns the project whose name equals to name, nil if not found.
+func (w *Workspace) Lookup(name string) *WorkspaceProject {
+	if w == nil {
+		return nil
+	}
+
+	for _, p := range w.Projects {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,358 @@
+// Package web serves dash data of local database as HTML pages and JSON API, so that
+// in-flight features across projects could be viewed in web browser.
+package web
+
+import (
+	"context"
+	"embed"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yeqown/log"
+
+	"github.com/yeqown/gitlab-flow/internal"
+	"github.com/yeqown/gitlab-flow/internal/render"
+)
+
+var (
+	//go:embed templates/page.html.tmpl
+	pageTmpl embed.FS
+)
+
+// page is the data of a page, View is rendered as sections in HTML page, or encoded into JSON by API.
+type page struct {
+	Title string
+	View  interface{}
+	Nav   []*navGroup
+}
+
+// navGroup is a group of navigation links to other pages.
+type navGroup struct {
+	Title string
+	URL   string
+	Links []*navLink
+}
+
+type navLink struct {
+	Title string
+	URL   string
+	Desc  string
+}
+
+// httpError is an error with http status code.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e httpError) Error() string {
+	return e.err.Error()
+}
+
+// loader loads page of the request.
+type loader func(r *http.Request) (*page, error)
+
+type server struct {
+	dash      internal.IDash
+	statsDays int
+	tmpl      *template.Template
+	mux       *http.ServeMux
+}
+
+// NewHandler creates http handler which serves pages and JSON API of dash, statsDays is the default
+// time window of project statistics. Pages are:
+//
+//	/                                   in-flight features of all projects.
+//	/projects/{id}                      project detail and all features of project.
+//	/projects/{id}/features/{branch}    feature detail.
+//	/milestones/{title}                 merge requests of milestone in all projects.
+//
+// JSON API of page is the same path with /api prefix, e.g. /api/projects/{id}.
+func NewHandler(dash internal.IDash, statsDays int) (http.Handler, error) {
+	tmpl, err := template.New("page.html.tmpl").
+		Funcs(template.FuncMap{"cell": cell}).
+		ParseFS(pageTmpl, "templates/page.html.tmpl")
+	if err != nil {
+		return nil, errors.Wrap(err, "parse page template")
+	}
+
+	s := &server{
+		dash:      dash,
+		statsDays: statsDays,
+		tmpl:      tmpl,
+		mux:       http.NewServeMux(),
+	}
+
+	s.handle("/{$}", s.inFlight)
+	s.handle("/projects/{id}", s.project)
+	s.handle("/projects/{id}/features/{branch...}", s.feature)
+	s.handle("/milestones/{title}", s.milestone)
+
+	return s.mux, nil
+}
+
+// Serve serves handler on addr until ctx is done.
+func Serve(ctx context.Context, addr string, handler http.Handler) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "listen on %s", addr)
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err = srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "serve")
+	}
+
+	return nil
+}
+
+func (s *server) handle(pattern string, load loader) {
+	s.mux.HandleFunc("GET "+pattern, s.renderHTML(load))
+	s.mux.HandleFunc("GET /api"+pattern, s.renderJSON(load))
+}
+
+func (s *server) renderHTML(load loader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := load(r)
+		data := struct {
+			Title    string
+			APIPath  string
+			Error    string
+			Nav      []*navGroup
+			Sections []*render.Section
+			Now      string
+		}{
+			APIPath: "/api" + r.URL.EscapedPath(),
+			Now:     time.Now().Format(time.RFC1123),
+		}
+
+		status := http.StatusOK
+		if err != nil {
+			status = statusOf(err)
+			data.Title = http.StatusText(status)
+			data.Error = err.Error()
+		} else {
+			data.Title = p.Title
+			data.Nav = p.Nav
+			if v, ok := p.View.(render.Tabular); ok {
+				data.Sections = v.Sections()
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		if err = s.tmpl.Execute(w, data); err != nil {
+			log.Errorf("web render page(%s) failed: %v", r.URL.Path, err)
+		}
+	}
+}
+
+func (s *server) renderJSON(load loader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var (
+			v      interface{}
+			status = http.StatusOK
+		)
+
+		p, err := load(r)
+		if err != nil {
+			status = statusOf(err)
+			v = map[string]string{"error": err.Error()}
+		} else {
+			v = p.View
+		}
+
+		data, err := render.Render(render.FormatJSON, v)
+		if err != nil {
+			log.Errorf("web render json(%s) failed: %v", r.URL.Path, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write(data)
+	}
+}
+
+func statusOf(err error) int {
+	var he httpError
+	if errors.As(err, &he) {
+		return he.status
+	}
+
+	return http.StatusInternalServerError
+}
+
+func (s *server) inFlight(_ *http.Request) (*page, error) {
+	view, err := s.dash.InFlight()
+	if err != nil {
+		return nil, err
+	}
+
+	p := &page{Title: "In-flight Features", View: view, Nav: make([]*navGroup, 0, len(view.Projects))}
+	for _, project := range view.Projects {
+		group := &navGroup{Title: project.Project.Name, URL: projectPath(project.Project.ID)}
+		for _, f := range project.Features {
+			group.Links = append(group.Links, featureLink(project.Project.ID, f))
+		}
+		p.Nav = append(p.Nav, group)
+	}
+
+	return p, nil
+}
+
+// projectPage is project detail and all features of project.
+type projectPage struct {
+	Detail   *internal.ProjectDetailView `json:"detail"`
+	Features *internal.FeatureListView   `json:"features"`
+}
+
+func (v *projectPage) Sections() []*render.Section {
+	return append(v.Detail.Sections(), v.Features.Sections()[1:]...)
+}
+
+// project loads project detail, statistics are loaded from gitlab only if stats=true,
+// and days overrides the default time window of statistics.
+func (s *server) project(r *http.Request) (*page, error) {
+	dash, err := s.projectDash(r)
+	if err != nil {
+		return nil, err
+	}
+
+	module, days := "links", s.statsDays
+	if stats, _ := strconv.ParseBool(r.URL.Query().Get("stats")); stats {
+		module = "all"
+	}
+	if v := r.URL.Query().Get("days"); v != "" {
+		if days, err = strconv.Atoi(v); err != nil || days <= 0 {
+			return nil, httpError{status: http.StatusBadRequest, err: errors.Errorf("invalid days: %s", v)}
+		}
+	}
+
+	detail, err := dash.ProjectDetail(module, days)
+	if err != nil {
+		return nil, err
+	}
+	features, err := dash.FeatureList()
+	if err != nil {
+		return nil, err
+	}
+
+	group := &navGroup{Title: "Features"}
+	for _, f := range features.Features {
+		group.Links = append(group.Links, featureLink(detail.Project.ID, f))
+	}
+	nav := []*navGroup{group}
+	if module != "all" {
+		nav = append(nav, &navGroup{
+			Title: "Statistics",
+			Links: []*navLink{{Title: "load statistics from gitlab", URL: projectPath(detail.Project.ID) + "?stats=true"}},
+		})
+	}
+
+	return &page{
+		Title: detail.Project.Name,
+		View:  &projectPage{Detail: detail, Features: features},
+		Nav:   nav,
+	}, nil
+}
+
+func (s *server) feature(r *http.Request) (*page, error) {
+	dash, err := s.projectDash(r)
+	if err != nil {
+		return nil, err
+	}
+
+	branch := r.PathValue("branch")
+	view, err := dash.FeatureDetail(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &page{
+		Title: view.Project.Name + " / " + view.FeatureBranch,
+		View:  view,
+		Nav: []*navGroup{{
+			Title: view.Milestone.Title,
+			Links: []*navLink{
+				{Title: view.Project.Name, URL: projectPath(view.Project.ID), Desc: "all features of project"},
+				{Title: view.Milestone.Title, URL: milestonePath(view.Milestone.Title), Desc: "milestone in all projects"},
+			},
+		}},
+	}, nil
+}
+
+// milestone loads merge requests of milestone in all projects, target filters merge requests
+// by target branch.
+func (s *server) milestone(r *http.Request) (*page, error) {
+	title := r.PathValue("title")
+	view, err := s.dash.MilestoneOverview(title, r.URL.Query().Get("target"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &page{Title: "Milestone " + title, View: view}, nil
+}
+
+func (s *server) projectDash(r *http.Request) (internal.IDash, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		return nil, httpError{status: http.StatusBadRequest, err: errors.Errorf("invalid project id: %s", r.PathValue("id"))}
+	}
+
+	dash, err := s.dash.ForProject(id)
+	if err != nil {
+		return nil, httpError{status: http.StatusNotFound, err: err}
+	}
+
+	return dash, nil
+}
+
+func projectPath(id int) string {
+	return "/projects/" + strconv.Itoa(id)
+}
+
+func milestonePath(title string) string {
+	return "/milestones/" + url.PathEscape(title)
+}
+
+func featureLink(projectID int, f *internal.FeatureItemView) *navLink {
+	link := &navLink{Title: f.Milestone.Title, Desc: f.FeatureBranch}
+	if f.FeatureBranch != "" {
+		link.URL = projectPath(projectID) + "/features/" + url.PathEscape(f.FeatureBranch)
+	} else {
+		link.URL = milestonePath(f.Milestone.Title)
+		link.Desc = "feature branch is unknown"
+	}
+
+	return link
+}
+
+// cell renders web URL as link, other texts are escaped by template.
+func cell(s string) interface{} {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return s
+	}
+
+	escaped := template.HTMLEscapeString(s)
+	return template.HTML(`<a href="` + escaped + `" target="_blank">` + escaped + `</a>`)
+}
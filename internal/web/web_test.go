@@ -0,0 +1,84 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yeqown/gitlab-flow/internal"
+)
+
+// fakeDash implements internal.IDash, unused methods panic.
+type fakeDash struct {
+	internal.IDash
+}
+
+func (fakeDash) InFlight() (*internal.InFlightView, error) {
+	return &internal.InFlightView{
+		Projects: []*internal.InFlightProjectView{{
+			Project: internal.ProjectView{ID: 1, Name: "flow", WebURL: "https://gitlab.com/a/flow"},
+			Features: []*internal.FeatureItemView{
+				{
+					Milestone:     internal.MilestoneView{ID: 2, Title: "login", WebURL: "https://gitlab.com/a/flow/-/milestones/2"},
+					FeatureBranch: "feature/login",
+				},
+			},
+		}},
+	}, nil
+}
+
+func (d fakeDash) ForProject(projectID int) (internal.IDash, error) {
+	if projectID != 1 {
+		return nil, errors.New("project not found")
+	}
+
+	return d, nil
+}
+
+func (fakeDash) FeatureDetail(branch string) (*internal.FeatureDetailView, error) {
+	return &internal.FeatureDetailView{
+		Project:       internal.ProjectView{ID: 1, Name: "flow"},
+		Milestone:     internal.MilestoneView{ID: 2, Title: "login"},
+		FeatureBranch: branch,
+	}, nil
+}
+
+func Test_Handler(t *testing.T) {
+	h, err := NewHandler(fakeDash{}, 30)
+	assert.NoError(t, err)
+
+	get := func(path string) (int, string) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		body, _ := io.ReadAll(rec.Body)
+		return rec.Code, string(body)
+	}
+
+	code, body := get("/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `<a href="/projects/1/features/feature%2Flogin">login</a>`)
+	assert.Contains(t, body, `<a href="https://gitlab.com/a/flow/-/milestones/2" target="_blank">`)
+
+	code, body = get("/api/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"feature_branch": "feature/login"`)
+
+	// branch name contains slash.
+	code, body = get("/api/projects/1/features/feature%2Flogin")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `"feature_branch": "feature/login"`)
+	code, body = get("/projects/1/features/feature/login")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, `/milestones/login`)
+
+	code, body = get("/api/projects/2/features/feature/login")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Contains(t, body, `"error": "project not found"`)
+
+	code, _ = get("/projects/abc")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yeqown/log"
+
+	gitop "github.com/yeqown/gitlab-flow/internal/git-operator"
+)
+
+// autoStashMessage is the prefix of messages of stashes which are saved by guardWorkingTree.
+const autoStashMessage = "gitlab-flow: auto stash before "
+
+// guardWorkingTree checks the working tree before op which switches branches or merges.
+// op is refused if any operation is in progress or HEAD is detached. Local changes of tracked
+// files are stashed if auto stash is enabled or confirmed, and the returned restore must be
+// called after op to restore them, otherwise op is refused with the files which block it.
+// If op is stopped by conflicts, local changes are kept in stash, since restoring them into
+// the conflicted working tree would mix them with the merge, resume tells how they could be
+// restored after that, empty means popping the stash manually.
+func (f flowImpl) guardWorkingTree(op, resume string) (restore func(), err error) {
+	restore = func() {}
+
+	status, err := f.gitOperator.Status()
+	if err != nil {
+		return restore, errors.Wrapf(err, "could not check working tree before %s", op)
+	}
+	if err = checkWorkingTree(status, op); err != nil || len(status.Changed) == 0 {
+		return restore, err
+	}
+
+	if !f.ctx.Config().AutoStash {
+		message := fmt.Sprintf("%d file(s) have local changes, stash them before %s and restore after that?",
+			len(status.Changed), op)
+		if !confirmInteractively(message, false, f.ctx.NonInteractive()) {
+			return restore, errLocalChanges(status, op)
+		}
+	}
+
+	if err = f.gitOperator.Stash(autoStashMessage + op); err != nil {
+		return restore, errors.Wrapf(err, "could not stash local changes before %s", op)
+	}
+	log.
+		WithFields(log.Fields{"files": status.Changed}).
+		Infof("local changes are stashed before %s", op)
+
+	if resume == "" {
+		resume = "please run `git stash pop` to restore them after it is finished"
+	}
+
+	return func() {
+		if status, err := f.gitOperator.Status(); err == nil && status.InProgress != "" {
+			log.
+				WithFields(log.Fields{"conflicted": status.Conflicted}).
+				Warnf("local changes stashed before %s are kept in stash while %s is in progress, %s",
+					op, status.InProgress, resume)
+			return
+		}
+		f.popStash(op)
+	}, nil
+}
+
+// restoreAutoStash restores local changes which are kept in stash by guardWorkingTree while the
+// operation is stopped by conflicts, the latest stash is left alone if it's not saved by guardWorkingTree.
+func (f flowImpl) restoreAutoStash() {
+	stashes, err := f.gitOperator.StashList()
+	if err != nil {
+		if !errors.Is(err, gitop.ErrNotSupported) {
+			log.
+				WithFields(log.Fields{"error": err}).
+				Warnf("could not list stashes, please run `git stash pop` manually if local changes are stashed")
+		}
+		return
+	}
+	if len(stashes) == 0 {
+		return
+	}
+
+	idx := strings.Index(stashes[0], autoStashMessage)
+	if idx < 0 {
+		return
+	}
+	f.popStash(stashes[0][idx+len(autoStashMessage):])
+}
+
+// popStash restores local changes which are stashed before op.
+func (f flowImpl) popStash(op string) {
+	if err := f.gitOperator.StashPop(); err != nil {
+		log.
+			WithFields(log.Fields{"error": err}).
+			Warnf("could not restore local changes stashed before %s, please run `git stash pop` manually", op)
+		return
+	}
+	log.Infof("local changes stashed before %s are restored", op)
+}
+
+// checkWorkingTree returns error if op could not run in the working tree whatever local changes are,
+// which means some operation is in progress or HEAD is detached.
+func checkWorkingTree(status *gitop.Status, op string) error {
+	if status.InProgress != "" {
+		if len(status.Conflicted) != 0 {
+			return errors.Errorf("could not %s: %s is in progress, and the following files are conflicted:\n%s\n"+
+				"please resolve conflicts and commit, or abort the %s first",
+				op, status.InProgress, formatFiles(status.Conflicted), status.InProgress)
+		}
+
+		return errors.Errorf("could not %s: %s is in progress, please finish or abort it first",
+			op, status.InProgress)
+	}
+
+	if status.Detached() {
+		return errors.Errorf("could not %s: HEAD is detached, please checkout a branch first", op)
+	}
+
+	return nil
+}
+
+// errLocalChanges returns error which tells the files have local changes block op.
+func errLocalChanges(status *gitop.Status, op string) error {
+	return errors.Errorf("could not %s: the following files have local changes:\n%s\n"+
+		"please commit or stash them first, or run with --auto-stash", op, formatFiles(status.Changed))
+}
+
+func formatFiles(files []string) string {
+	lines := make([]string, 0, len(files))
+	for _, file := range files {
+		lines = append(lines, "  "+file)
+	}
+
+	return strings.Join(lines, "\n")
+}
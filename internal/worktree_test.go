@@ -0,0 +1,173 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gitop "github.com/yeqown/gitlab-flow/internal/git-operator"
+	"github.com/yeqown/gitlab-flow/internal/types"
+)
+
+func Test_checkWorkingTree(t *testing.T) {
+	assert.NoError(t, checkWorkingTree(&gitop.Status{Branch: "master", Changed: []string{"a.go"}}, "checkout dev"))
+
+	err := checkWorkingTree(&gitop.Status{Branch: "HEAD"}, "checkout dev")
+	assert.EqualError(t, err, "could not checkout dev: HEAD is detached, please checkout a branch first")
+
+	err = checkWorkingTree(&gitop.Status{Branch: "master", InProgress: gitop.OperationRebase}, "checkout dev")
+	assert.EqualError(t, err, "could not checkout dev: rebase is in progress, please finish or abort it first")
+
+	err = checkWorkingTree(&gitop.Status{
+		Branch:     "master",
+		InProgress: gitop.OperationMerge,
+		Changed:    []string{"a.go", "b.go"},
+		Conflicted: []string{"b.go"},
+	}, "checkout dev")
+	assert.EqualError(t, err, "could not checkout dev: merge is in progress, and the following files are conflicted:\n"+
+		"  b.go\nplease resolve conflicts and commit, or abort the merge first")
+
+	err = errLocalChanges(&gitop.Status{Branch: "master", Changed: []string{"a.go", "b.go"}}, "checkout dev")
+	assert.EqualError(t, err, "could not checkout dev: the following files have local changes:\n"+
+		"  a.go\n  b.go\nplease commit or stash them first, or run with --auto-stash")
+}
+
+func Test_flowImpl_guardWorkingTree_stash(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git executable file is not found")
+	}
+	t.Setenv("GIT_MERGE_AUTOEDIT", "no")
+
+	cases := []struct {
+		name     string
+		feature  string
+		conflict bool
+	}{
+		{name: "merged", feature: "b.txt"},
+		{name: "conflicted", feature: "a.txt", conflict: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			dir := newTestWorkDir(t)
+			testGit(t, dir, "checkout", "-b", "feature/a")
+			testCommit(t, dir, c.feature, "feature")
+			testGit(t, dir, "checkout", "master")
+			testCommit(t, dir, "a.txt", "master")
+			writeTestFile(t, dir, "local.txt", "local changes")
+
+			f := flowImpl{
+				ctx:         types.NewContext(dir, "a", &types.Config{AutoStash: true}, false, true),
+				gitOperator: gitop.NewBasedCmd(dir, ""),
+			}
+			restore, err := f.guardWorkingTree("merge feature/a into master", "")
+			require.NoError(t, err)
+			assert.Equal(t, "init", readTestFile(t, dir, "local.txt"))
+
+			err = f.gitOperator.Merge("feature/a", "master")
+			restore()
+
+			stashes, err2 := f.gitOperator.StashList()
+			require.NoError(t, err2)
+			if !c.conflict {
+				require.NoError(t, err)
+				assert.Empty(t, stashes)
+				assert.Equal(t, "local changes", readTestFile(t, dir, "local.txt"))
+				return
+			}
+
+			// local changes are kept in stash rather than mixed with the conflicted merge.
+			require.Error(t, err)
+			assert.Equal(t, []string{"On master: " + autoStashMessage + "merge feature/a into master"}, stashes)
+			assert.Equal(t, "init", readTestFile(t, dir, "local.txt"))
+		})
+	}
+}
+
+func Test_flowImpl_updateBranch_stash(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git executable file is not found")
+	}
+	t.Setenv("GIT_MERGE_AUTOEDIT", "no")
+
+	for _, action := range []types.UpdateAction{types.UpdateActionContinue, types.UpdateActionAbort} {
+		t.Run(string(action), func(t *testing.T) {
+			dir := newTestWorkDir(t)
+			testGit(t, dir, "clone", "--bare", dir, filepath.Join(dir, ".git", "origin.git"))
+			testGit(t, dir, "remote", "add", "origin", filepath.Join(dir, ".git", "origin.git"))
+			testGit(t, dir, "checkout", "-b", "feature/a")
+			testCommit(t, dir, "a.txt", "feature")
+			testGit(t, dir, "checkout", "master")
+			testCommit(t, dir, "a.txt", "master")
+			testGit(t, dir, "push", "origin", "master")
+			testGit(t, dir, "checkout", "feature/a")
+			writeTestFile(t, dir, "local.txt", "local changes")
+
+			f := flowImpl{
+				ctx:         types.NewContext(dir, "a", &types.Config{AutoStash: true}, false, true),
+				gitOperator: gitop.NewBasedCmd(dir, ""),
+			}
+			require.Error(t, f.updateBranch("feature/a", "master", false, types.UpdateActionStart))
+			stashes, err := f.gitOperator.StashList()
+			require.NoError(t, err)
+			assert.Len(t, stashes, 1)
+			assert.Equal(t, "init", readTestFile(t, dir, "local.txt"))
+
+			if action == types.UpdateActionContinue {
+				writeTestFile(t, dir, "a.txt", "resolved")
+				testGit(t, dir, "add", "a.txt")
+			}
+			require.NoError(t, f.updateBranch("feature/a", "master", false, action))
+
+			stashes, err = f.gitOperator.StashList()
+			require.NoError(t, err)
+			assert.Empty(t, stashes)
+			assert.Equal(t, "local changes", readTestFile(t, dir, "local.txt"))
+		})
+	}
+}
+
+// newTestWorkDir creates a git repository with a.txt, b.txt and local.txt committed into master.
+func newTestWorkDir(t *testing.T) string {
+	t.Setenv("GIT_AUTHOR_NAME", "gitlab-flow")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gitlab-flow@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gitlab-flow")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gitlab-flow@example.com")
+
+	dir := t.TempDir()
+	testGit(t, dir, "init", "-b", "master")
+	for _, name := range []string{"a.txt", "b.txt", "local.txt"} {
+		writeTestFile(t, dir, name, "init")
+	}
+	testGit(t, dir, "add", ".")
+	testGit(t, dir, "commit", "-m", "init")
+
+	return dir
+}
+
+// testCommit writes content into file name and commits it into current branch.
+func testCommit(t *testing.T, dir, name, content string) {
+	writeTestFile(t, dir, name, content)
+	testGit(t, dir, "commit", "-am", content)
+}
+
+func testGit(t *testing.T, dir string, args ...string) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+func readTestFile(t *testing.T, dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// PrependFile writes data to the head of file, the file would be created if
+// it does not exist.
+func PrependFile(path string, data []byte) error {
+	return PrependFileUnder(path, nil, data)
+}
+
+// PrependFileUnder writes heading and data to the head of file like PrependFile, but heading
+// is written only if the file does not start with it, otherwise data is written right after
+// the existing heading.
+func PrependFileUnder(path string, heading, data []byte) error {
+	origin, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "read file failed")
+	}
+	origin = bytes.TrimPrefix(origin, heading)
+
+	info, err := os.Stat(path)
+	mode := os.FileMode(0644)
+	if err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	out := make([]byte, 0, len(heading)+len(data)+len(origin))
+	out = append(out, heading...)
+	out = append(out, data...)
+	out = append(out, origin...)
+	if err = os.WriteFile(path, out, mode); err != nil {
+		return errors.Wrap(err, "write file failed")
+	}
+
+	return nil
+}
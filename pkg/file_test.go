@@ -0,0 +1,32 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PrependFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+
+	assert.NoError(t, PrependFile(path, []byte("## v1\n")))
+	assert.NoError(t, PrependFile(path, []byte("## v2\n")))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "## v2\n## v1\n", string(data))
+}
+
+func Test_PrependFileUnder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	heading := []byte("# Changelog\n\n")
+
+	assert.NoError(t, PrependFileUnder(path, heading, []byte("## v1\n")))
+	assert.NoError(t, PrependFileUnder(path, heading, []byte("## v2\n")))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Changelog\n\n## v2\n## v1\n", string(data))
+}
@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// BumpPart represents which part of the semantic version would be increased.
+type BumpPart string
+
+const (
+	BumpMajor BumpPart = "major"
+	BumpMinor BumpPart = "minor"
+	BumpPatch BumpPart = "patch"
+)
+
+// ParseBumpPart parses s into BumpPart, error would be returned if s is not
+// one of (major, minor, patch).
+func ParseBumpPart(s string) (BumpPart, error) {
+	switch p := BumpPart(s); p {
+	case BumpMajor, BumpMinor, BumpPatch:
+		return p, nil
+	}
+
+	return "", errors.Errorf("invalid bump part: %s, must be one of (major, minor, patch)", s)
+}
+
+// semverRegexp matches version like: v1.2.3, 1.2.3, v1.2.3-rc.1, v1.2.3+build.
+var semverRegexp = regexp.MustCompile(`^(v?)(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// Semver is a semantic version (https://semver.org), Prefix is "v" or empty,
+// and build metadata is ignored.
+type Semver struct {
+	Prefix     string
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// ParseSemver parses s into Semver.
+func ParseSemver(s string) (*Semver, error) {
+	matched := semverRegexp.FindStringSubmatch(s)
+	if len(matched) == 0 {
+		return nil, errors.Errorf("%s is not a semantic version", s)
+	}
+
+	v := &Semver{Prefix: matched[1], Prerelease: matched[5]}
+	v.Major, _ = strconv.Atoi(matched[2])
+	v.Minor, _ = strconv.Atoi(matched[3])
+	v.Patch, _ = strconv.Atoi(matched[4])
+
+	return v, nil
+}
+
+// String formats the version, e.g. v1.2.3, v1.2.3-rc.1.
+func (v Semver) String() string {
+	s := fmt.Sprintf("%s%d.%d.%d", v.Prefix, v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+
+	return s
+}
+
+// Bump returns the next version of v, lower parts are reset and prerelease is dropped.
+// Bumping patch of a prerelease version just drops the prerelease, since v1.2.3-rc.1 < v1.2.3.
+func (v Semver) Bump(part BumpPart) Semver {
+	next := Semver{Prefix: v.Prefix, Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	switch part {
+	case BumpMajor:
+		next.Major++
+		next.Minor, next.Patch = 0, 0
+	case BumpMinor:
+		next.Minor++
+		next.Patch = 0
+	default:
+		if v.Prerelease == "" {
+			next.Patch++
+		}
+	}
+
+	return next
+}
+
+// Compare returns -1 if v < o, 0 if v == o, 1 if v > o. Prerelease versions
+// are compared in lexical order, which is enough for most cases.
+func (v Semver) Compare(o Semver) int {
+	cmp := func(a, b int) int {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+
+	if c := cmp(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := cmp(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := cmp(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	case v.Prerelease < o.Prerelease:
+		return -1
+	}
+
+	return 1
+}
+
+// LatestSemver returns the greatest semantic version among versions, those could not be
+// parsed are ignored. nil would be returned if there is no valid version.
+func LatestSemver(versions []string) *Semver {
+	var latest *Semver
+	for _, s := range versions {
+		v, err := ParseSemver(s)
+		if err != nil {
+			continue
+		}
+		if latest == nil || v.Compare(*latest) > 0 {
+			latest = v
+		}
+	}
+
+	return latest
+}
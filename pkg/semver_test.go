@@ -0,0 +1,72 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseSemver(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "v1.2.3", want: "v1.2.3"},
+		{input: "1.2.3", want: "1.2.3"},
+		{input: "v1.2.3-rc.1+build.5", want: "v1.2.3-rc.1"},
+		{input: "v1.2", wantErr: true},
+		{input: "feature-login", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := ParseSemver(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, v.String())
+		})
+	}
+}
+
+func Test_Semver_Bump(t *testing.T) {
+	tests := []struct {
+		input string
+		part  BumpPart
+		want  string
+	}{
+		{input: "v1.2.3", part: BumpMajor, want: "v2.0.0"},
+		{input: "v1.2.3", part: BumpMinor, want: "v1.3.0"},
+		{input: "v1.2.3", part: BumpPatch, want: "v1.2.4"},
+		{input: "1.2.3-rc.1", part: BumpPatch, want: "1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input+"_"+string(tt.part), func(t *testing.T) {
+			v, err := ParseSemver(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, v.Bump(tt.part).String())
+		})
+	}
+}
+
+func Test_LatestSemver(t *testing.T) {
+	latest := LatestSemver([]string{"v1.9.0", "feature-a", "v1.10.0-rc.1", "v1.10.0", "v1.2.3"})
+	if assert.NotNil(t, latest) {
+		assert.Equal(t, "v1.10.0", latest.String())
+	}
+
+	assert.Nil(t, LatestSemver([]string{"a", "b"}))
+}
+
+func Test_ParseBumpPart(t *testing.T) {
+	p, err := ParseBumpPart("minor")
+	assert.NoError(t, err)
+	assert.Equal(t, BumpMinor, p)
+
+	_, err = ParseBumpPart("huge")
+	assert.Error(t, err)
+}
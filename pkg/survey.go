@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/core"
+	"github.com/pkg/errors"
+)
+
+// AskOrLoad asks questions interactively, or loads answers from environment variables if
+// nonInteractive is true. The environment variable of a question is named by EnvName(envPrefix, q.Name),
+// and the default value of the prompt would be used if the environment variable is not set.
+func AskOrLoad(qs []*survey.Question, ans interface{}, nonInteractive bool, envPrefix string) error {
+	if !nonInteractive {
+		return survey.Ask(qs, ans)
+	}
+
+	return loadAnswers(qs, ans, envPrefix, os.LookupEnv)
+}
+
+// EnvName returns environment variable name of the question, e.g. "apiURL" => "PREFIX_API_URL".
+func EnvName(prefix, name string) string {
+	runes := []rune(name)
+	sb := strings.Builder{}
+	sb.WriteString(prefix)
+	sb.WriteByte('_')
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+
+	return sb.String()
+}
+
+// loadAnswers fills answers with values from lookup or prompt's default value, all missing or
+// invalid answers are reported together.
+func loadAnswers(
+	qs []*survey.Question, ans interface{}, envPrefix string, lookup func(string) (string, bool)) error {
+	invalid := make([]string, 0, 4)
+
+	for _, q := range qs {
+		envName := EnvName(envPrefix, q.Name)
+		env, hasEnv := lookup(envName)
+
+		var value interface{}
+		switch p := q.Prompt.(type) {
+		case *survey.Input:
+			value = p.Default
+			if hasEnv {
+				value = env
+			}
+		case *survey.Password:
+			value = env
+		case *survey.Confirm:
+			value = p.Default
+			if hasEnv {
+				b, err := strconv.ParseBool(env)
+				if err != nil {
+					invalid = append(invalid, envName+" (should be true or false)")
+					continue
+				}
+				value = b
+			}
+		case *survey.Select:
+			selected, _ := p.Default.(string)
+			if hasEnv {
+				selected = env
+			}
+			idx := indexOf(p.Options, selected)
+			if idx < 0 {
+				invalid = append(invalid, envName+" (one of "+strings.Join(p.Options, ", ")+")")
+				continue
+			}
+			value = core.OptionAnswer{Value: selected, Index: idx}
+		default:
+			return errors.Errorf("question(%s) could not be answered in non-interactive mode", q.Name)
+		}
+
+		if q.Validate != nil {
+			if err := q.Validate(value); err != nil {
+				invalid = append(invalid, envName+" ("+err.Error()+")")
+				continue
+			}
+		}
+
+		if err := core.WriteAnswer(ans, q.Name, value); err != nil {
+			return errors.Wrapf(err, "write answer of %s", q.Name)
+		}
+	}
+
+	if len(invalid) != 0 {
+		return errors.Errorf("missing or invalid answers in non-interactive mode, "+
+			"please set environment variables: %s", strings.Join(invalid, ", "))
+	}
+
+	return nil
+}
+
+func indexOf(options []string, s string) int {
+	for idx, v := range options {
+		if v == s {
+			return idx
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"testing"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EnvName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "apiURL", want: "FLOW_API_URL"},
+		{name: "appID", want: "FLOW_APP_ID"},
+		{name: "debugMode", want: "FLOW_DEBUG_MODE"},
+		{name: "featureBranchPrefix", want: "FLOW_FEATURE_BRANCH_PREFIX"},
+		{name: "URLPrefix", want: "FLOW_URL_PREFIX"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EnvName("FLOW", tt.name))
+		})
+	}
+}
+
+func Test_loadAnswers(t *testing.T) {
+	qs := []*survey.Question{
+		{Name: "apiURL", Prompt: &survey.Input{Default: "https://a.com"}, Validate: survey.Required},
+		{Name: "masterBranch", Prompt: &survey.Input{Default: "master"}, Validate: survey.Required},
+		{Name: "debugMode", Prompt: &survey.Confirm{Default: false}},
+		{Name: "oauthMode", Prompt: &survey.Select{Options: []string{"auto", "manual"}, Default: "auto"}},
+	}
+	env := map[string]string{
+		"FLOW_API_URL":    "https://gitlab.example.com",
+		"FLOW_DEBUG_MODE": "true",
+		"FLOW_OAUTH_MODE": "manual",
+	}
+	lookup := func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	}
+
+	ans := struct {
+		APIUrl       string
+		MasterBranch string
+		DebugMode    bool
+		OAuthMode    string
+	}{}
+	err := loadAnswers(qs, &ans, "FLOW", lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://gitlab.example.com", ans.APIUrl)
+	assert.Equal(t, "master", ans.MasterBranch)
+	assert.True(t, ans.DebugMode)
+	assert.Equal(t, "manual", ans.OAuthMode)
+
+	// required answer is missing and select option is invalid.
+	env = map[string]string{"FLOW_OAUTH_MODE": "unknown"}
+	qs[0].Prompt = &survey.Input{}
+	err = loadAnswers(qs, &ans, "FLOW", lookup)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "FLOW_API_URL")
+	assert.Contains(t, err.Error(), "FLOW_OAUTH_MODE (one of auto, manual)")
+}